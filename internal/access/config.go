@@ -0,0 +1,123 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// AnonymousUser names callers that did not present a bearer token
+const AnonymousUser = "anonymous"
+
+// tokenLifetime is the expiration reported to the SDK for a verified static token;
+// the token itself does not expire, it is verified again on every request
+const tokenLifetime = time.Hour
+
+// Config maps bearer tokens to principals
+type Config struct {
+	// Tokens maps a bearer token to its principal. When empty, the HTTP transports
+	// do not require authentication and every caller gets DefaultRole.
+	Tokens map[string]Principal
+	// DefaultRole applies to callers without a token: stdio clients, SSE sessions
+	// and HTTP clients when no tokens are configured
+	DefaultRole Role
+}
+
+// DefaultConfig returns a configuration without tokens where everybody is a viewer
+func DefaultConfig() *Config {
+	return &Config{
+		Tokens:      map[string]Principal{},
+		DefaultRole: RoleViewer,
+	}
+}
+
+// LoadConfigFromEnv loads access control from environment variables:
+// MCP_AUTH_TOKENS is a comma-separated list of token:user:role entries and
+// MCP_DEFAULT_ROLE is the role of callers without a token (default: viewer).
+// Malformed entries are skipped with a log message.
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	if name := os.Getenv("MCP_DEFAULT_ROLE"); name != "" {
+		if role, err := ParseRole(name); err == nil {
+			cfg.DefaultRole = role
+		} else {
+			log.Printf("Ignoring MCP_DEFAULT_ROLE: %v", err)
+		}
+	}
+
+	if tokens := os.Getenv("MCP_AUTH_TOKENS"); tokens != "" {
+		for _, entry := range strings.Split(tokens, ",") {
+			token, principal, err := parseTokenEntry(strings.TrimSpace(entry))
+			if err != nil {
+				log.Printf("Ignoring MCP_AUTH_TOKENS entry: %v", err)
+				continue
+			}
+			cfg.Tokens[token] = principal
+		}
+	}
+
+	return cfg
+}
+
+func parseTokenEntry(entry string) (string, Principal, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", Principal{}, fmt.Errorf("expected token:user:role")
+	}
+
+	role, err := ParseRole(parts[2])
+	if err != nil {
+		return "", Principal{}, fmt.Errorf("user %s: %w", parts[1], err)
+	}
+
+	return parts[0], Principal{User: parts[1], Role: role}, nil
+}
+
+// RequiresAuth reports whether HTTP callers must present a bearer token
+func (c *Config) RequiresAuth() bool {
+	return len(c.Tokens) > 0
+}
+
+// Verifier checks bearer tokens against the configured tokens and passes
+// the principal to the tool handlers through auth.TokenInfo
+func (c *Config) Verifier() auth.TokenVerifier {
+	return func(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
+		principal, ok := c.Tokens[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown token", auth.ErrInvalidToken)
+		}
+
+		return &auth.TokenInfo{
+			Scopes:     []string{string(principal.Role)},
+			Expiration: time.Now().Add(tokenLifetime),
+			Extra:      map[string]any{"user": principal.User},
+		}, nil
+	}
+}
+
+// Principal returns the caller described by the token info, or an anonymous
+// caller with the default role when there is none
+func (c *Config) Principal(info *auth.TokenInfo) Principal {
+	if info == nil || len(info.Scopes) == 0 {
+		return Principal{User: AnonymousUser, Role: c.DefaultRole}
+	}
+
+	user, _ := info.Extra["user"].(string)
+	if user == "" {
+		user = AnonymousUser
+	}
+
+	role, err := ParseRole(info.Scopes[0])
+	if err != nil {
+		return Principal{User: user, Role: c.DefaultRole}
+	}
+
+	return Principal{User: user, Role: role}
+}
@@ -0,0 +1,46 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+)
+
+func TestLoadConfigFromEnv_ParsesTokensAndSkipsMalformedEntries(t *testing.T) {
+	t.Setenv("MCP_AUTH_TOKENS", "t1:alice:admin, t2:bob:operator,broken,t3:carol:root")
+	t.Setenv("MCP_DEFAULT_ROLE", "operator")
+
+	cfg := LoadConfigFromEnv()
+
+	assert.Equal(t, RoleOperator, cfg.DefaultRole)
+	assert.True(t, cfg.RequiresAuth())
+	assert.Equal(t, map[string]Principal{
+		"t1": {User: "alice", Role: RoleAdmin},
+		"t2": {User: "bob", Role: RoleOperator},
+	}, cfg.Tokens)
+}
+
+func TestConfig_VerifiedTokenResolvesToPrincipal(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Tokens["t2"] = Principal{User: "bob", Role: RoleOperator}
+
+	info, err := cfg.Verifier()(context.Background(), "t2", nil)
+	require.NoError(t, err)
+
+	principal := cfg.Principal(info)
+	assert.Equal(t, Principal{User: "bob", Role: RoleOperator}, principal)
+	assert.True(t, principal.Can(model.ActionNameCancelBackend))
+	assert.False(t, principal.Can(model.ActionNameTerminateBackend))
+
+	_, err = cfg.Verifier()(context.Background(), "nope", nil)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	anonymous := cfg.Principal(nil)
+	assert.Equal(t, Principal{User: AnonymousUser, Role: RoleViewer}, anonymous)
+	assert.True(t, anonymous.Can(model.ActionNameActiveQueries))
+	assert.False(t, anonymous.Can(model.ActionNameCancelBackend))
+}
@@ -0,0 +1,113 @@
+package access
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// DefaultConfirmTTL is how long a confirm token stays valid
+const DefaultConfirmTTL = 2 * time.Minute
+
+var (
+	ErrConfirmTokenUnknown  = errors.New("confirm token is unknown, expired or already used")
+	ErrConfirmTokenMismatch = errors.New("confirm token was issued for a different request")
+)
+
+// Intent is what a confirm token approves: one action on one backend,
+// requested by one user
+type Intent struct {
+	Instance   string
+	Action     model.ActionName
+	PID        int
+	QueryStart time.Time
+	User       string
+}
+
+func (i Intent) matches(other Intent) bool {
+	return i.Instance == other.Instance &&
+		i.Action == other.Action &&
+		i.PID == other.PID &&
+		i.QueryStart.Equal(other.QueryStart) &&
+		i.User == other.User
+}
+
+type pendingConfirmation struct {
+	intent    Intent
+	expiresAt time.Time
+}
+
+// Confirmations implements the two-step confirmation of state-changing actions:
+// the first call gets a single-use token bound to its intent, the second call
+// must present it with the same parameters before the token expires
+type Confirmations struct {
+	ttl     time.Duration
+	pending map[string]pendingConfirmation
+	mu      sync.Mutex
+
+	now func() time.Time
+}
+
+func NewConfirmations(ttl time.Duration) *Confirmations {
+	if ttl <= 0 {
+		ttl = DefaultConfirmTTL
+	}
+
+	return &Confirmations{
+		ttl:     ttl,
+		pending: make(map[string]pendingConfirmation),
+		now:     time.Now,
+	}
+}
+
+// Issue returns a new token for the intent and its expiration time
+func (c *Confirmations) Issue(intent Intent) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.expireLocked(now)
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[token] = pendingConfirmation{intent: intent, expiresAt: expiresAt}
+
+	return token, expiresAt, nil
+}
+
+// Consume checks the token against the intent and invalidates it.
+// A token presented with different parameters is invalidated too, so it cannot be retried.
+func (c *Confirmations) Consume(token string, intent Intent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expireLocked(c.now())
+
+	pending, ok := c.pending[token]
+	if !ok {
+		return ErrConfirmTokenUnknown
+	}
+	delete(c.pending, token)
+
+	if !pending.intent.matches(intent) {
+		return ErrConfirmTokenMismatch
+	}
+	return nil
+}
+
+func (c *Confirmations) expireLocked(now time.Time) {
+	for token, pending := range c.pending {
+		if !now.Before(pending.expiresAt) {
+			delete(c.pending, token)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package access
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+)
+
+func TestConfirmations_TokenIsSingleUseAndBoundToIntent(t *testing.T) {
+	confirmations := NewConfirmations(time.Minute)
+
+	intent := Intent{
+		Instance:   "prod",
+		Action:     model.ActionNameTerminateBackend,
+		PID:        4242,
+		QueryStart: time.Date(2025, 10, 20, 12, 30, 15, 0, time.UTC),
+		User:       "alice",
+	}
+
+	token, expiresAt, err := confirmations.Issue(intent)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	assert.NoError(t, confirmations.Consume(token, intent))
+	assert.ErrorIs(t, confirmations.Consume(token, intent), ErrConfirmTokenUnknown)
+
+	// A token presented for another backend is rejected and cannot be retried
+	token, _, err = confirmations.Issue(intent)
+	require.NoError(t, err)
+
+	other := intent
+	other.PID = 4343
+	assert.ErrorIs(t, confirmations.Consume(token, other), ErrConfirmTokenMismatch)
+	assert.ErrorIs(t, confirmations.Consume(token, intent), ErrConfirmTokenUnknown)
+
+	// Another user cannot confirm somebody else's request
+	token, _, err = confirmations.Issue(intent)
+	require.NoError(t, err)
+
+	other = intent
+	other.User = "bob"
+	assert.ErrorIs(t, confirmations.Consume(token, other), ErrConfirmTokenMismatch)
+}
+
+func TestConfirmations_TokenExpires(t *testing.T) {
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	confirmations := NewConfirmations(time.Minute)
+	confirmations.now = func() time.Time { return now }
+
+	intent := Intent{Instance: "prod", Action: model.ActionNameCancelBackend, PID: 1, User: "alice"}
+
+	token, expiresAt, err := confirmations.Issue(intent)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+
+	now = now.Add(time.Minute)
+	assert.ErrorIs(t, confirmations.Consume(token, intent), ErrConfirmTokenUnknown)
+}
@@ -0,0 +1,65 @@
+package access
+
+import (
+	"fmt"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// Role is the access level of an MCP caller. Roles are ordered: each one
+// includes everything the previous one may do.
+type Role string
+
+const (
+	// RoleViewer may call every read-only tool
+	RoleViewer Role = "viewer"
+	// RoleOperator may also cancel running queries, save settings baselines and statements
+	// snapshots and manage alerts
+	RoleOperator Role = "operator"
+	// RoleAdmin may also terminate backends
+	RoleAdmin Role = "admin"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// requiredRoles lists the actions that change server or registry state; everything else needs RoleViewer
+var requiredRoles = map[model.ActionName]Role{
+	model.ActionNameCancelBackend:          RoleOperator,
+	model.ActionNameTerminateBackend:       RoleAdmin,
+	model.ActionNameSaveSettingsBaseline:   RoleOperator,
+	model.ActionNameSaveAlertRule:          RoleOperator,
+	model.ActionNameSilenceAlert:           RoleOperator,
+	model.ActionNameSaveStatementsSnapshot: RoleOperator,
+}
+
+// ParseRole validates a role name
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, ok := roleRank[role]; !ok {
+		return "", fmt.Errorf("unknown role %q (expected %s, %s or %s)", name, RoleViewer, RoleOperator, RoleAdmin)
+	}
+	return role, nil
+}
+
+// RequiredRole returns the lowest role allowed to run the action
+func RequiredRole(action model.ActionName) Role {
+	if role, ok := requiredRoles[action]; ok {
+		return role
+	}
+	return RoleViewer
+}
+
+// Principal is the authenticated caller of a tool
+type Principal struct {
+	User string `json:"user"`
+	Role Role   `json:"role"`
+}
+
+// Can reports whether the principal may run the action
+func (p Principal) Can(action model.ActionName) bool {
+	return roleRank[p.Role] >= roleRank[RequiredRole(action)]
+}
@@ -0,0 +1,54 @@
+package alerting
+
+import (
+	"os"
+	"time"
+)
+
+// Config holds the schedule and notification sinks of the alerting engine
+type Config struct {
+	// Interval is the time between two evaluations of all rules
+	Interval time.Duration
+	// WebhookURL receives notifications as JSON POST requests; empty disables the webhook
+	WebhookURL string
+	// WebhookTimeout bounds a single webhook call
+	WebhookTimeout time.Duration
+}
+
+// DefaultConfig returns the default alerting configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Interval:       time.Minute,
+		WebhookTimeout: 10 * time.Second,
+	}
+}
+
+// LoadConfigFromEnv loads the alerting configuration from environment variables
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	if interval := os.Getenv("ALERT_EVALUATION_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d > 0 {
+			cfg.Interval = d
+		}
+	}
+
+	cfg.WebhookURL = os.Getenv("ALERT_WEBHOOK_URL")
+
+	if timeout := os.Getenv("ALERT_WEBHOOK_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
+			cfg.WebhookTimeout = d
+		}
+	}
+
+	return cfg
+}
+
+// Notifiers returns the configured sinks; the log sink is always on
+func (c *Config) Notifiers() []Notifier {
+	notifiers := []Notifier{LogNotifier{}}
+	if c.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(c.WebhookURL, c.WebhookTimeout))
+	}
+	return notifiers
+}
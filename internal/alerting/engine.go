@@ -0,0 +1,350 @@
+// Package alerting evaluates alert rules on the output of router actions per
+// instance on a schedule, tracks their state in the registry and notifies sinks
+// when alerts start firing or resolve.
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/router"
+)
+
+// Store keeps rules, alert states and silences in the registry
+type Store interface {
+	ListAlertRules(ctx context.Context) ([]model.AlertRule, error)
+	ListAlertStates(ctx context.Context) ([]model.AlertState, error)
+	SaveAlertState(ctx context.Context, state *model.AlertState) error
+	DeleteAlertState(ctx context.Context, ruleName, instanceName string) error
+	ListActiveSilences(ctx context.Context, at time.Time) ([]model.AlertSilence, error)
+}
+
+// Engine evaluates the enabled rules against the matching instances
+type Engine struct {
+	store     Store
+	querier   router.Querier
+	instances router.InstanceLister
+	notifiers []Notifier
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewEngine(store Store, querier router.Querier, instances router.InstanceLister, config *Config) *Engine {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Engine{
+		store:     store,
+		querier:   querier,
+		instances: instances,
+		notifiers: config.Notifiers(),
+		interval:  config.Interval,
+		now:       time.Now,
+	}
+}
+
+// Run evaluates the rules every interval until the context is done
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Evaluate(ctx); err != nil {
+			log.Printf("Alert evaluation failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate runs one round: every enabled rule on every instance its selector matches.
+// An instance that cannot be queried keeps its alert state until the next round.
+func (e *Engine) Evaluate(ctx context.Context) error {
+	now := e.now()
+
+	rules, err := e.store.ListAlertRules(ctx)
+	if err != nil {
+		return err
+	}
+	instances, err := e.instances.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+	storedStates, err := e.store.ListAlertStates(ctx)
+	if err != nil {
+		return err
+	}
+	silences, err := e.store.ListActiveSilences(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	states := make(map[stateKey]*model.AlertState, len(storedStates))
+	for i := range storedStates {
+		state := &storedStates[i]
+		states[stateKey{state.RuleName, state.InstanceName}] = state
+	}
+	visited := map[stateKey]bool{}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		expression, err := ParseExpression(rule.Expression)
+		if err != nil {
+			log.Printf("Skipping alert rule %s: %v", rule.Name, err)
+			continue
+		}
+		selected, err := selectInstances(rule, instances)
+		if err != nil {
+			log.Printf("Skipping alert rule %s: %v", rule.Name, err)
+			continue
+		}
+
+		responses := e.querier.RouteAll(ctx, router.QueryRequest{Action: rule.Action, Parameters: rule.Params}, selected)
+		for i, response := range responses {
+			instance := selected[i]
+			key := stateKey{rule.Name, instance.Name}
+			visited[key] = true
+
+			if !response.Success {
+				log.Printf("Alert rule %s on %s: %s", rule.Name, instance.Name, response.Error)
+				continue
+			}
+			matched, value, err := Match(expression, response.Data)
+			if err != nil {
+				log.Printf("Alert rule %s on %s: %v", rule.Name, instance.Name, err)
+				continue
+			}
+
+			silenced := false
+			for _, silence := range silences {
+				if silence.Matches(rule.Name, instance.Name, now) {
+					silenced = true
+					break
+				}
+			}
+
+			if err := e.transition(ctx, rule, instance, states[key], matched, value, silenced, now); err != nil {
+				log.Printf("Alert rule %s on %s: %v", rule.Name, instance.Name, err)
+			}
+		}
+	}
+
+	// Rules that were disabled or removed and instances that no longer match leave no alerts behind
+	for key, state := range states {
+		if visited[key] {
+			continue
+		}
+		if err := e.store.DeleteAlertState(ctx, state.RuleName, state.InstanceName); err != nil {
+			log.Printf("Alert rule %s on %s: %v", state.RuleName, state.InstanceName, err)
+		}
+	}
+
+	return nil
+}
+
+type stateKey struct {
+	rule     string
+	instance string
+}
+
+func selectInstances(rule model.AlertRule, instances []model.Instance) ([]model.Instance, error) {
+	selector, err := model.ParseLabelSelector(rule.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Labels.Matches(selector) {
+			selected = append(selected, instance)
+		}
+	}
+	return selected, nil
+}
+
+// transition moves the alert of a rule on an instance to its next state and notifies
+// on the way into firing and out of it. A firing alert is announced once; while it is
+// silenced or a sink fails, the announcement is retried at the next evaluation.
+func (e *Engine) transition(
+	ctx context.Context,
+	rule model.AlertRule,
+	instance model.Instance,
+	state *model.AlertState,
+	matched bool,
+	value *float64,
+	silenced bool,
+	now time.Time,
+) error {
+	if !matched {
+		switch {
+		case state == nil || state.State == model.AlertStateResolved:
+			return nil
+		case state.State == model.AlertStatePending:
+			return e.store.DeleteAlertState(ctx, rule.Name, instance.Name)
+		}
+
+		state.State = model.AlertStateResolved
+		state.ResolvedAt = &now
+		state.Value = value
+		state.EvaluatedAt = now
+		if state.Notified {
+			if err := e.notify(ctx, rule, instance, state); err != nil {
+				log.Printf("Alert rule %s on %s: %v", rule.Name, instance.Name, err)
+			}
+		}
+		return e.store.SaveAlertState(ctx, state)
+	}
+
+	if state == nil || state.State == model.AlertStateResolved {
+		state = &model.AlertState{
+			RuleName:     rule.Name,
+			InstanceName: instance.Name,
+			State:        model.AlertStatePending,
+			ActiveSince:  now,
+		}
+	}
+	state.Value = value
+	state.EvaluatedAt = now
+
+	if state.State == model.AlertStatePending && now.Sub(state.ActiveSince) >= rule.For {
+		state.State = model.AlertStateFiring
+		state.FiringSince = &now
+	}
+
+	if state.State == model.AlertStateFiring && !state.Notified && !silenced {
+		if err := e.notify(ctx, rule, instance, state); err != nil {
+			log.Printf("Alert rule %s on %s: %v", rule.Name, instance.Name, err)
+		} else {
+			state.Notified = true
+		}
+	}
+
+	return e.store.SaveAlertState(ctx, state)
+}
+
+func (e *Engine) notify(ctx context.Context, rule model.AlertRule, instance model.Instance, state *model.AlertState) error {
+	notification := Notification{
+		Status:      state.State,
+		Rule:        rule.Name,
+		Description: rule.Description,
+		Severity:    rule.Severity,
+		Instance:    instance.Name,
+		Labels:      instance.Labels,
+		Action:      string(rule.Action),
+		Expression:  rule.Expression,
+		Value:       state.Value,
+		ActiveSince: state.ActiveSince,
+		FiringSince: state.FiringSince,
+		ResolvedAt:  state.ResolvedAt,
+	}
+
+	var errs []error
+	for _, notifier := range e.notifiers {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("%s notifier: %w", notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Match evaluates the expression against an action output. An object is evaluated
+// as is, a list matches when any of its elements does and any other value is
+// available as the field "value". value is the left operand of the outermost
+// comparison for the (first matching) object, nil when there is none.
+func Match(expression *Expression, data interface{}) (matched bool, value *float64, err error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to encode action output: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return false, nil, fmt.Errorf("failed to decode action output: %w", err)
+	}
+
+	var objects []map[string]interface{}
+	switch v := decoded.(type) {
+	case map[string]interface{}:
+		objects = []map[string]interface{}{v}
+	case []interface{}:
+		for _, element := range v {
+			object, ok := element.(map[string]interface{})
+			if !ok {
+				object = map[string]interface{}{"value": element}
+			}
+			objects = append(objects, object)
+		}
+	default:
+		objects = []map[string]interface{}{{"value": v}}
+	}
+
+	for _, object := range objects {
+		matched, v, err := expression.Eval(object)
+		if err != nil {
+			return false, nil, err
+		}
+		if value == nil && !math.IsNaN(v) {
+			value = &v
+		}
+		if matched {
+			if !math.IsNaN(v) {
+				value = &v
+			}
+			return true, value, nil
+		}
+	}
+	return false, value, nil
+}
+
+// ValidateRule checks a rule before it is saved: the expression must parse, the
+// selector must be valid and the action must be read-only and served by the router,
+// with parameter names the router reads.
+func ValidateRule(rule *model.AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if rule.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+	if role := access.RequiredRole(rule.Action); role != access.RoleViewer {
+		return fmt.Errorf("action %s changes state and cannot be used in alert rules", rule.Action)
+	}
+	if !router.SupportsAction(rule.Action) {
+		return fmt.Errorf("unknown action %s", rule.Action)
+	}
+	for name := range rule.Params {
+		if !router.IsParameter(name) {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	switch rule.Severity {
+	case model.AlertSeverityCritical, model.AlertSeverityWarning, model.AlertSeverityInfo:
+	default:
+		return fmt.Errorf("unknown severity %q, expected %s, %s or %s",
+			rule.Severity, model.AlertSeverityCritical, model.AlertSeverityWarning, model.AlertSeverityInfo)
+	}
+	if rule.For < 0 {
+		return fmt.Errorf("for must not be negative")
+	}
+	if _, err := ParseExpression(rule.Expression); err != nil {
+		return fmt.Errorf("invalid expression: %w", err)
+	}
+	if _, err := model.ParseLabelSelector(rule.Selector); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+)
+
+type memoryStore struct {
+	rules    []model.AlertRule
+	states   map[stateKey]model.AlertState
+	silences []model.AlertSilence
+}
+
+func (m *memoryStore) ListAlertRules(ctx context.Context) ([]model.AlertRule, error) {
+	return m.rules, nil
+}
+
+func (m *memoryStore) ListAlertStates(ctx context.Context) ([]model.AlertState, error) {
+	states := []model.AlertState{}
+	for _, state := range m.states {
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (m *memoryStore) SaveAlertState(ctx context.Context, state *model.AlertState) error {
+	m.states[stateKey{state.RuleName, state.InstanceName}] = *state
+	return nil
+}
+
+func (m *memoryStore) DeleteAlertState(ctx context.Context, ruleName, instanceName string) error {
+	delete(m.states, stateKey{ruleName, instanceName})
+	return nil
+}
+
+func (m *memoryStore) ListActiveSilences(ctx context.Context, at time.Time) ([]model.AlertSilence, error) {
+	return m.silences, nil
+}
+
+// connectionsQuerier answers connection_stats with the active count set per instance
+type connectionsQuerier struct {
+	active map[string]int
+}
+
+func (q *connectionsQuerier) RouteAll(ctx context.Context, req router.QueryRequest, instances []model.Instance) []*router.QueryResponse {
+	responses := make([]*router.QueryResponse, len(instances))
+	for i, instance := range instances {
+		responses[i] = &router.QueryResponse{
+			Instance: instance.Name,
+			Action:   req.Action,
+			Success:  true,
+			Data:     &pg.ConnectionSummary{Active: q.active[instance.Name], MaxConnections: 100},
+		}
+	}
+	return responses
+}
+
+type instanceList []model.Instance
+
+func (l instanceList) ListInstances(ctx context.Context) ([]model.Instance, error) {
+	return l, nil
+}
+
+type recordingNotifier struct {
+	notifications []Notification
+}
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) Notify(ctx context.Context, n Notification) error {
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func newTestEngine(store *memoryStore, querier *connectionsQuerier, instances instanceList) (*Engine, *recordingNotifier, *time.Time) {
+	notifier := &recordingNotifier{}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	engine := NewEngine(store, querier, instances, DefaultConfig())
+	engine.notifiers = []Notifier{notifier}
+	engine.now = func() time.Time { return now }
+	return engine, notifier, &now
+}
+
+func saturationRule() model.AlertRule {
+	return model.AlertRule{
+		Name:       "connection_saturation",
+		Action:     model.ActionNameConnectionStats,
+		Expression: "active / max_connections > 0.9",
+		For:        5 * time.Minute,
+		Severity:   model.AlertSeverityCritical,
+		Selector:   "env=prod",
+		Enabled:    true,
+	}
+}
+
+func TestEngine_PendingFiringResolved(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{rules: []model.AlertRule{saturationRule()}, states: map[stateKey]model.AlertState{}}
+	querier := &connectionsQuerier{active: map[string]int{"prod-1": 95, "dev-1": 99}}
+	instances := instanceList{
+		{Name: "prod-1", Labels: model.Labels{"env": "prod"}},
+		{Name: "dev-1", Labels: model.Labels{"env": "dev"}},
+	}
+	engine, notifier, now := newTestEngine(store, querier, instances)
+
+	require.NoError(t, engine.Evaluate(ctx))
+	state := store.states[stateKey{"connection_saturation", "prod-1"}]
+	assert.Equal(t, model.AlertStatePending, state.State)
+	assert.InDelta(t, 0.95, *state.Value, 1e-9)
+	assert.NotContains(t, store.states, stateKey{"connection_saturation", "dev-1"}, "dev-1 does not match the selector")
+
+	*now = now.Add(5 * time.Minute)
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notifications, 1)
+	assert.Equal(t, model.AlertStateFiring, notifier.notifications[0].Status)
+	assert.Equal(t, "prod-1", notifier.notifications[0].Instance)
+
+	// Still firing: no duplicate notification
+	*now = now.Add(time.Minute)
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Len(t, notifier.notifications, 1)
+
+	querier.active["prod-1"] = 10
+	*now = now.Add(time.Minute)
+	require.NoError(t, engine.Evaluate(ctx))
+	require.Len(t, notifier.notifications, 2)
+	assert.Equal(t, model.AlertStateResolved, notifier.notifications[1].Status)
+	assert.Equal(t, *now, *notifier.notifications[1].ResolvedAt)
+	assert.Equal(t, model.AlertStateResolved, store.states[stateKey{"connection_saturation", "prod-1"}].State)
+}
+
+func TestEngine_ShortSpikeStaysPending(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{rules: []model.AlertRule{saturationRule()}, states: map[stateKey]model.AlertState{}}
+	querier := &connectionsQuerier{active: map[string]int{"prod-1": 95}}
+	engine, notifier, now := newTestEngine(store, querier, instanceList{{Name: "prod-1", Labels: model.Labels{"env": "prod"}}})
+
+	require.NoError(t, engine.Evaluate(ctx))
+	querier.active["prod-1"] = 50
+	*now = now.Add(time.Minute)
+	require.NoError(t, engine.Evaluate(ctx))
+
+	assert.Empty(t, notifier.notifications)
+	assert.Empty(t, store.states)
+}
+
+func TestEngine_SilenceDefersNotification(t *testing.T) {
+	ctx := context.Background()
+	rule := saturationRule()
+	rule.For = 0
+	store := &memoryStore{
+		rules:  []model.AlertRule{rule},
+		states: map[stateKey]model.AlertState{},
+	}
+	querier := &connectionsQuerier{active: map[string]int{"prod-1": 95}}
+	engine, notifier, now := newTestEngine(store, querier, instanceList{{Name: "prod-1", Labels: model.Labels{"env": "prod"}}})
+	store.silences = []model.AlertSilence{{InstanceName: "prod-1", StartsAt: *now, EndsAt: now.Add(time.Hour)}}
+
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Equal(t, model.AlertStateFiring, store.states[stateKey{rule.Name, "prod-1"}].State)
+	assert.Empty(t, notifier.notifications)
+
+	*now = now.Add(time.Hour)
+	require.NoError(t, engine.Evaluate(ctx))
+	assert.Len(t, notifier.notifications, 1, "announced once the silence has ended")
+}
+
+func TestEngine_DisabledRuleClearsState(t *testing.T) {
+	ctx := context.Background()
+	rule := saturationRule()
+	rule.Enabled = false
+	store := &memoryStore{
+		rules: []model.AlertRule{rule},
+		states: map[stateKey]model.AlertState{
+			{rule.Name, "prod-1"}: {RuleName: rule.Name, InstanceName: "prod-1", State: model.AlertStateFiring},
+		},
+	}
+	engine, _, _ := newTestEngine(store, &connectionsQuerier{}, instanceList{{Name: "prod-1", Labels: model.Labels{"env": "prod"}}})
+
+	require.NoError(t, engine.Evaluate(ctx))
+
+	assert.Empty(t, store.states)
+}
+
+func TestMatch_ListMatchesAnyElement(t *testing.T) {
+	expression, err := ParseExpression("retained_bytes > 1000")
+	require.NoError(t, err)
+
+	data := []map[string]interface{}{
+		{"slot_name": "a", "retained_bytes": 10},
+		{"slot_name": "b", "retained_bytes": 5000},
+	}
+	matched, value, err := Match(expression, data)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, 5000.0, *value)
+
+	matched, value, err = Match(expression, []map[string]interface{}{})
+	require.NoError(t, err)
+	assert.False(t, matched)
+	assert.Nil(t, value)
+}
+
+func TestValidateRule(t *testing.T) {
+	rule := saturationRule()
+	assert.NoError(t, ValidateRule(&rule))
+
+	rule.Action = model.ActionNameTerminateBackend
+	assert.ErrorContains(t, ValidateRule(&rule), "changes state")
+
+	rule = saturationRule()
+	rule.Action = "connection_stat"
+	assert.ErrorContains(t, ValidateRule(&rule), "unknown action")
+
+	rule = saturationRule()
+	rule.Severity = "page"
+	assert.ErrorContains(t, ValidateRule(&rule), "unknown severity")
+
+	rule = saturationRule()
+	rule.Expression = "active"
+	assert.ErrorContains(t, ValidateRule(&rule), "invalid expression")
+
+	rule = saturationRule()
+	rule.Params = map[string]interface{}{"dbName": "shop"}
+	assert.NoError(t, ValidateRule(&rule))
+
+	rule.Params = map[string]interface{}{"db_name": "shop"}
+	assert.ErrorContains(t, ValidateRule(&rule), `unknown parameter "db_name"`)
+}
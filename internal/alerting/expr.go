@@ -0,0 +1,463 @@
+package alerting
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed alert condition such as "active / max_connections > 0.9".
+//
+// Operands are numbers and fields of the action output, addressed by their JSON names
+// and joined with dots for nested objects (version.major). Booleans count as 1 and 0,
+// null as a missing value that makes every comparison false, and an array as its
+// length. Supported operators, by increasing precedence: ||, &&, !, comparisons
+// (> >= < <= == !=), + -, * /, unary minus.
+type Expression struct {
+	source string
+	root   node
+}
+
+// ParseExpression parses an alert condition. The outermost operator must be a
+// comparison or a logical operator, so that the expression is true or false.
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.tokens[p.pos].text, p.tokens[p.pos].pos+1)
+	}
+	if !root.boolean() {
+		return nil, fmt.Errorf("expression must compare a value, e.g. %s > 10", source)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against one object of an action output. value is the
+// left operand of the outermost comparison, NaN when the expression combines several.
+func (e *Expression) Eval(fields map[string]interface{}) (matched bool, value float64, err error) {
+	value = math.NaN()
+	if cmp, ok := e.root.(*comparison); ok {
+		if value, err = cmp.left.eval(fields); err != nil {
+			return false, value, err
+		}
+	}
+
+	result, err := e.root.eval(fields)
+	if err != nil {
+		return false, value, err
+	}
+	return result == 1, value, nil
+}
+
+type node interface {
+	eval(fields map[string]interface{}) (float64, error)
+	// boolean reports whether the node yields true or false rather than a number
+	boolean() bool
+}
+
+type number float64
+
+func (n number) eval(map[string]interface{}) (float64, error) { return float64(n), nil }
+func (n number) boolean() bool                                { return false }
+
+type field []string
+
+func (f field) eval(fields map[string]interface{}) (float64, error) {
+	var current interface{} = fields
+	for i, name := range f {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return 0, fmt.Errorf("field %s is not an object", strings.Join(f[:i], "."))
+		}
+		if current, ok = object[name]; !ok {
+			return 0, fmt.Errorf("unknown field %s", strings.Join(f[:i+1], "."))
+		}
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return math.NaN(), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case []interface{}:
+		return float64(len(v)), nil
+	default:
+		return 0, fmt.Errorf("field %s is not a number", strings.Join(f, "."))
+	}
+}
+
+func (f field) boolean() bool { return false }
+
+type negation struct{ operand node }
+
+func (n *negation) eval(fields map[string]interface{}) (float64, error) {
+	v, err := n.operand.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	return -v, nil
+}
+
+func (n *negation) boolean() bool { return false }
+
+type not struct{ operand node }
+
+func (n *not) eval(fields map[string]interface{}) (float64, error) {
+	v, err := n.operand.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	return truth(!(v != 0 && !math.IsNaN(v))), nil
+}
+
+func (n *not) boolean() bool { return true }
+
+type arithmetic struct {
+	op          string
+	left, right node
+}
+
+func (a *arithmetic) eval(fields map[string]interface{}) (float64, error) {
+	l, err := a.left.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	r, err := a.right.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+
+	switch a.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	default:
+		// A zero denominator has no meaningful ratio, so comparisons with it are false
+		if r == 0 {
+			return math.NaN(), nil
+		}
+		return l / r, nil
+	}
+}
+
+func (a *arithmetic) boolean() bool { return false }
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (c *comparison) eval(fields map[string]interface{}) (float64, error) {
+	l, err := c.left.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.right.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+
+	switch c.op {
+	case ">":
+		return truth(l > r), nil
+	case ">=":
+		return truth(l >= r), nil
+	case "<":
+		return truth(l < r), nil
+	case "<=":
+		return truth(l <= r), nil
+	case "==":
+		return truth(l == r), nil
+	default:
+		return truth(l != r && !math.IsNaN(l) && !math.IsNaN(r)), nil
+	}
+}
+
+func (c *comparison) boolean() bool { return true }
+
+type logical struct {
+	op          string
+	left, right node
+}
+
+func (l *logical) eval(fields map[string]interface{}) (float64, error) {
+	left, err := l.left.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	if l.op == "&&" && left != 1 {
+		return 0, nil
+	}
+	if l.op == "||" && left == 1 {
+		return 1, nil
+	}
+
+	right, err := l.right.eval(fields)
+	if err != nil {
+		return 0, err
+	}
+	return truth(right == 1), nil
+}
+
+func (l *logical) boolean() bool { return true }
+
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type token struct {
+	kind string // number, ident or the operator itself
+	text string
+	pos  int
+}
+
+var operators = []string{"&&", "||", ">=", "<=", "==", "!=", ">", "<", "!", "+", "-", "*", "/", "(", ")"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: "number", text: source[start:i], pos: start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(source) && (unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i])) || source[i] == '_' || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: "ident", text: source[start:i], pos: start})
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, token{kind: op, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i+1)
+			}
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(kinds ...string) (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	for _, kind := range kinds {
+		if p.tokens[p.pos].kind == kind {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peek("||"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peek("&&"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) not() (node, error) {
+	if _, ok := p.peek("!"); ok {
+		p.pos++
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &not{operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peek(">", ">=", "<", "<=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	return &comparison{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) sum() (node, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peek("+", "-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) product() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peek("*", "/")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if _, ok := p.peek("-"); ok {
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case "number":
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos+1)
+		}
+		return number(v), nil
+
+	case "ident":
+		switch tok.text {
+		case "true":
+			return number(1), nil
+		case "false":
+			return number(0), nil
+		}
+		path := strings.Split(tok.text, ".")
+		for _, name := range path {
+			if name == "" {
+				return nil, fmt.Errorf("invalid field %q at position %d", tok.text, tok.pos+1)
+			}
+		}
+		return field(path), nil
+
+	case "(":
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.peek(")"); !ok {
+			return nil, fmt.Errorf("missing ) for ( at position %d", tok.pos+1)
+		}
+		p.pos++
+		return inner, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+}
@@ -0,0 +1,69 @@
+package alerting
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Eval(t *testing.T) {
+	fields := map[string]interface{}{
+		"active":          float64(95),
+		"max_connections": float64(100),
+		"idle":            float64(0),
+		"hit_rate":        nil,
+		"replay_paused":   true,
+		"version":         map[string]interface{}{"major": float64(16)},
+		"root_blockers":   []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+	}
+
+	tests := []struct {
+		expression string
+		matched    bool
+		value      float64
+	}{
+		{"active / max_connections > 0.9", true, 0.95},
+		{"active / max_connections > 0.95", false, 0.95},
+		{"active / idle > 1", false, math.NaN()},
+		{"hit_rate < 0.99", false, math.NaN()},
+		{"hit_rate != 1", false, math.NaN()},
+		{"replay_paused == true", true, 1},
+		{"version.major >= 16 && (active > 90 || idle > 0)", true, math.NaN()},
+		{"!(active > 90)", false, math.NaN()},
+		{"root_blockers > 1", true, 2},
+		{"-active + 2 * 50 <= 5", true, 5},
+	}
+
+	for _, tt := range tests {
+		expression, err := ParseExpression(tt.expression)
+		require.NoError(t, err, tt.expression)
+
+		matched, value, err := expression.Eval(fields)
+		require.NoError(t, err, tt.expression)
+		assert.Equal(t, tt.matched, matched, tt.expression)
+		if math.IsNaN(tt.value) {
+			assert.True(t, math.IsNaN(value), tt.expression)
+		} else {
+			assert.InDelta(t, tt.value, value, 1e-9, tt.expression)
+		}
+	}
+}
+
+func TestExpression_Errors(t *testing.T) {
+	for _, source := range []string{"", "active", "active >", "(active > 1", "active > 1 )", "active # 1", "active..x > 1"} {
+		_, err := ParseExpression(source)
+		assert.Error(t, err, source)
+	}
+
+	expression, err := ParseExpression("activ > 1")
+	require.NoError(t, err)
+	_, _, err = expression.Eval(map[string]interface{}{"active": float64(1)})
+	assert.EqualError(t, err, "unknown field activ")
+
+	expression, err = ParseExpression("state.x > 1")
+	require.NoError(t, err)
+	_, _, err = expression.Eval(map[string]interface{}{"state": "active"})
+	assert.EqualError(t, err, "field state is not an object")
+}
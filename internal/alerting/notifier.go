@@ -0,0 +1,93 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// Notification announces that an alert started firing or was resolved
+type Notification struct {
+	Status      string       `json:"status"` // firing or resolved
+	Rule        string       `json:"rule"`
+	Description string       `json:"description,omitempty"`
+	Severity    string       `json:"severity"`
+	Instance    string       `json:"instance"`
+	Labels      model.Labels `json:"labels,omitempty"`
+	Action      string       `json:"action"`
+	Expression  string       `json:"expression"`
+	Value       *float64     `json:"value,omitempty"`
+	ActiveSince time.Time    `json:"active_since"`
+	FiringSince *time.Time   `json:"firing_since,omitempty"`
+	ResolvedAt  *time.Time   `json:"resolved_at,omitempty"`
+}
+
+// Notifier delivers notifications to a sink
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the server log
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string {
+	return "log"
+}
+
+func (LogNotifier) Notify(ctx context.Context, n Notification) error {
+	value := "-"
+	if n.Value != nil {
+		value = fmt.Sprintf("%g", *n.Value)
+	}
+	log.Printf("Alert %s: %s on %s [%s] %s (value %s)", n.Status, n.Rule, n.Instance, n.Severity, n.Expression, value)
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a URL
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
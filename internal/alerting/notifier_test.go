@@ -0,0 +1,47 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_PostsJSON(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	value := 0.95
+	err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), Notification{
+		Status:   "firing",
+		Rule:     "connection_saturation",
+		Instance: "prod-1",
+		Value:    &value,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "connection_saturation", received.Rule)
+	assert.Equal(t, 0.95, *received.Value)
+}
+
+func TestWebhookNotifier_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), Notification{})
+
+	assert.EqualError(t, err, "webhook returned 503 Service Unavailable")
+}
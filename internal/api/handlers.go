@@ -30,6 +30,7 @@ func (s *APIServer) RegisterInstance(c *gin.Context) {
 		Description:     req.Description,
 		CreatorUsername: req.CreatorUsername,
 		Status:          "active",
+		Labels:          req.Labels,
 	}
 
 	// Register the instance
@@ -43,6 +44,13 @@ func (s *APIServer) RegisterInstance(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, instance_manager.ErrInvalidInstanceName) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+			})
+			return
+		}
 
 		// Handle other errors
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -70,6 +78,7 @@ func (s *APIServer) RegisterInstance(c *gin.Context) {
 		Description:     createdInstance.Description,
 		CreatorUsername: createdInstance.CreatorUsername,
 		Status:          createdInstance.Status,
+		Labels:          createdInstance.Labels,
 		CreatedAt:       createdInstance.CreatedAt,
 		UpdatedAt:       createdInstance.UpdatedAt,
 	}
@@ -0,0 +1,473 @@
+// Package healthreport runs the statistics checks of an instance concurrently and
+// scores the result against a configurable rule set.
+package healthreport
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"psql-mcp-registry/internal/pg"
+)
+
+// Severities of findings, most urgent first
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+)
+
+// Overall status of a report
+const (
+	StatusHealthy  = "healthy"
+	StatusDegraded = "degraded"
+	StatusCritical = "critical"
+)
+
+// Penalties subtracted from the score of 100 per finding
+var penalties = map[string]int{
+	SeverityCritical: 25,
+	SeverityWarning:  10,
+}
+
+const (
+	// TablesLimit is how many of the largest tables are checked for dead tuples and vacuum age
+	TablesLimit = 200
+	// minDeadTuples keeps small tables out of the dead tuple and vacuum rules
+	minDeadTuples = 10000
+	// minCheckpoints is the number of checkpoints needed before the requested ratio is judged
+	minCheckpoints = 10
+	// maxEvidenceTables caps the tables listed as evidence of one finding
+	maxEvidenceTables = 5
+	// maxConcurrentChecks bounds the connections a report uses at once to the size of
+	// a per-database pool
+	maxConcurrentChecks = 2
+)
+
+// Names of the checks, as reported in Report.Unavailable
+const (
+	CheckDatabaseOverview = "database_overview"
+	CheckCacheHitRate     = "cache_hit_rate"
+	CheckConnections      = "connection_stats"
+	CheckCheckpoints      = "checkpoints_stats"
+	CheckTables           = "tables_info"
+)
+
+var checkNames = []string{CheckDatabaseOverview, CheckCacheHitRate, CheckConnections, CheckCheckpoints, CheckTables}
+
+// Facts are the results of the checks a report is built from. A nil field means
+// the check failed and the rules that need it are skipped.
+type Facts struct {
+	Database    *pg.DatabaseOverview
+	CacheHit    *pg.CacheHitRate
+	Connections *pg.ConnectionSummary
+	Checkpoints *pg.CheckpointsStats
+	Tables      []pg.TableInfo
+	// Errors maps the failed checks to their error
+	Errors map[string]string
+}
+
+// Finding is a rule that crossed its threshold
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  string   `json:"severity"`
+	Penalty   int      `json:"penalty"`
+	Value     *float64 `json:"value"`     // observed value in the unit of the rule threshold, null when there is none (a table never vacuumed)
+	Threshold float64  `json:"threshold"` // the threshold that was crossed
+	Summary   string   `json:"summary"`
+	Evidence  []string `json:"evidence"`
+
+	// value is Value for ranking; +Inf for a table never vacuumed
+	value float64
+	// excess is how far the value is past the warning threshold, relative to the distance
+	// between the warning and critical thresholds
+	excess float64
+}
+
+// Report is the scored result of the rules
+type Report struct {
+	Database    string    `json:"database"`
+	Score       int       `json:"score"` // 100 minus the penalties of the findings, at least 0
+	Status      string    `json:"status"`
+	Findings    []Finding `json:"findings"` // ranked by severity, then by how close the value is to the next threshold
+	Checked     []string  `json:"checked"`  // rules that were evaluated
+	Skipped     []string  `json:"skipped"`  // rules that were disabled or lacked data
+	Unavailable []string  `json:"unavailable"`
+}
+
+// Run collects the facts of dbName and evaluates them. It fails only when every check
+// failed, so that an unreachable instance is reported as an error rather than as healthy.
+func Run(ctx context.Context, client pg.ClientInterface, dbName string, rules Rules) (*Report, error) {
+	facts, err := Collect(ctx, client, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Evaluate(facts, rules, time.Now())
+	report.Database = dbName
+	return report, nil
+}
+
+// Collect runs the checks concurrently, at most maxConcurrentChecks at a time
+func Collect(ctx context.Context, client pg.ClientInterface, dbName string) (*Facts, error) {
+	facts := &Facts{Errors: map[string]string{}}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	slots := make(chan struct{}, maxConcurrentChecks)
+	check := func(name string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := fn(); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				facts.Errors[name] = err.Error()
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}()
+	}
+
+	// Every check writes its own field, so only the error map needs the mutex
+	check(CheckDatabaseOverview, func() (err error) {
+		facts.Database, err = client.GetDatabaseOverview(ctx, dbName)
+		return err
+	})
+	check(CheckCacheHitRate, func() (err error) {
+		facts.CacheHit, err = client.GetCacheHitRateDB(ctx, dbName)
+		return err
+	})
+	check(CheckConnections, func() (err error) {
+		facts.Connections, err = client.GetConnectionStats(ctx)
+		return err
+	})
+	check(CheckCheckpoints, func() (err error) {
+		facts.Checkpoints, err = client.GetCheckpointsStats(ctx)
+		return err
+	})
+	check(CheckTables, func() (err error) {
+		facts.Tables, err = client.GetTablesInfoDB(ctx, dbName, TablesLimit)
+		return err
+	})
+	wg.Wait()
+
+	if len(facts.Errors) == len(checkNames) {
+		return nil, fmt.Errorf("all health checks failed: %w", firstErr)
+	}
+	return facts, nil
+}
+
+// Evaluate applies the enabled rules to the facts. now is the reference for vacuum age.
+func Evaluate(facts *Facts, rules Rules, now time.Time) *Report {
+	report := &Report{
+		Findings:    []Finding{},
+		Checked:     []string{},
+		Skipped:     []string{},
+		Unavailable: []string{},
+	}
+
+	for _, name := range checkNames {
+		if err, failed := facts.Errors[name]; failed {
+			report.Unavailable = append(report.Unavailable, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	e := evaluator{facts: facts, now: now}
+	evaluators := map[string]func(Threshold) (*Finding, bool){
+		RuleCacheHitRate:         e.cacheHitRate,
+		RuleDeadTuples:           e.deadTuples,
+		RuleConnectionSaturation: e.connectionSaturation,
+		RuleRequestedCheckpoints: e.requestedCheckpoints,
+		RuleStaleAutovacuum:      e.staleAutovacuum,
+		RuleDeadlocks:            e.deadlocks,
+		RuleTempBytes:            e.tempBytes,
+	}
+
+	for _, name := range ruleNames {
+		if !rules.enabled(name) {
+			report.Skipped = append(report.Skipped, name)
+			continue
+		}
+
+		finding, evaluated := evaluators[name](rules.Thresholds[name])
+		if !evaluated {
+			report.Skipped = append(report.Skipped, name)
+			continue
+		}
+		report.Checked = append(report.Checked, name)
+		if finding != nil {
+			finding.Rule = name
+			finding.Penalty = penalties[finding.Severity]
+			report.Findings = append(report.Findings, *finding)
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Penalty != b.Penalty {
+			return a.Penalty > b.Penalty
+		}
+		return a.excess > b.excess
+	})
+
+	report.Score = 100
+	report.Status = StatusHealthy
+	for _, finding := range report.Findings {
+		report.Score -= finding.Penalty
+		if finding.Severity == SeverityCritical {
+			report.Status = StatusCritical
+		} else if report.Status == StatusHealthy {
+			report.Status = StatusDegraded
+		}
+	}
+	report.Score = max(report.Score, 0)
+
+	return report
+}
+
+type evaluator struct {
+	facts *Facts
+	now   time.Time
+}
+
+// classify compares value with the threshold of rule and returns nil when it is within it
+func classify(rule string, value float64, threshold Threshold) *Finding {
+	finding := &Finding{Value: &value, value: value, Evidence: []string{}}
+
+	crossed := func(limit float64) bool {
+		if lowerIsWorse[rule] {
+			return value < limit
+		}
+		return value >= limit
+	}
+	switch {
+	case crossed(threshold.Critical):
+		finding.Severity = SeverityCritical
+		finding.Threshold = threshold.Critical
+	case crossed(threshold.Warning):
+		finding.Severity = SeverityWarning
+		finding.Threshold = threshold.Warning
+	default:
+		return nil
+	}
+
+	// 0 at the warning threshold, 1 at the critical one
+	if span := math.Abs(threshold.Critical - threshold.Warning); span > 0 {
+		finding.excess = math.Abs(value-threshold.Warning) / span
+	} else {
+		finding.excess = math.Abs(value - threshold.Warning)
+	}
+	return finding
+}
+
+func (e evaluator) cacheHitRate(threshold Threshold) (*Finding, bool) {
+	if e.facts.CacheHit == nil || !e.facts.CacheHit.HitRate.Valid {
+		return nil, false
+	}
+
+	rate := 100 * e.facts.CacheHit.HitRate.Float64
+	finding := classify(RuleCacheHitRate, rate, threshold)
+	if finding == nil {
+		return nil, true
+	}
+
+	finding.Summary = fmt.Sprintf("cache hit rate is %.2f%%, below %g%%", rate, finding.Threshold)
+	if database := e.facts.Database; database != nil {
+		finding.Evidence = append(finding.Evidence,
+			fmt.Sprintf("blks_hit %d, blks_read %d", database.BlksHit, database.BlksRead))
+		if database.BlkReadTime > 0 {
+			finding.Evidence = append(finding.Evidence, fmt.Sprintf("blk_read_time %.0f ms", database.BlkReadTime))
+		}
+	}
+	return finding, true
+}
+
+func (e evaluator) deadTuples(threshold Threshold) (*Finding, bool) {
+	if e.facts.Tables == nil {
+		return nil, false
+	}
+
+	var worst *Finding
+	var offenders []pg.TableInfo
+	for _, table := range e.facts.Tables {
+		if !table.DeadRatio.Valid || table.NDeadTup.Int64 < minDeadTuples {
+			continue
+		}
+		finding := classify(RuleDeadTuples, table.DeadRatio.Float64, threshold)
+		if finding == nil {
+			continue
+		}
+		offenders = append(offenders, table)
+		if worst == nil || finding.value > worst.value {
+			worst = finding
+		}
+	}
+	if worst == nil {
+		return nil, true
+	}
+
+	sort.SliceStable(offenders, func(i, j int) bool {
+		return offenders[i].DeadRatio.Float64 > offenders[j].DeadRatio.Float64
+	})
+	worst.Summary = fmt.Sprintf("tables with %g%% dead tuples or more: %d, up to %.1f%%", threshold.Warning, len(offenders), worst.value)
+	for _, table := range offenders[:min(len(offenders), maxEvidenceTables)] {
+		worst.Evidence = append(worst.Evidence, fmt.Sprintf("%s.%s: %.1f%% dead (%d dead, %d live tuples)",
+			table.SchemaName, table.TableName, table.DeadRatio.Float64, table.NDeadTup.Int64, table.NLiveTup.Int64))
+	}
+	return worst, true
+}
+
+func (e evaluator) connectionSaturation(threshold Threshold) (*Finding, bool) {
+	connections := e.facts.Connections
+	if connections == nil || connections.MaxConnections == 0 {
+		return nil, false
+	}
+
+	used := 100 * float64(connections.TotalConnections) / float64(connections.MaxConnections)
+	finding := classify(RuleConnectionSaturation, used, threshold)
+	if finding == nil {
+		return nil, true
+	}
+
+	finding.Summary = fmt.Sprintf("%d of %d connections are in use (%.0f%%)", connections.TotalConnections, connections.MaxConnections, used)
+	finding.Evidence = append(finding.Evidence, fmt.Sprintf("active %d, idle %d, idle in transaction %d, waiting %d",
+		connections.Active, connections.Idle, connections.IdleInTransaction, connections.Waiting))
+	return finding, true
+}
+
+func (e evaluator) requestedCheckpoints(threshold Threshold) (*Finding, bool) {
+	stats := e.facts.Checkpoints
+	if stats == nil {
+		return nil, false
+	}
+	total := stats.CheckpointsTimed + stats.CheckpointsReq
+	if total < minCheckpoints {
+		return nil, false
+	}
+
+	ratio := 100 * float64(stats.CheckpointsReq) / float64(total)
+	finding := classify(RuleRequestedCheckpoints, ratio, threshold)
+	if finding == nil {
+		return nil, true
+	}
+
+	finding.Summary = fmt.Sprintf("%.0f%% of checkpoints were requested instead of timed; max_wal_size is too small for the write load", ratio)
+	finding.Evidence = append(finding.Evidence,
+		fmt.Sprintf("checkpoints_req %d, checkpoints_timed %d", stats.CheckpointsReq, stats.CheckpointsTimed),
+		fmt.Sprintf("checkpoint_write_time %.0f ms, checkpoint_sync_time %.0f ms", stats.CheckpointWriteTime, stats.CheckpointSyncTime))
+	return finding, true
+}
+
+func (e evaluator) staleAutovacuum(threshold Threshold) (*Finding, bool) {
+	if e.facts.Tables == nil {
+		return nil, false
+	}
+
+	type staleTable struct {
+		table pg.TableInfo
+		hours float64 // +Inf when never vacuumed
+	}
+
+	var worst *Finding
+	var offenders []staleTable
+	for _, table := range e.facts.Tables {
+		if table.NDeadTup.Int64 < minDeadTuples {
+			continue
+		}
+
+		hours := math.Inf(1)
+		if last := lastVacuum(table); !last.IsZero() {
+			hours = math.Round(e.now.Sub(last).Hours()*10) / 10
+		}
+
+		finding := classify(RuleStaleAutovacuum, hours, threshold)
+		if finding == nil {
+			continue
+		}
+		offenders = append(offenders, staleTable{table: table, hours: hours})
+		if worst == nil || hours > worst.value {
+			worst = finding
+		}
+	}
+	if worst == nil {
+		return nil, true
+	}
+
+	sort.SliceStable(offenders, func(i, j int) bool {
+		return offenders[i].hours > offenders[j].hours
+	})
+	// A never vacuumed table has no age to report; JSON cannot carry +Inf either
+	if math.IsInf(worst.value, 1) {
+		worst.Value = nil
+		worst.excess = math.MaxFloat64
+	}
+	worst.Summary = fmt.Sprintf("tables with dead tuples not vacuumed for %g hours or more: %d", threshold.Warning, len(offenders))
+	for _, stale := range offenders[:min(len(offenders), maxEvidenceTables)] {
+		age := "never vacuumed"
+		if !math.IsInf(stale.hours, 1) {
+			age = fmt.Sprintf("last vacuumed %.0f hours ago", stale.hours)
+		}
+		worst.Evidence = append(worst.Evidence, fmt.Sprintf("%s.%s: %s, %d dead tuples",
+			stale.table.SchemaName, stale.table.TableName, age, stale.table.NDeadTup.Int64))
+	}
+	return worst, true
+}
+
+func lastVacuum(table pg.TableInfo) time.Time {
+	var last time.Time
+	if table.LastVacuum.Valid {
+		last = table.LastVacuum.Time
+	}
+	if table.LastAutovacuum.Valid && table.LastAutovacuum.Time.After(last) {
+		last = table.LastAutovacuum.Time
+	}
+	return last
+}
+
+func (e evaluator) deadlocks(threshold Threshold) (*Finding, bool) {
+	database := e.facts.Database
+	if database == nil {
+		return nil, false
+	}
+
+	finding := classify(RuleDeadlocks, float64(database.Deadlocks), threshold)
+	if finding == nil {
+		return nil, true
+	}
+
+	finding.Summary = fmt.Sprintf("%d deadlocks since the statistics reset; the application acquires locks in inconsistent order", database.Deadlocks)
+	finding.Evidence = append(finding.Evidence,
+		fmt.Sprintf("xact_commit %d, xact_rollback %d", database.XactCommit, database.XactRollback))
+	return finding, true
+}
+
+func (e evaluator) tempBytes(threshold Threshold) (*Finding, bool) {
+	database := e.facts.Database
+	if database == nil {
+		return nil, false
+	}
+
+	mb := float64(database.TempBytes) / (1 << 20)
+	finding := classify(RuleTempBytes, mb, threshold)
+	if finding == nil {
+		return nil, true
+	}
+
+	finding.Summary = fmt.Sprintf("%.0f MB written to temp files since the statistics reset; sorts and hashes spill to disk", mb)
+	finding.Evidence = append(finding.Evidence, fmt.Sprintf("temp_files %d", database.TempFiles))
+	if database.TempFiles > 0 {
+		finding.Evidence = append(finding.Evidence,
+			fmt.Sprintf("%.1f MB per temp file on average", mb/float64(database.TempFiles)))
+	}
+	return finding, true
+}
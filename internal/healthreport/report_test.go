@@ -0,0 +1,178 @@
+package healthreport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func table(name string, live, dead int64, lastAutovacuum time.Time) pg.TableInfo {
+	t := pg.TableInfo{
+		SchemaName: "public",
+		TableName:  name,
+		NLiveTup:   pg.NewNullInt64(live),
+		NDeadTup:   pg.NewNullInt64(dead),
+		DeadRatio:  pg.NewNullFloat64(100 * float64(dead) / float64(live+dead)),
+	}
+	if !lastAutovacuum.IsZero() {
+		t.LastAutovacuum = pg.NewNullTime(lastAutovacuum)
+	}
+	return t
+}
+
+func healthyFacts() *Facts {
+	return &Facts{
+		Database:    &pg.DatabaseOverview{BlksHit: 999000, BlksRead: 1000},
+		CacheHit:    &pg.CacheHitRate{HitRate: pg.NewNullFloat64(0.999)},
+		Connections: &pg.ConnectionSummary{TotalConnections: 20, MaxConnections: 100},
+		Checkpoints: &pg.CheckpointsStats{CheckpointsTimed: 95, CheckpointsReq: 5},
+		Tables:      []pg.TableInfo{table("orders", 1000000, 20000, now.Add(-time.Hour))},
+		Errors:      map[string]string{},
+	}
+}
+
+func TestEvaluate_HealthyInstanceScores100(t *testing.T) {
+	report := Evaluate(healthyFacts(), DefaultRules(), now)
+
+	assert.Equal(t, 100, report.Score)
+	assert.Equal(t, StatusHealthy, report.Status)
+	assert.Empty(t, report.Findings)
+	assert.Equal(t, RuleNames(), report.Checked)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestEvaluate_RanksFindingsBySeverityAndExcess(t *testing.T) {
+	facts := healthyFacts()
+	facts.CacheHit.HitRate = pg.NewNullFloat64(0.97)
+	facts.Connections.TotalConnections = 97
+	facts.Database.Deadlocks = 3
+	facts.Database.TempBytes = 2048 << 20
+	facts.Database.TempFiles = 4
+	facts.Tables = []pg.TableInfo{
+		table("orders", 1000000, 20000, now.Add(-time.Hour)),
+		table("events", 60000, 40000, now.Add(-48*time.Hour)),
+		table("audit", 100000, 50000, time.Time{}),
+	}
+
+	report := Evaluate(facts, DefaultRules(), now)
+
+	rules := make([]string, len(report.Findings))
+	for i, finding := range report.Findings {
+		rules[i] = finding.Rule
+	}
+	assert.Equal(t, []string{
+		RuleStaleAutovacuum,      // critical, never vacuumed
+		RuleConnectionSaturation, // critical
+		RuleDeadTuples,           // warning, 40% is two thirds of the way to 50%
+		RuleCacheHitRate,         // warning, 97% is half way to 95%
+		RuleTempBytes,            // warning, 2GB against 1GB and 10GB
+		RuleDeadlocks,            // warning, 3 against 1 and 100
+	}, rules)
+
+	assert.Equal(t, StatusCritical, report.Status)
+	assert.Equal(t, 100-2*25-4*10, report.Score)
+
+	dead := report.Findings[2]
+	assert.Equal(t, SeverityWarning, dead.Severity)
+	require.NotNil(t, dead.Value)
+	assert.InDelta(t, 40.0, *dead.Value, 0.01)
+	assert.Equal(t, []string{
+		"public.events: 40.0% dead (40000 dead, 60000 live tuples)",
+		"public.audit: 33.3% dead (50000 dead, 100000 live tuples)",
+	}, dead.Evidence)
+
+	stale := report.Findings[0]
+	assert.Nil(t, stale.Value, "a never vacuumed table has no age")
+	assert.Equal(t, []string{
+		"public.audit: never vacuumed, 50000 dead tuples",
+		"public.events: last vacuumed 48 hours ago, 40000 dead tuples",
+	}, stale.Evidence)
+}
+
+func TestEvaluate_SkipsDisabledRulesAndMissingFacts(t *testing.T) {
+	facts := healthyFacts()
+	facts.Checkpoints = nil
+	facts.Errors[CheckCheckpoints] = "permission denied"
+	facts.Database.Deadlocks = 500
+
+	rules := DefaultRules()
+	require.NoError(t, rules.Disable(RuleDeadlocks))
+
+	report := Evaluate(facts, rules, now)
+
+	assert.Empty(t, report.Findings)
+	assert.Equal(t, []string{RuleRequestedCheckpoints, RuleDeadlocks}, report.Skipped)
+	assert.Equal(t, []string{"checkpoints_stats: permission denied"}, report.Unavailable)
+}
+
+func TestRun_CollectsChecksAndFailsOnlyWhenAllFail(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("connection refused")
+
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetDatabaseOverview", mock.Anything, "shop").Return(&pg.DatabaseOverview{}, nil).Once()
+	client.On("GetCacheHitRateDB", mock.Anything, "shop").Return(nil, failure).Once()
+	client.On("GetConnectionStats", mock.Anything).Return(&pg.ConnectionSummary{TotalConnections: 1, MaxConnections: 100}, nil).Once()
+	client.On("GetCheckpointsStats", mock.Anything).Return(&pg.CheckpointsStats{}, nil).Once()
+	client.On("GetTablesInfoDB", mock.Anything, "shop", TablesLimit).Return([]pg.TableInfo{}, nil).Once()
+
+	report, err := Run(ctx, client, "shop", DefaultRules())
+
+	require.NoError(t, err)
+	assert.Equal(t, "shop", report.Database)
+	assert.Equal(t, 100, report.Score)
+	assert.Equal(t, []string{"cache_hit_rate: connection refused"}, report.Unavailable)
+	assert.Equal(t, []string{RuleCacheHitRate, RuleRequestedCheckpoints}, report.Skipped)
+
+	client.On("GetDatabaseOverview", mock.Anything, "shop").Return(nil, failure)
+	client.On("GetCacheHitRateDB", mock.Anything, "shop").Return(nil, failure)
+	client.On("GetConnectionStats", mock.Anything).Return(nil, failure)
+	client.On("GetCheckpointsStats", mock.Anything).Return(nil, failure)
+	client.On("GetTablesInfoDB", mock.Anything, "shop", TablesLimit).Return(nil, failure)
+
+	_, err = Run(ctx, client, "shop", DefaultRules())
+
+	assert.ErrorIs(t, err, failure)
+}
+
+func TestCollect_RunsChecksConcurrentlyWithinTheCap(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		running, peak int
+	)
+	track := func(mock.Arguments) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}
+
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetDatabaseOverview", mock.Anything, "shop").Run(track).Return(&pg.DatabaseOverview{}, nil)
+	client.On("GetCacheHitRateDB", mock.Anything, "shop").Run(track).Return(&pg.CacheHitRate{}, nil)
+	client.On("GetConnectionStats", mock.Anything).Run(track).Return(&pg.ConnectionSummary{}, nil)
+	client.On("GetCheckpointsStats", mock.Anything).Run(track).Return(&pg.CheckpointsStats{}, nil)
+	client.On("GetTablesInfoDB", mock.Anything, "shop", TablesLimit).Run(track).Return([]pg.TableInfo{}, nil)
+
+	facts, err := Collect(context.Background(), client, "shop")
+
+	require.NoError(t, err)
+	assert.Empty(t, facts.Errors)
+	assert.Equal(t, maxConcurrentChecks, peak)
+}
@@ -0,0 +1,203 @@
+package healthreport
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Names of the rules, in the order they are evaluated
+const (
+	RuleCacheHitRate         = "cache_hit_rate"
+	RuleDeadTuples           = "dead_tuples"
+	RuleConnectionSaturation = "connection_saturation"
+	RuleRequestedCheckpoints = "requested_checkpoints"
+	RuleStaleAutovacuum      = "stale_autovacuum"
+	RuleDeadlocks            = "deadlocks"
+	RuleTempBytes            = "temp_bytes"
+)
+
+// ruleNames lists the rules in evaluation order
+var ruleNames = []string{
+	RuleCacheHitRate,
+	RuleDeadTuples,
+	RuleConnectionSaturation,
+	RuleRequestedCheckpoints,
+	RuleStaleAutovacuum,
+	RuleDeadlocks,
+	RuleTempBytes,
+}
+
+// lowerIsWorse marks the rules whose value is bad when it drops below the threshold
+var lowerIsWorse = map[string]bool{
+	RuleCacheHitRate: true,
+}
+
+// RuleNames returns the names of all rules
+func RuleNames() []string {
+	return append([]string(nil), ruleNames...)
+}
+
+// Threshold is the value at which a rule reports a warning and a critical finding.
+// Units depend on the rule:
+//
+//	cache_hit_rate         percent of block reads served from shared buffers (lower is worse)
+//	dead_tuples            percent of dead tuples in a table
+//	connection_saturation  percent of max_connections in use
+//	requested_checkpoints  percent of checkpoints requested instead of timed
+//	stale_autovacuum       hours since a table with dead tuples was last vacuumed
+//	deadlocks              deadlocks since the statistics reset
+//	temp_bytes             MB written to temp files since the statistics reset
+type Threshold struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+}
+
+// Rules configure the health report: thresholds per rule and the rules that are switched off
+type Rules struct {
+	Thresholds map[string]Threshold `json:"thresholds"`
+	Disabled   map[string]bool      `json:"disabled,omitempty"`
+}
+
+// DefaultRules returns the built-in thresholds with every rule enabled
+func DefaultRules() Rules {
+	return Rules{
+		Thresholds: map[string]Threshold{
+			RuleCacheHitRate:         {Warning: 99, Critical: 95},
+			RuleDeadTuples:           {Warning: 20, Critical: 50},
+			RuleConnectionSaturation: {Warning: 80, Critical: 95},
+			RuleRequestedCheckpoints: {Warning: 20, Critical: 50},
+			RuleStaleAutovacuum:      {Warning: 24, Critical: 168},
+			RuleDeadlocks:            {Warning: 1, Critical: 100},
+			RuleTempBytes:            {Warning: 1024, Critical: 10240},
+		},
+		Disabled: map[string]bool{},
+	}
+}
+
+// LoadRulesFromEnv loads the rules from environment variables:
+// HEALTH_RULES is a comma-separated list of rule=warning:critical entries that override
+// the defaults and HEALTH_DISABLED_RULES a comma-separated list of rules to skip.
+// Malformed entries are skipped with a log message.
+func LoadRulesFromEnv() Rules {
+	rules := DefaultRules()
+
+	if entries := os.Getenv("HEALTH_RULES"); entries != "" {
+		for _, entry := range strings.Split(entries, ",") {
+			name, threshold, err := parseRuleEntry(strings.TrimSpace(entry))
+			if err == nil {
+				err = rules.Override(name, threshold)
+			}
+			if err != nil {
+				log.Printf("Ignoring HEALTH_RULES entry: %v", err)
+			}
+		}
+	}
+
+	if disabled := os.Getenv("HEALTH_DISABLED_RULES"); disabled != "" {
+		for _, name := range strings.Split(disabled, ",") {
+			if err := rules.Disable(strings.TrimSpace(name)); err != nil {
+				log.Printf("Ignoring HEALTH_DISABLED_RULES entry: %v", err)
+			}
+		}
+	}
+
+	return rules
+}
+
+func parseRuleEntry(entry string) (string, Threshold, error) {
+	name, values, found := strings.Cut(entry, "=")
+	warning, critical, separated := strings.Cut(values, ":")
+	if !found || !separated {
+		return "", Threshold{}, fmt.Errorf("expected rule=warning:critical, got %q", entry)
+	}
+
+	w, err := strconv.ParseFloat(warning, 64)
+	if err != nil {
+		return "", Threshold{}, fmt.Errorf("rule %s: invalid warning threshold %q", name, warning)
+	}
+	c, err := strconv.ParseFloat(critical, 64)
+	if err != nil {
+		return "", Threshold{}, fmt.Errorf("rule %s: invalid critical threshold %q", name, critical)
+	}
+
+	return name, Threshold{Warning: w, Critical: c}, nil
+}
+
+// Clone returns a copy of the rules that can be changed without affecting the original
+func (r Rules) Clone() Rules {
+	clone := Rules{
+		Thresholds: make(map[string]Threshold, len(r.Thresholds)),
+		Disabled:   make(map[string]bool, len(r.Disabled)),
+	}
+	for name, threshold := range r.Thresholds {
+		clone.Thresholds[name] = threshold
+	}
+	for name, disabled := range r.Disabled {
+		clone.Disabled[name] = disabled
+	}
+	return clone
+}
+
+// Override replaces the threshold of a rule. The critical threshold must not be
+// less severe than the warning one.
+func (r *Rules) Override(name string, threshold Threshold) error {
+	if err := validateRule(name); err != nil {
+		return err
+	}
+	if lowerIsWorse[name] && threshold.Critical > threshold.Warning {
+		return fmt.Errorf("rule %s: critical threshold %g must not be above warning threshold %g", name, threshold.Critical, threshold.Warning)
+	}
+	if !lowerIsWorse[name] && threshold.Critical < threshold.Warning {
+		return fmt.Errorf("rule %s: critical threshold %g must not be below warning threshold %g", name, threshold.Critical, threshold.Warning)
+	}
+
+	if r.Thresholds == nil {
+		r.Thresholds = map[string]Threshold{}
+	}
+	r.Thresholds[name] = threshold
+	return nil
+}
+
+// Disable switches a rule off
+func (r *Rules) Disable(name string) error {
+	if err := validateRule(name); err != nil {
+		return err
+	}
+	if r.Disabled == nil {
+		r.Disabled = map[string]bool{}
+	}
+	r.Disabled[name] = true
+	return nil
+}
+
+// DisabledRules returns the names of the disabled rules in evaluation order
+func (r Rules) DisabledRules() []string {
+	disabled := []string{}
+	for _, name := range ruleNames {
+		if r.Disabled[name] {
+			disabled = append(disabled, name)
+		}
+	}
+	return disabled
+}
+
+func (r Rules) enabled(name string) bool {
+	_, configured := r.Thresholds[name]
+	return configured && !r.Disabled[name]
+}
+
+func validateRule(name string) error {
+	for _, known := range ruleNames {
+		if name == known {
+			return nil
+		}
+	}
+
+	names := RuleNames()
+	sort.Strings(names)
+	return fmt.Errorf("unknown rule %q (expected one of %s)", name, strings.Join(names, ", "))
+}
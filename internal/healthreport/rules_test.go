@@ -0,0 +1,31 @@
+package healthreport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRulesFromEnv(t *testing.T) {
+	t.Setenv("HEALTH_RULES", "cache_hit_rate=98:90, dead_tuples=30:60,unknown=1:2,deadlocks=10:5,temp_bytes=oops")
+	t.Setenv("HEALTH_DISABLED_RULES", "temp_bytes,bogus")
+
+	rules := LoadRulesFromEnv()
+
+	assert.Equal(t, Threshold{Warning: 98, Critical: 90}, rules.Thresholds[RuleCacheHitRate])
+	assert.Equal(t, Threshold{Warning: 30, Critical: 60}, rules.Thresholds[RuleDeadTuples])
+	assert.Equal(t, DefaultRules().Thresholds[RuleDeadlocks], rules.Thresholds[RuleDeadlocks], "critical below warning is rejected")
+	assert.Equal(t, []string{RuleTempBytes}, rules.DisabledRules())
+}
+
+func TestRules_OverrideValidatesDirection(t *testing.T) {
+	rules := DefaultRules()
+
+	assert.Error(t, rules.Override(RuleCacheHitRate, Threshold{Warning: 95, Critical: 99}))
+	assert.NoError(t, rules.Override(RuleConnectionSaturation, Threshold{Warning: 70, Critical: 90}))
+	assert.ErrorContains(t, rules.Disable("cache"), `unknown rule "cache"`)
+
+	clone := rules.Clone()
+	assert.NoError(t, clone.Disable(RuleDeadlocks))
+	assert.Empty(t, rules.DisabledRules())
+}
@@ -2,6 +2,7 @@ package instance_manager
 
 import (
 	"context"
+	"sync"
 
 	"psql-mcp-registry/internal/model"
 )
@@ -10,8 +11,12 @@ type Manager interface {
 	RegisterInstance(ctx context.Context, instance model.Instance) error
 	GetInstance(ctx context.Context, instanceName string) (*model.Instance, error)
 	ListInstances(ctx context.Context) ([]model.Instance, error)
+	OnRegister(listener Listener)
 }
 
+// Listener is called after an instance has been registered and stored
+type Listener func(instance model.Instance)
+
 //go:generate mockery --case snake --name Storage
 type Storage interface {
 	CreateInstance(ctx context.Context, instance *model.Instance) error
@@ -25,8 +30,10 @@ type InstanceRegistry interface {
 }
 
 type Implementation struct {
-	storage  Storage
-	registry InstanceRegistry
+	storage   Storage
+	registry  InstanceRegistry
+	listeners []Listener
+	mu        sync.RWMutex
 }
 
 func NewManager(storage Storage, registry InstanceRegistry) Manager {
@@ -35,3 +42,21 @@ func NewManager(storage Storage, registry InstanceRegistry) Manager {
 		registry: registry,
 	}
 }
+
+// OnRegister subscribes a listener to instance registrations
+func (i *Implementation) OnRegister(listener Listener) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.listeners = append(i.listeners, listener)
+}
+
+func (i *Implementation) notifyRegistered(instance model.Instance) {
+	i.mu.RLock()
+	listeners := append([]Listener(nil), i.listeners...)
+	i.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(instance)
+	}
+}
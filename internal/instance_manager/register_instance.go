@@ -3,15 +3,24 @@ package instance_manager
 import (
 	"context"
 	"errors"
+	"regexp"
 
 	"psql-mcp-registry/internal/model"
 )
 
 var (
 	ErrInstanceAlreadyExists = errors.New("instance already exists")
+	ErrInvalidInstanceName   = errors.New("instance name may contain only letters, digits, '.', '_' and '-'")
 )
 
+// validInstanceName keeps names usable as the host of the instances:// resource URIs
+var validInstanceName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
 func (i *Implementation) RegisterInstance(ctx context.Context, instance model.Instance) error {
+	if !validInstanceName.MatchString(instance.Name) {
+		return ErrInvalidInstanceName
+	}
+
 	_, err := i.storage.GetInstanceByName(ctx, instance.Name)
 	if err == nil {
 		return ErrInstanceAlreadyExists
@@ -27,5 +36,7 @@ func (i *Implementation) RegisterInstance(ctx context.Context, instance model.In
 		return err
 	}
 
+	i.notifyRegistered(instance)
+
 	return nil
 }
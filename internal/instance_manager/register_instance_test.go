@@ -38,3 +38,73 @@ func TestRegisterInstance_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestRegisterInstance_NotifiesListeners(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := mocks.NewStorage(t)
+	mockRegistry := mocks.NewInstanceRegistry(t)
+
+	impl := &Implementation{
+		storage:  mockStorage,
+		registry: mockRegistry,
+	}
+
+	instance := model.Instance{
+		Name:         "test-instance",
+		DatabaseName: "test_db",
+		Status:       "active",
+	}
+
+	mockStorage.On("GetInstanceByName", ctx, instance.Name).Return(nil, errors.New("not found"))
+	mockStorage.On("CreateInstance", ctx, &instance).Return(nil)
+	mockRegistry.On("AddInstanceToRegistry", instance).Return(nil)
+
+	var notified []model.Instance
+	impl.OnRegister(func(registered model.Instance) {
+		notified = append(notified, registered)
+	})
+
+	err := impl.RegisterInstance(ctx, instance)
+
+	assert.NoError(t, err)
+	assert.Len(t, notified, 1)
+	assert.Equal(t, instance.Name, notified[0].Name)
+}
+
+func TestRegisterInstance_RegistryErrorDoesNotNotify(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := mocks.NewStorage(t)
+	mockRegistry := mocks.NewInstanceRegistry(t)
+
+	impl := &Implementation{
+		storage:  mockStorage,
+		registry: mockRegistry,
+	}
+
+	instance := model.Instance{Name: "unreachable-instance"}
+
+	mockStorage.On("GetInstanceByName", ctx, instance.Name).Return(nil, errors.New("not found"))
+	mockRegistry.On("AddInstanceToRegistry", instance).Return(errors.New("connection refused"))
+
+	notified := false
+	impl.OnRegister(func(model.Instance) {
+		notified = true
+	})
+
+	err := impl.RegisterInstance(ctx, instance)
+
+	assert.Error(t, err)
+	assert.False(t, notified)
+}
+
+func TestRegisterInstance_RejectsNamesInvalidInURIs(t *testing.T) {
+	impl := &Implementation{
+		storage:  mocks.NewStorage(t),
+		registry: mocks.NewInstanceRegistry(t),
+	}
+
+	for _, name := range []string{"", "prod db", "db:5432", "a/b", "50%", "-prod"} {
+		err := impl.RegisterInstance(context.Background(), model.Instance{Name: name, DatabaseName: "db"})
+		assert.ErrorIs(t, err, ErrInvalidInstanceName, name)
+	}
+}
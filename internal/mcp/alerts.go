@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/alerting"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/router"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func (s *MCPServer) handleSaveAlertRule(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SaveAlertRuleInput,
+) (*mcp.CallToolResult, *SaveAlertRuleOutput, error) {
+	if err := s.checkAlertRole(req, model.ActionNameSaveAlertRule); err != nil {
+		return nil, nil, err
+	}
+
+	// Rules may use the input names of the tools; the router reads its own parameter names
+	params, err := router.NormalizeParameters(input.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rule := &model.AlertRule{
+		Name:        input.Name,
+		Description: input.Description,
+		Action:      model.ActionName(input.Action),
+		Params:      params,
+		Expression:  input.Expression,
+		Severity:    input.Severity,
+		Selector:    input.Labels,
+		Enabled:     !input.Disabled,
+		CreatedBy:   s.principal(req).User,
+	}
+	if rule.Severity == "" {
+		rule.Severity = model.AlertSeverityWarning
+	}
+	if input.For != "" {
+		duration, err := time.ParseDuration(input.For)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid for %q: %w", input.For, err)
+		}
+		rule.For = duration
+	}
+	if err := alerting.ValidateRule(rule); err != nil {
+		return nil, nil, err
+	}
+
+	// The preview refuses field names missing from the action output and actions that fail
+	// on a reachable instance before the rule is stored
+	preview, err := s.previewAlertRule(ctx, rule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.alerts.SaveAlertRule(ctx, rule); err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&SaveAlertRuleOutput{AlertRule: alertRuleOutput(*rule), Preview: preview})
+}
+
+func (s *MCPServer) previewAlertRule(ctx context.Context, rule *model.AlertRule) ([]AlertPreview, error) {
+	preview := []AlertPreview{}
+
+	instances, err := s.selectInstances(ctx, nil, rule.Selector)
+	if err != nil {
+		// A rule may be stored before any instance carries its labels
+		return preview, nil
+	}
+
+	expression, err := alerting.ParseExpression(rule.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := s.router.RouteAll(ctx, router.QueryRequest{Action: rule.Action, Parameters: rule.Params}, instances)
+	for _, response := range responses {
+		result := AlertPreview{Instance: response.Instance}
+		if !response.Success {
+			if !router.IsUnavailable(response.Err) {
+				return nil, fmt.Errorf("action %s failed on %s: %s", rule.Action, response.Instance, response.Error)
+			}
+			// An unreachable instance says nothing about the rule
+			result.Error = response.Error
+		} else if result.Matched, result.Value, err = alerting.Match(expression, response.Data); err != nil {
+			return nil, fmt.Errorf("expression on %s: %w", response.Instance, err)
+		}
+		preview = append(preview, result)
+	}
+	return preview, nil
+}
+
+func (s *MCPServer) handleAlertRules(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input AlertRulesInput,
+) (*mcp.CallToolResult, *AlertRulesOutput, error) {
+	if s.alerts == nil {
+		return nil, nil, fmt.Errorf("alerting is not configured")
+	}
+
+	rules, err := s.alerts.ListAlertRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &AlertRulesOutput{Rules: make([]AlertRule, 0, len(rules))}
+	for _, rule := range rules {
+		output.Rules = append(output.Rules, alertRuleOutput(rule))
+	}
+
+	return toolResult(output)
+}
+
+// Order of alert states in the alerts listing
+var alertStateOrder = map[string]int{
+	model.AlertStateFiring:   0,
+	model.AlertStatePending:  1,
+	model.AlertStateResolved: 2,
+}
+
+func (s *MCPServer) handleAlerts(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input AlertsInput,
+) (*mcp.CallToolResult, *AlertsOutput, error) {
+	if s.alerts == nil {
+		return nil, nil, fmt.Errorf("alerting is not configured")
+	}
+	if _, known := alertStateOrder[input.State]; input.State != "" && !known {
+		return nil, nil, fmt.Errorf("unknown state %q, expected pending, firing or resolved", input.State)
+	}
+
+	rules, err := s.alerts.ListAlertRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	states, err := s.alerts.ListAlertStates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	silences, err := s.alerts.ListActiveSilences(ctx, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	severities := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		severities[rule.Name] = rule.Severity
+	}
+
+	output := &AlertsOutput{Alerts: []Alert{}, Silences: make([]AlertSilence, 0, len(silences))}
+	for _, state := range states {
+		if input.State != "" && state.State != input.State {
+			continue
+		}
+
+		alert := Alert{
+			Rule:        state.RuleName,
+			Severity:    severities[state.RuleName],
+			Instance:    state.InstanceName,
+			State:       state.State,
+			Value:       state.Value,
+			ActiveSince: state.ActiveSince,
+			FiringSince: state.FiringSince,
+			ResolvedAt:  state.ResolvedAt,
+			EvaluatedAt: state.EvaluatedAt,
+			Notified:    state.Notified,
+		}
+		for _, silence := range silences {
+			if silence.Matches(state.RuleName, state.InstanceName, now) {
+				alert.Silenced = true
+				break
+			}
+		}
+		if alert.State == model.AlertStateFiring {
+			output.Firing++
+		}
+		output.Alerts = append(output.Alerts, alert)
+	}
+	sort.SliceStable(output.Alerts, func(i, j int) bool {
+		return alertStateOrder[output.Alerts[i].State] < alertStateOrder[output.Alerts[j].State]
+	})
+
+	for _, silence := range silences {
+		output.Silences = append(output.Silences, alertSilenceOutput(silence))
+	}
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleSilenceAlert(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SilenceAlertInput,
+) (*mcp.CallToolResult, *SilenceAlertOutput, error) {
+	if err := s.checkAlertRole(req, model.ActionNameSilenceAlert); err != nil {
+		return nil, nil, err
+	}
+	if input.Rule == "" && input.InstanceName == "" {
+		return nil, nil, fmt.Errorf("rule or instance_name is required")
+	}
+	duration, err := time.ParseDuration(input.Duration)
+	if err != nil || duration <= 0 {
+		return nil, nil, fmt.Errorf("invalid duration %q, expected a positive duration such as 2h", input.Duration)
+	}
+
+	now := time.Now()
+	silence := &model.AlertSilence{
+		RuleName:     input.Rule,
+		InstanceName: input.InstanceName,
+		StartsAt:     now,
+		EndsAt:       now.Add(duration),
+		Comment:      input.Comment,
+		CreatedBy:    s.principal(req).User,
+	}
+	if err := s.alerts.SaveAlertSilence(ctx, silence); err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&SilenceAlertOutput{AlertSilence: alertSilenceOutput(*silence)})
+}
+
+// checkAlertRole refuses callers that may not change alerting configuration
+func (s *MCPServer) checkAlertRole(req *mcp.CallToolRequest, action model.ActionName) error {
+	principal := s.principal(req)
+	if !principal.Can(action) {
+		return fmt.Errorf("user %s with role %s may not run %s, role %s is required",
+			principal.User, principal.Role, action, access.RequiredRole(action))
+	}
+	if s.alerts == nil {
+		return fmt.Errorf("alerting is not configured")
+	}
+	return nil
+}
+
+func alertRuleOutput(rule model.AlertRule) AlertRule {
+	return AlertRule{
+		Name:        rule.Name,
+		Description: rule.Description,
+		Action:      string(rule.Action),
+		Params:      rule.Params,
+		Expression:  rule.Expression,
+		For:         rule.For.String(),
+		Severity:    rule.Severity,
+		Labels:      rule.Selector,
+		Enabled:     rule.Enabled,
+		CreatedBy:   rule.CreatedBy,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+}
+
+func alertSilenceOutput(silence model.AlertSilence) AlertSilence {
+	return AlertSilence{
+		ID:        silence.ID,
+		Rule:      silence.RuleName,
+		Instance:  silence.InstanceName,
+		StartsAt:  silence.StartsAt,
+		EndsAt:    silence.EndsAt,
+		Comment:   silence.Comment,
+		CreatedBy: silence.CreatedBy,
+	}
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Statuses of cancel_backend and terminate_backend
+const (
+	backendStatusConfirmationRequired = "confirmation_required"
+	backendStatusCancelled            = "cancelled"
+	backendStatusTerminated           = "terminated"
+	backendStatusNotSignalled         = "not_signalled"
+)
+
+func (s *MCPServer) handleCancelBackend(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BackendActionInput,
+) (*mcp.CallToolResult, *BackendActionOutput, error) {
+	return s.signalBackend(ctx, req, input, model.ActionNameCancelBackend)
+}
+
+func (s *MCPServer) handleTerminateBackend(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BackendActionInput,
+) (*mcp.CallToolResult, *BackendActionOutput, error) {
+	return s.signalBackend(ctx, req, input, model.ActionNameTerminateBackend)
+}
+
+// signalBackend runs the two steps of a backend action. Without a confirm token it
+// checks the role and the backend and issues a token; with one it consumes the token
+// and signals the backend, which the router re-checks in the same statement.
+// Every step is written to the audit log; a token is only issued once its record is stored.
+func (s *MCPServer) signalBackend(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BackendActionInput,
+	action model.ActionName,
+) (*mcp.CallToolResult, *BackendActionOutput, error) {
+	principal := s.principal(req)
+
+	queryStart, err := time.Parse(time.RFC3339Nano, input.QueryStart)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query_start must be an RFC 3339 timestamp as reported by active_queries: %w", err)
+	}
+
+	record := &model.AuditRecord{
+		InstanceName: input.InstanceName,
+		Action:       action,
+		Username:     principal.User,
+		Role:         string(principal.Role),
+		PID:          input.Pid,
+		QueryStart:   &queryStart,
+	}
+
+	if !principal.Can(action) {
+		return nil, nil, s.refuseBackendAction(ctx, record, fmt.Errorf("user %s with role %s may not run %s, role %s is required",
+			principal.User, principal.Role, action, access.RequiredRole(action)))
+	}
+
+	intent := access.Intent{
+		Instance:   input.InstanceName,
+		Action:     action,
+		PID:        input.Pid,
+		QueryStart: queryStart,
+		User:       principal.User,
+	}
+
+	output := &BackendActionOutput{
+		Instance:    input.InstanceName,
+		Action:      string(action),
+		RequestedBy: principal.User,
+	}
+
+	if input.ConfirmToken == "" {
+		data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameBackendInfo, map[string]interface{}{
+			"pid": input.Pid,
+		})
+		if err != nil {
+			return nil, nil, s.refuseBackendAction(ctx, record, err)
+		}
+
+		backend, err := routerData[*pg.Backend](data)
+		if err != nil {
+			return nil, nil, err
+		}
+		record.TargetUser = backend.Username.String
+		record.TargetQuery = backend.Query.String
+
+		if err := checkBackendTarget(backend, queryStart); err != nil {
+			return nil, nil, s.refuseBackendAction(ctx, record, err)
+		}
+
+		token, expiresAt, err := s.confirmations.Issue(intent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to issue confirm token: %w", err)
+		}
+
+		record.Outcome = model.AuditOutcomeConfirmationRequired
+		if err := s.recordAudit(ctx, record); err != nil {
+			return nil, nil, fmt.Errorf("refusing %s, the audit record could not be written: %w", action, err)
+		}
+
+		output.Status = backendStatusConfirmationRequired
+		output.ConfirmToken = token
+		output.ExpiresAt = pg.NewNullTime(expiresAt)
+		output.Backend = backend
+		return toolResult(output)
+	}
+
+	if err := s.confirmations.Consume(input.ConfirmToken, intent); err != nil {
+		return nil, nil, s.refuseBackendAction(ctx, record, err)
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, action, map[string]interface{}{
+		"pid":        input.Pid,
+		"queryStart": queryStart,
+	})
+	if err != nil {
+		record.Outcome = model.AuditOutcomeFailed
+		record.Detail = err.Error()
+		s.logAuditFailure(s.recordAudit(ctx, record))
+		return nil, nil, err
+	}
+
+	signalled, err := routerData[bool](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output.Status = backendStatusNotSignalled
+	if signalled && action == model.ActionNameTerminateBackend {
+		output.Status = backendStatusTerminated
+	} else if signalled {
+		output.Status = backendStatusCancelled
+	}
+
+	record.Outcome = model.AuditOutcomeExecuted
+	record.Detail = output.Status
+	s.logAuditFailure(s.recordAudit(ctx, record))
+
+	return toolResult(output)
+}
+
+// checkBackendTarget refuses backends that may not be signalled through MCP and
+// backends whose current query is not the one the caller observed
+func checkBackendTarget(backend *pg.Backend, queryStart time.Time) error {
+	if !backend.QueryStart.Valid || !backend.QueryStart.Time.Equal(queryStart) {
+		return fmt.Errorf("backend %d query_start does not match: it runs another query now or the pid was reused", backend.PID)
+	}
+	if backend.Superuser {
+		return fmt.Errorf("backend %d belongs to a superuser and cannot be signalled", backend.PID)
+	}
+	if !backend.ClientBackend {
+		return fmt.Errorf("backend %d is a replication or system process (%s) and cannot be signalled",
+			backend.PID, formatOptionalString(backend.BackendType))
+	}
+	return nil
+}
+
+// refuseBackendAction records the refusal and returns the reason
+func (s *MCPServer) refuseBackendAction(ctx context.Context, record *model.AuditRecord, reason error) error {
+	record.Outcome = model.AuditOutcomeRefused
+	record.Detail = reason.Error()
+	s.logAuditFailure(s.recordAudit(ctx, record))
+	return reason
+}
+
+func (s *MCPServer) recordAudit(ctx context.Context, record *model.AuditRecord) error {
+	if s.audit == nil {
+		return fmt.Errorf("audit log is not configured")
+	}
+	return s.audit.RecordAudit(ctx, record)
+}
+
+func (s *MCPServer) logAuditFailure(err error) {
+	if err != nil {
+		log.Printf("Failed to write audit record: %v", err)
+	}
+}
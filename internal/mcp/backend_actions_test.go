@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"psql-mcp-registry/internal/pg"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBackendTarget_RequiresTheExactQueryStart(t *testing.T) {
+	observed := time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC)
+	backend := &pg.Backend{PID: 42, QueryStart: pg.NewNullTime(observed), ClientBackend: true}
+
+	assert.NoError(t, checkBackendTarget(backend, observed.In(time.FixedZone("CEST", 2*60*60))))
+
+	backend.QueryStart = pg.NewNullTime(observed.Add(time.Microsecond))
+	assert.ErrorContains(t, checkBackendTarget(backend, observed), "query_start does not match",
+		"a new query started within the same second")
+
+	backend.QueryStart = pg.NullTime{}
+	assert.Error(t, checkBackendTarget(backend, observed))
+
+	backend.QueryStart = pg.NewNullTime(observed)
+	backend.Superuser = true
+	assert.ErrorContains(t, checkBackendTarget(backend, observed), "superuser")
+}
@@ -4,8 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/healthreport"
 	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/schemadiff"
+	"psql-mcp-registry/internal/settingsdrift"
+	"psql-mcp-registry/internal/tuning"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -14,7 +22,7 @@ func (s *MCPServer) handleDatabaseOverview(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input DatabaseOverviewInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *DatabaseOverviewOutput, error) {
 	params := make(map[string]interface{})
 	if input.DbName != "" {
 		params["dbName"] = input.DbName
@@ -25,14 +33,23 @@ func (s *MCPServer) handleDatabaseOverview(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	overview, err := routerData[*pg.DatabaseOverview](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&DatabaseOverviewOutput{
+		Instance: input.InstanceName,
+		Database: dbNameOrDefault(input.DbName),
+		Overview: *overview,
+	})
 }
 
 func (s *MCPServer) handleCacheHitRate(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input CacheHitRateInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *CacheHitRateOutput, error) {
 	params := make(map[string]interface{})
 	if input.DbName != "" {
 		params["dbName"] = input.DbName
@@ -43,40 +60,82 @@ func (s *MCPServer) handleCacheHitRate(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	rate, err := routerData[*pg.CacheHitRate](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&CacheHitRateOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		HitRate:  rate.HitRate,
+	})
 }
 
 func (s *MCPServer) handleCheckpointsStats(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input CheckpointsStatsInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *CheckpointsStatsOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameCheckpointsStats, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	stats, err := routerData[*pg.CheckpointsStats](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&CheckpointsStatsOutput{Instance: input.InstanceName, CheckpointsStats: *stats})
 }
 
 func (s *MCPServer) handleWalActivity(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input WalActivityInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *WalActivityOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameWalActivity, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	stats, err := routerData[*pg.WalActivity](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&WalActivityOutput{Instance: input.InstanceName, WalActivity: *stats})
+}
+
+func (s *MCPServer) handleIOStats(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input IOStatsInput,
+) (*mcp.CallToolResult, *IOStatsOutput, error) {
+	params := make(map[string]interface{})
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameIOStats, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := routerData[*pg.IOReport](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&IOStatsOutput{Instance: input.InstanceName, IOReport: *report})
 }
 
 func (s *MCPServer) handleTablesInfo(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input TablesInfoInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *TablesInfoOutput, error) {
 	params := make(map[string]interface{})
 	if input.Limit > 0 {
 		params["limit"] = input.Limit
@@ -87,14 +146,22 @@ func (s *MCPServer) handleTablesInfo(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	tables, err := routerData[[]pg.TableInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&TablesInfoOutput{
+		Instance: input.InstanceName,
+		Tables:   nonNil(tables),
+	})
 }
 
 func (s *MCPServer) handleLockingInfo(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input LockingInfoInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *LockingInfoOutput, error) {
 	params := make(map[string]interface{})
 	if input.DbName != "" {
 		params["dbName"] = input.DbName
@@ -105,33 +172,58 @@ func (s *MCPServer) handleLockingInfo(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	locks, err := routerData[[]pg.LockInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&LockingInfoOutput{
+		Instance: input.InstanceName,
+		Database: dbNameOrDefault(input.DbName),
+		Locks:    nonNil(locks),
+	})
 }
 
 func (s *MCPServer) handleChangedSettings(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input ChangedSettingsInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *ChangedSettingsOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameChangedSettings, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	settings, err := routerData[[]pg.SettingInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&ChangedSettingsOutput{
+		Instance: input.InstanceName,
+		Settings: nonNil(settings),
+	})
 }
 
 func (s *MCPServer) handleVersion(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input VersionInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *VersionOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameVersion, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	version, err := routerData[*pg.Version](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&VersionOutput{
+		Instance: input.InstanceName,
+		Version:  *version,
+	})
 }
 
 func (s *MCPServer) handleListInstancesResource(
@@ -149,6 +241,7 @@ func (s *MCPServer) handleListInstancesResource(
 			"database_name": inst.DatabaseName,
 			"description":   inst.Description,
 			"status":        inst.Status,
+			"labels":        inst.Labels,
 			"created_at":    inst.CreatedAt,
 			"updated_at":    inst.UpdatedAt,
 		})
@@ -177,7 +270,7 @@ func (s *MCPServer) handleIndexStats(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input IndexStatsInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *IndexStatsOutput, error) {
 	params := make(map[string]interface{})
 	if input.Limit > 0 {
 		params["limit"] = input.Limit
@@ -188,14 +281,22 @@ func (s *MCPServer) handleIndexStats(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	indexes, err := routerData[[]pg.IndexStats](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&IndexStatsOutput{
+		Instance: input.InstanceName,
+		Indexes:  nonNil(indexes),
+	})
 }
 
 func (s *MCPServer) handleActiveQueries(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input ActiveQueriesInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *ActiveQueriesOutput, error) {
 	params := make(map[string]interface{})
 	if input.DbName != "" {
 		params["dbName"] = input.DbName
@@ -209,27 +310,50 @@ func (s *MCPServer) handleActiveQueries(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	queries, err := routerData[[]pg.ActiveQuery](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minDuration := input.MinDurationSeconds
+	if minDuration <= 0 {
+		minDuration = defaultMinDurationSeconds
+	}
+
+	return toolResult(&ActiveQueriesOutput{
+		Instance:           input.InstanceName,
+		Database:           dbNameOrDefault(input.DbName),
+		MinDurationSeconds: minDuration,
+		Queries:            nonNil(queries),
+	})
 }
 
 func (s *MCPServer) handleConnectionStats(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input ConnectionStatsInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *ConnectionStatsOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameConnectionStats, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	connections, err := routerData[*pg.ConnectionSummary](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&ConnectionStatsOutput{
+		Instance:    input.InstanceName,
+		Connections: *connections,
+	})
 }
 
 func (s *MCPServer) handleSlowQueries(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input SlowQueriesInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *SlowQueriesOutput, error) {
 	params := make(map[string]interface{})
 	if input.Limit > 0 {
 		params["limit"] = input.Limit
@@ -240,18 +364,919 @@ func (s *MCPServer) handleSlowQueries(
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	queries, err := routerData[[]pg.SlowQuery](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&SlowQueriesOutput{
+		Instance: input.InstanceName,
+		Queries:  nonNil(queries),
+	})
+}
+
+func (s *MCPServer) handleTopStatements(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TopStatementsInput,
+) (*mcp.CallToolResult, *TopStatementsOutput, error) {
+	params := map[string]interface{}{
+		"orderBy":  input.OrderBy,
+		"database": input.Database,
+		"user":     input.User,
+	}
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameTopStatements, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := routerData[*pg.StatementsReport](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&TopStatementsOutput{
+		Instance:         input.InstanceName,
+		StatementsReport: *report,
+	})
 }
 
 func (s *MCPServer) handleDatabaseSizes(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
 	input DatabaseSizesInput,
-) (*mcp.CallToolResult, interface{}, error) {
+) (*mcp.CallToolResult, *DatabaseSizesOutput, error) {
 	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameDatabaseSizes, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return nil, data, nil
+	databases, err := routerData[[]pg.DatabaseSize](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&DatabaseSizesOutput{
+		Instance:  input.InstanceName,
+		Databases: nonNil(databases),
+	})
+}
+
+func (s *MCPServer) handleInstanceHealth(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input InstanceHealthInput,
+) (*mcp.CallToolResult, *InstanceHealthOutput, error) {
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameInstanceHealth, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	health, err := routerData[*router.InstanceHealth](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&InstanceHealthOutput{InstanceHealth: *health})
+}
+
+func (s *MCPServer) handleReplicationStatus(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ReplicationStatusInput,
+) (*mcp.CallToolResult, *ReplicationStatusOutput, error) {
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameReplicationStatus, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, err := routerData[*pg.ReplicationStatus](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &ReplicationStatusOutput{Instance: input.InstanceName, ReplicationStatus: *status}
+	output.Replicas = nonNil(output.Replicas)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleReplicationSlots(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ReplicationSlotsInput,
+) (*mcp.CallToolResult, *ReplicationSlotsOutput, error) {
+	threshold := input.RetainedWalThresholdMB
+	if threshold <= 0 {
+		threshold = defaultRetainedWalThresholdMB
+	}
+	params := map[string]interface{}{"retainedWalThresholdMB": threshold}
+
+	responses, err := s.executeRouterFanOut(ctx, input.InstanceName, model.ActionNameReplicationSlots, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &ReplicationSlotsOutput{
+		RetainedWalThresholdMB: threshold,
+		Instances:              make([]InstanceReplicationSlots, 0, len(responses)),
+	}
+	for _, response := range responses {
+		result := InstanceReplicationSlots{Instance: response.Instance, Slots: []pg.ReplicationSlot{}}
+		if !response.Success {
+			result.Error = response.Error
+		} else if slots, err := routerData[[]pg.ReplicationSlot](response.Data); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Slots = nonNil(slots)
+		}
+
+		for _, slot := range result.Slots {
+			if slot.AtRisk {
+				output.AtRisk++
+			}
+		}
+		output.Instances = append(output.Instances, result)
+	}
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleXidWraparound(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input XidWraparoundInput,
+) (*mcp.CallToolResult, *XidWraparoundOutput, error) {
+	params := make(map[string]interface{})
+	if input.HoldersLimit > 0 {
+		params["holdersLimit"] = input.HoldersLimit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameXidWraparound, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wraparound, err := routerData[*pg.XidWraparound](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &XidWraparoundOutput{Instance: input.InstanceName, XidWraparound: *wraparound}
+	output.Databases = nonNil(output.Databases)
+	output.HorizonHolders = nonNil(output.HorizonHolders)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleTableFreezeAge(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TableFreezeAgeInput,
+) (*mcp.CallToolResult, *TableFreezeAgeOutput, error) {
+	params := make(map[string]interface{})
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameTableFreezeAge, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tables, err := routerData[[]pg.TableFreezeAge](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&TableFreezeAgeOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Tables:   nonNil(tables),
+	})
+}
+
+func (s *MCPServer) handleMaintenanceProgress(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input MaintenanceProgressInput,
+) (*mcp.CallToolResult, *MaintenanceProgressOutput, error) {
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameMaintenanceProgress, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operations, err := routerData[[]pg.MaintenanceProgress](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&MaintenanceProgressOutput{
+		Instance:   input.InstanceName,
+		Operations: nonNil(operations),
+	})
+}
+
+func (s *MCPServer) handleTableBloat(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TableBloatInput,
+) (*mcp.CallToolResult, *TableBloatOutput, error) {
+	params := map[string]interface{}{
+		"exact": input.Exact,
+	}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameTableBloat, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tables, err := routerData[[]pg.TableBloat](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&TableBloatOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Exact:    input.Exact,
+		Tables:   nonNil(tables),
+	})
+}
+
+func (s *MCPServer) handleIndexBloat(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input IndexBloatInput,
+) (*mcp.CallToolResult, *IndexBloatOutput, error) {
+	params := map[string]interface{}{
+		"exact": input.Exact,
+	}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameIndexBloat, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexes, err := routerData[[]pg.IndexBloat](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&IndexBloatOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Exact:    input.Exact,
+		Indexes:  nonNil(indexes),
+	})
+}
+
+func (s *MCPServer) handleIndexAdvisor(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input IndexAdvisorInput,
+) (*mcp.CallToolResult, *IndexAdvisorOutput, error) {
+	params := make(map[string]interface{})
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameIndexAdvisor, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	advice, err := routerData[*pg.IndexAdvice](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &IndexAdvisorOutput{
+		Instance:    input.InstanceName,
+		Database:    input.DbName,
+		IndexAdvice: *advice,
+	}
+	output.Findings = nonNil(output.Findings)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleLockGraph(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input LockGraphInput,
+) (*mcp.CallToolResult, *LockGraphOutput, error) {
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameLockGraph, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph, err := routerData[*pg.LockGraph](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &LockGraphOutput{
+		Instance:  input.InstanceName,
+		LockGraph: *graph,
+	}
+	output.Roots = nonNil(output.Roots)
+	output.Edges = nonNil(output.Edges)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleListSchemas(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListSchemasInput,
+) (*mcp.CallToolResult, *SchemasOutput, error) {
+	params := map[string]interface{}{}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameListSchemas, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schemas, err := routerData[[]pg.SchemaInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&SchemasOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Schemas:  nonNil(schemas),
+	})
+}
+
+func (s *MCPServer) handleListTables(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaPageInput,
+) (*mcp.CallToolResult, *SchemaTablesOutput, error) {
+	limit, offset, params := schemaPageParams(input)
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameListTables, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tables, err := routerData[[]pg.TableSchema](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &SchemaTablesOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Schema:   input.Schema,
+	}
+	output.Tables, output.NextOffset = schemaPage(nonNil(tables), limit, offset)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleDescribeTable(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input DescribeTableInput,
+) (*mcp.CallToolResult, *DescribeTableOutput, error) {
+	params := map[string]interface{}{
+		"table": input.Table,
+	}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	if input.Schema != "" {
+		params["schema"] = input.Schema
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameDescribeTable, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	detail, err := routerData[*pg.TableDetail](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&DescribeTableOutput{
+		Instance:    input.InstanceName,
+		Database:    input.DbName,
+		TableDetail: *detail,
+	})
+}
+
+func (s *MCPServer) handleListViews(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaPageInput,
+) (*mcp.CallToolResult, *SchemaViewsOutput, error) {
+	limit, offset, params := schemaPageParams(input)
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameListViews, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	views, err := routerData[[]pg.ViewInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &SchemaViewsOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Schema:   input.Schema,
+	}
+	output.Views, output.NextOffset = schemaPage(nonNil(views), limit, offset)
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleListFunctions(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaPageInput,
+) (*mcp.CallToolResult, *SchemaFunctionsOutput, error) {
+	limit, offset, params := schemaPageParams(input)
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameListFunctions, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	functions, err := routerData[[]pg.FunctionInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &SchemaFunctionsOutput{
+		Instance: input.InstanceName,
+		Database: input.DbName,
+		Schema:   input.Schema,
+	}
+	output.Functions, output.NextOffset = schemaPage(nonNil(functions), limit, offset)
+
+	return toolResult(output)
+}
+
+// Modes of schema_diff
+const (
+	schemaDiffModeChanges = "changes"
+	schemaDiffModeSummary = "summary"
+	schemaDiffModePatch   = "patch"
+)
+
+func (s *MCPServer) handleSchemaDiff(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaDiffInput,
+) (*mcp.CallToolResult, *SchemaDiffOutput, error) {
+	mode := input.Mode
+	if mode == "" {
+		mode = schemaDiffModeChanges
+	}
+	if mode != schemaDiffModeChanges && mode != schemaDiffModeSummary && mode != schemaDiffModePatch {
+		return nil, nil, fmt.Errorf("unknown mode %q, expected changes, summary or patch", mode)
+	}
+
+	// Both snapshots are read concurrently, each through the slots of its own instance
+	var source, target *pg.SchemaSnapshot
+	var sourceErr, targetErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		source, sourceErr = s.schemaSnapshot(ctx, input.SourceInstance, input.SourceDb, input.Schema)
+	}()
+	go func() {
+		defer wg.Done()
+		target, targetErr = s.schemaSnapshot(ctx, input.TargetInstance, input.TargetDb, input.Schema)
+	}()
+	wg.Wait()
+
+	if sourceErr != nil {
+		return nil, nil, fmt.Errorf("source %s: %w", input.SourceInstance, sourceErr)
+	}
+	if targetErr != nil {
+		return nil, nil, fmt.Errorf("target %s: %w", input.TargetInstance, targetErr)
+	}
+
+	changes := schemadiff.Diff(source, target)
+	output := &SchemaDiffOutput{
+		Source:  SchemaDiffSide{Instance: input.SourceInstance, Database: input.SourceDb},
+		Target:  SchemaDiffSide{Instance: input.TargetInstance, Database: input.TargetDb},
+		Schema:  input.Schema,
+		Mode:    mode,
+		Summary: schemadiff.Summarize(changes),
+		Changes: []schemadiff.Change{},
+	}
+	switch mode {
+	case schemaDiffModeChanges:
+		output.Changes = changes
+	case schemaDiffModePatch:
+		output.Patch = schemadiff.Patch(changes)
+	}
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) schemaSnapshot(ctx context.Context, instanceName, dbName, schema string) (*pg.SchemaSnapshot, error) {
+	params := map[string]interface{}{
+		"schema": schema,
+	}
+	if dbName != "" {
+		params["dbName"] = dbName
+	}
+
+	data, err := s.executeRouterQuery(ctx, instanceName, model.ActionNameSchemaSnapshot, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return routerData[*pg.SchemaSnapshot](data)
+}
+
+func (s *MCPServer) handleSettingsDrift(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SettingsDriftInput,
+) (*mcp.CallToolResult, *SettingsDriftOutput, error) {
+	instances, err := s.selectInstances(ctx, input.Instances, input.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var baseline map[string]string
+	if input.Baseline != "" {
+		if s.baselines == nil {
+			return nil, nil, fmt.Errorf("settings baselines are not configured")
+		}
+		stored, err := s.baselines.GetSettingsBaseline(ctx, input.Baseline)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get settings baseline %s: %w", input.Baseline, err)
+		}
+		baseline = stored.Settings
+	}
+
+	responses := s.router.RouteAll(ctx, createRouterRequest("", model.ActionNameSettings, nil), instances)
+
+	output := &SettingsDriftOutput{
+		Instances: []string{},
+		Baseline:  input.Baseline,
+		Errors:    []InstanceError{},
+	}
+	var compared []settingsdrift.Instance
+	for _, response := range responses {
+		if !response.Success {
+			output.Errors = append(output.Errors, InstanceError{Instance: response.Instance, Error: response.Error})
+			continue
+		}
+		settings, err := routerData[[]pg.SettingInfo](response.Data)
+		if err != nil {
+			output.Errors = append(output.Errors, InstanceError{Instance: response.Instance, Error: err.Error()})
+			continue
+		}
+		compared = append(compared, settingsdrift.Instance{Name: response.Instance, Settings: settings})
+		output.Instances = append(output.Instances, response.Instance)
+	}
+
+	if len(compared) < 2 && baseline == nil {
+		return nil, nil, fmt.Errorf("settings of %d instances could be read, at least two or a baseline are needed", len(compared))
+	}
+	if len(compared) == 0 {
+		return nil, nil, fmt.Errorf("settings of none of the instances could be read")
+	}
+
+	output.Drifts = settingsdrift.Compare(compared, baseline)
+	for _, drift := range output.Drifts {
+		if drift.PendingRestart {
+			output.PendingRestart++
+		}
+	}
+
+	return toolResult(output)
+}
+
+// selectInstances returns the named instances or, without names, every registered
+// instance; a label selector narrows either list
+func (s *MCPServer) selectInstances(ctx context.Context, names []string, labels string) ([]model.Instance, error) {
+	selector, err := model.ParseLabelSelector(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []model.Instance
+	if len(names) > 0 {
+		for _, name := range names {
+			instance, err := s.manager.GetInstance(ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get instance %s: %w", name, err)
+			}
+			instances = append(instances, *instance)
+		}
+	} else {
+		instances, err = s.manager.ListInstances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+	}
+
+	selected := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Labels.Matches(selector) {
+			selected = append(selected, instance)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no registered instances match labels %q", labels)
+	}
+	return selected, nil
+}
+
+// Sources of pg_settings values that come from the connection rather than the
+// server configuration; they are not captured into baselines
+var sessionSettingSources = map[string]bool{
+	"client":   true,
+	"session":  true,
+	"override": true,
+}
+
+func (s *MCPServer) handleSaveSettingsBaseline(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SaveSettingsBaselineInput,
+) (*mcp.CallToolResult, *SettingsBaselineOutput, error) {
+	principal := s.principal(req)
+	if !principal.Can(model.ActionNameSaveSettingsBaseline) {
+		return nil, nil, fmt.Errorf("user %s with role %s may not save settings baselines, role %s is required",
+			principal.User, principal.Role, access.RequiredRole(model.ActionNameSaveSettingsBaseline))
+	}
+	if s.baselines == nil {
+		return nil, nil, fmt.Errorf("settings baselines are not configured")
+	}
+	if input.Name == "" {
+		return nil, nil, fmt.Errorf("name is required")
+	}
+	if input.InstanceName == "" && len(input.Settings) == 0 {
+		return nil, nil, fmt.Errorf("instance_name or settings is required")
+	}
+
+	baseline := &model.SettingsBaseline{
+		Name:        input.Name,
+		Description: input.Description,
+		Settings:    map[string]string{},
+		CreatedBy:   principal.User,
+	}
+
+	if input.InstanceName != "" {
+		data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameChangedSettings, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		settings, err := routerData[[]pg.SettingInfo](data)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, setting := range settings {
+			if sessionSettingSources[setting.Source] || settingsdrift.IgnoredSettings[setting.Name] {
+				continue
+			}
+			baseline.Settings[setting.Name] = settingsdrift.Normalize(setting.Setting, setting.Unit.String)
+		}
+	}
+	for name, value := range input.Settings {
+		baseline.Settings[name] = value
+	}
+
+	if err := s.baselines.SaveSettingsBaseline(ctx, baseline); err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&SettingsBaselineOutput{SettingsBaseline: settingsBaselineOutput(*baseline)})
+}
+
+func (s *MCPServer) handleSettingsBaselines(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SettingsBaselinesInput,
+) (*mcp.CallToolResult, *SettingsBaselinesOutput, error) {
+	if s.baselines == nil {
+		return nil, nil, fmt.Errorf("settings baselines are not configured")
+	}
+
+	baselines, err := s.baselines.ListSettingsBaselines(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &SettingsBaselinesOutput{Baselines: make([]SettingsBaseline, 0, len(baselines))}
+	for _, baseline := range baselines {
+		output.Baselines = append(output.Baselines, settingsBaselineOutput(baseline))
+	}
+
+	return toolResult(output)
+}
+
+func settingsBaselineOutput(baseline model.SettingsBaseline) SettingsBaseline {
+	settings := baseline.Settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	return SettingsBaseline{
+		Name:        baseline.Name,
+		Description: baseline.Description,
+		Settings:    settings,
+		CreatedBy:   baseline.CreatedBy,
+		UpdatedAt:   baseline.UpdatedAt,
+	}
+}
+
+func (s *MCPServer) handleTuningAdvisor(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TuningAdvisorInput,
+) (*mcp.CallToolResult, *TuningAdvisorOutput, error) {
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameSettings, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings, err := routerData[[]pg.SettingInfo](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &TuningAdvisorOutput{
+		Instance:    input.InstanceName,
+		Unavailable: []string{},
+	}
+	facts := tuning.Facts{Settings: settings}
+	if input.HostRAMMB > 0 {
+		facts.HostRAMBytes = int64(input.HostRAMMB) << 20
+		output.HostRAMBytes = pg.NewNullInt64(facts.HostRAMBytes)
+	}
+
+	// The observations sharpen the advice but are not required for it
+	unavailable := func(action model.ActionName, err error) {
+		output.Unavailable = append(output.Unavailable, fmt.Sprintf("%s: %v", action, err))
+	}
+
+	if data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameCheckpointsStats, nil); err != nil {
+		unavailable(model.ActionNameCheckpointsStats, err)
+	} else if facts.Checkpoints, err = routerData[*pg.CheckpointsStats](data); err != nil {
+		unavailable(model.ActionNameCheckpointsStats, err)
+	}
+
+	params := map[string]interface{}{}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	if data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameDatabaseOverview, params); err != nil {
+		unavailable(model.ActionNameDatabaseOverview, err)
+	} else if facts.Database, err = routerData[*pg.DatabaseOverview](data); err != nil {
+		unavailable(model.ActionNameDatabaseOverview, err)
+	}
+
+	if data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameConnectionStats, nil); err != nil {
+		unavailable(model.ActionNameConnectionStats, err)
+	} else if facts.Connections, err = routerData[*pg.ConnectionSummary](data); err != nil {
+		unavailable(model.ActionNameConnectionStats, err)
+	}
+
+	output.Findings = nonNil(tuning.Advise(facts))
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleHealthReport(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input HealthReportInput,
+) (*mcp.CallToolResult, *HealthReportOutput, error) {
+	rules := s.router.HealthRules()
+	for name, threshold := range input.Thresholds {
+		if err := rules.Override(name, healthreport.Threshold(threshold)); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, name := range input.Disable {
+		if err := rules.Disable(name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	params := map[string]interface{}{"rules": rules}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameHealthReport, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := routerData[*healthreport.Report](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&HealthReportOutput{
+		Instance:   input.InstanceName,
+		Report:     *report,
+		Thresholds: rules.Thresholds,
+	})
+}
+
+// Page sizes of the schema listing tools
+const (
+	defaultSchemaPageSize = 50
+	maxSchemaPageSize     = 500
+)
+
+// schemaPageParams builds the router parameters of a schema listing. The router is asked
+// for one row more than the page so schemaPage can tell whether another page follows.
+func schemaPageParams(input SchemaPageInput) (int, int, map[string]interface{}) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSchemaPageSize
+	}
+	limit = min(limit, maxSchemaPageSize)
+	offset := max(input.Offset, 0)
+
+	params := map[string]interface{}{
+		"schema": input.Schema,
+		"limit":  limit + 1,
+		"offset": offset,
+	}
+	if input.DbName != "" {
+		params["dbName"] = input.DbName
+	}
+	return limit, offset, params
+}
+
+// schemaPage trims the extra row requested by schemaPageParams and returns the next offset
+func schemaPage[T any](items []T, limit, offset int) ([]T, pg.NullInt64) {
+	if len(items) <= limit {
+		return items, pg.NullInt64{}
+	}
+	return items[:limit], pg.NewNullInt64(int64(offset + limit))
+}
+
+// Defaults applied by the router when the corresponding parameters are omitted
+const (
+	defaultDbName                 = "postgres"
+	defaultMinDurationSeconds     = 5
+	defaultRetainedWalThresholdMB = 1024
+)
+
+type textOutput interface {
+	Text() string
+}
+
+// toolResult returns the typed output as structured content together with its text rendering
+func toolResult[T textOutput](output T) (*mcp.CallToolResult, T, error) {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: output.Text()}},
+	}, output, nil
+}
+
+func routerData[T any](data interface{}) (T, error) {
+	typed, ok := data.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unexpected router result type %T", data)
+	}
+	return typed, nil
+}
+
+func dbNameOrDefault(dbName string) string {
+	if dbName == "" {
+		return defaultDbName
+	}
+	return dbName
 }
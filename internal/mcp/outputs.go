@@ -0,0 +1,397 @@
+package mcp
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"psql-mcp-registry/internal/healthreport"
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/schemadiff"
+	"psql-mcp-registry/internal/settingsdrift"
+	"psql-mcp-registry/internal/statements"
+	"psql-mcp-registry/internal/tuning"
+	"psql-mcp-registry/internal/waitprofile"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tool outputs are concrete types so that every tool advertises an output
+// schema. Nullable columns use the pg.Null* types: they serialize as a plain
+// value or null, and nullSchemas describes them as ["null", T] in the schema.
+// List fields are always non-nil so that they serialize as [] rather than null.
+
+type DatabaseOverviewOutput struct {
+	Instance string              `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string              `json:"database" jsonschema:"database the statistics belong to"`
+	Overview pg.DatabaseOverview `json:"overview"`
+}
+
+type CacheHitRateOutput struct {
+	Instance string         `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string         `json:"database,omitempty" jsonschema:"database name, empty for the global rate"`
+	HitRate  pg.NullFloat64 `json:"hit_rate" jsonschema:"share of block reads served from shared buffers (0..1), null when there was no activity"`
+}
+
+type CheckpointsStatsOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.CheckpointsStats
+}
+
+type WalActivityOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.WalActivity
+}
+
+type IOStatsOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.IOReport
+}
+
+type TablesInfoOutput struct {
+	Instance string         `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Tables   []pg.TableInfo `json:"tables" jsonschema:"tables ordered by total size, largest first"`
+}
+
+type LockingInfoOutput struct {
+	Instance string        `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string        `json:"database"`
+	Locks    []pg.LockInfo `json:"locks" jsonschema:"waiting or blocked sessions"`
+}
+
+type ChangedSettingsOutput struct {
+	Instance string           `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Settings []pg.SettingInfo `json:"settings" jsonschema:"settings whose source is not default"`
+}
+
+type VersionOutput struct {
+	Instance string     `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Version  pg.Version `json:"version"`
+}
+
+type IndexStatsOutput struct {
+	Instance string          `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Indexes  []pg.IndexStats `json:"indexes" jsonschema:"indexes ordered by size, largest first"`
+}
+
+type ActiveQueriesOutput struct {
+	Instance           string           `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database           string           `json:"database"`
+	MinDurationSeconds int              `json:"min_duration_seconds"`
+	Queries            []pg.ActiveQuery `json:"queries" jsonschema:"non-idle sessions ordered by query start, oldest first"`
+}
+
+type ConnectionStatsOutput struct {
+	Instance    string               `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Connections pg.ConnectionSummary `json:"connections"`
+}
+
+type WaitProfileOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	waitprofile.Profile
+}
+
+type WaitProfileHistoryOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Windows  int    `json:"windows" jsonschema:"number of recorded windows merged into the profile"`
+	waitprofile.Profile
+}
+
+type SlowQueriesOutput struct {
+	Instance string         `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Queries  []pg.SlowQuery `json:"queries" jsonschema:"statements ordered by total execution time"`
+}
+
+type TopStatementsOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.StatementsReport
+}
+
+type StatementsSnapshot struct {
+	ID         int        `json:"id" jsonschema:"ID of the snapshot"`
+	Instance   string     `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	TakenAt    time.Time  `json:"taken_at" jsonschema:"when the snapshot was taken"`
+	Statements int        `json:"statements" jsonschema:"number of queryids in the snapshot"`
+	StatsReset *time.Time `json:"stats_reset,omitempty" jsonschema:"last reset of pg_stat_statements at that time (PostgreSQL 14+)"`
+	Comment    string     `json:"comment,omitempty" jsonschema:"why the snapshot was taken"`
+	CreatedBy  string     `json:"created_by,omitempty" jsonschema:"user who took the snapshot, or collector"`
+}
+
+type CaptureStatementsSnapshotOutput struct {
+	StatementsSnapshot
+}
+
+type StatementsSnapshotsOutput struct {
+	Instance  string               `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Snapshots []StatementsSnapshot `json:"snapshots" jsonschema:"stored snapshots, newest first"`
+}
+
+type StatementsDiffOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	statements.Diff
+}
+
+type DatabaseSizesOutput struct {
+	Instance  string            `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Databases []pg.DatabaseSize `json:"databases" jsonschema:"databases ordered by size, largest first"`
+}
+
+type ReplicationStatusOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.ReplicationStatus
+}
+
+type InstanceReplicationSlots struct {
+	Instance string               `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Error    string               `json:"error,omitempty" jsonschema:"why the slots of this instance could not be read"`
+	Slots    []pg.ReplicationSlot `json:"slots" jsonschema:"slots ordered by retained WAL, largest first"`
+}
+
+type ReplicationSlotsOutput struct {
+	RetainedWalThresholdMB int                        `json:"retained_wal_threshold_mb"`
+	AtRisk                 int                        `json:"at_risk" jsonschema:"number of slots flagged as at risk across all instances"`
+	Instances              []InstanceReplicationSlots `json:"instances"`
+}
+
+type XidWraparoundOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.XidWraparound
+}
+
+type TableFreezeAgeOutput struct {
+	Instance string              `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string              `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Tables   []pg.TableFreezeAge `json:"tables" jsonschema:"tables ordered by relfrozenxid age, oldest first"`
+}
+
+type MaintenanceProgressOutput struct {
+	Instance   string                   `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Operations []pg.MaintenanceProgress `json:"operations" jsonschema:"running maintenance operations"`
+}
+
+type TableBloatOutput struct {
+	Instance string          `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string          `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Exact    bool            `json:"exact" jsonschema:"true when measured with pgstattuple, false when estimated from pg_stats"`
+	Tables   []pg.TableBloat `json:"tables" jsonschema:"tables ordered by wasted bytes, largest first"`
+}
+
+type IndexBloatOutput struct {
+	Instance string          `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string          `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Exact    bool            `json:"exact" jsonschema:"true when measured with pgstatindex, false when estimated from pg_stats"`
+	Indexes  []pg.IndexBloat `json:"indexes" jsonschema:"btree indexes ordered by wasted bytes, largest first"`
+}
+
+type IndexAdvisorOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	pg.IndexAdvice
+}
+
+type LockGraphOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	pg.LockGraph
+}
+
+type BackendActionOutput struct {
+	Instance     string      `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Action       string      `json:"action" jsonschema:"cancel_backend or terminate_backend"`
+	Status       string      `json:"status" jsonschema:"confirmation_required, cancelled, terminated or not_signalled"`
+	RequestedBy  string      `json:"requested_by" jsonschema:"user the action is recorded for"`
+	ConfirmToken string      `json:"confirm_token,omitempty" jsonschema:"single-use token to pass to the second call"`
+	ExpiresAt    pg.NullTime `json:"expires_at" jsonschema:"when the confirm token expires"`
+	Backend      *pg.Backend `json:"backend,omitempty" jsonschema:"the backend as observed before the action"`
+}
+
+type SchemasOutput struct {
+	Instance string          `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string          `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Schemas  []pg.SchemaInfo `json:"schemas" jsonschema:"user schemas with the number of tables, views and functions"`
+}
+
+type SchemaTablesOutput struct {
+	Instance   string           `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database   string           `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Schema     string           `json:"schema,omitempty" jsonschema:"schema filter, empty for all user schemas"`
+	Tables     []pg.TableSchema `json:"tables" jsonschema:"tables ordered by schema and name, with their columns"`
+	NextOffset pg.NullInt64     `json:"next_offset" jsonschema:"offset of the next page, null on the last page"`
+}
+
+type DescribeTableOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	pg.TableDetail
+}
+
+type SchemaViewsOutput struct {
+	Instance   string        `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database   string        `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Schema     string        `json:"schema,omitempty" jsonschema:"schema filter, empty for all user schemas"`
+	Views      []pg.ViewInfo `json:"views" jsonschema:"views and materialized views ordered by schema and name, with their definitions"`
+	NextOffset pg.NullInt64  `json:"next_offset" jsonschema:"offset of the next page, null on the last page"`
+}
+
+type SchemaFunctionsOutput struct {
+	Instance   string            `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database   string            `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+	Schema     string            `json:"schema,omitempty" jsonschema:"schema filter, empty for all user schemas"`
+	Functions  []pg.FunctionInfo `json:"functions" jsonschema:"functions and procedures ordered by schema and name"`
+	NextOffset pg.NullInt64      `json:"next_offset" jsonschema:"offset of the next page, null on the last page"`
+}
+
+type SchemaDiffSide struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Database string `json:"database,omitempty" jsonschema:"database name, empty for the database of the instance"`
+}
+
+type SchemaDiffOutput struct {
+	Source  SchemaDiffSide      `json:"source" jsonschema:"the reference schema"`
+	Target  SchemaDiffSide      `json:"target" jsonschema:"the schema compared with the source"`
+	Schema  string              `json:"schema,omitempty" jsonschema:"schema filter, empty for all user schemas"`
+	Mode    string              `json:"mode" jsonschema:"changes, summary or patch"`
+	Summary schemadiff.Summary  `json:"summary" jsonschema:"number of changes per object"`
+	Changes []schemadiff.Change `json:"changes" jsonschema:"differences seen from the source: missing objects exist only in the source, extra objects only in the target; empty in summary mode"`
+	Patch   string              `json:"patch,omitempty" jsonschema:"DDL that makes the target match the source, in patch mode; nothing is executed"`
+}
+
+type InstanceError struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Error    string `json:"error" jsonschema:"why the instance could not be read"`
+}
+
+type SettingsDriftOutput struct {
+	Instances      []string              `json:"instances" jsonschema:"instances whose settings were compared"`
+	Baseline       string                `json:"baseline,omitempty" jsonschema:"name of the baseline compared against"`
+	Drifts         []settingsdrift.Drift `json:"drifts" jsonschema:"settings that differ between instances, from the baseline, or wait for a restart; values are normalized (4GB, 5min, on)"`
+	PendingRestart int                   `json:"pending_restart" jsonschema:"number of drifting settings changed in the configuration but not applied until a restart"`
+	Errors         []InstanceError       `json:"errors" jsonschema:"instances that could not be read"`
+}
+
+type SettingsBaseline struct {
+	Name        string            `json:"name" jsonschema:"name of the baseline"`
+	Description string            `json:"description,omitempty" jsonschema:"what the baseline is for"`
+	Settings    map[string]string `json:"settings" jsonschema:"expected values in postgresql.conf syntax"`
+	CreatedBy   string            `json:"created_by,omitempty" jsonschema:"user who last saved the baseline"`
+	UpdatedAt   time.Time         `json:"updated_at" jsonschema:"when the baseline was last saved"`
+}
+
+type SettingsBaselineOutput struct {
+	SettingsBaseline
+}
+
+type SettingsBaselinesOutput struct {
+	Baselines []SettingsBaseline `json:"baselines" jsonschema:"settings baselines stored in the registry"`
+}
+
+type TuningAdvisorOutput struct {
+	Instance     string           `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	HostRAMBytes pg.NullInt64     `json:"host_ram_bytes" jsonschema:"host RAM the advice is sized for, null when unknown"`
+	Findings     []tuning.Finding `json:"findings" jsonschema:"recommendations ordered by severity (critical, warning, info) with the current and suggested value and the rationale"`
+	Unavailable  []string         `json:"unavailable" jsonschema:"observations that could not be collected; rules that need them were skipped"`
+}
+
+type HealthReportOutput struct {
+	Instance string `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	healthreport.Report
+	Thresholds map[string]healthreport.Threshold `json:"thresholds" jsonschema:"thresholds the rules were evaluated with, by rule name"`
+}
+
+type AlertRule struct {
+	Name        string                 `json:"name" jsonschema:"name of the rule"`
+	Description string                 `json:"description,omitempty" jsonschema:"what the alert means"`
+	Action      string                 `json:"action" jsonschema:"action whose output is checked"`
+	Params      map[string]interface{} `json:"params,omitempty" jsonschema:"parameters of the action"`
+	Expression  string                 `json:"expression" jsonschema:"condition on the action output"`
+	For         string                 `json:"for" jsonschema:"how long the condition must hold before the alert fires"`
+	Severity    string                 `json:"severity" jsonschema:"critical, warning or info"`
+	Labels      string                 `json:"labels,omitempty" jsonschema:"label selector of the instances, empty for all"`
+	Enabled     bool                   `json:"enabled" jsonschema:"whether the rule is evaluated"`
+	CreatedBy   string                 `json:"created_by,omitempty" jsonschema:"user who last saved the rule"`
+	UpdatedAt   time.Time              `json:"updated_at" jsonschema:"when the rule was last saved"`
+}
+
+type AlertPreview struct {
+	Instance string   `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	Matched  bool     `json:"matched" jsonschema:"whether the condition holds right now"`
+	Value    *float64 `json:"value,omitempty" jsonschema:"left operand of the condition"`
+	Error    string   `json:"error,omitempty" jsonschema:"why the rule could not be evaluated on this instance"`
+}
+
+type SaveAlertRuleOutput struct {
+	AlertRule
+	Preview []AlertPreview `json:"preview" jsonschema:"the condition evaluated once on every instance the rule applies to"`
+}
+
+type AlertRulesOutput struct {
+	Rules []AlertRule `json:"rules" jsonschema:"alert rules stored in the registry"`
+}
+
+type Alert struct {
+	Rule        string     `json:"rule" jsonschema:"name of the rule"`
+	Severity    string     `json:"severity" jsonschema:"severity of the rule"`
+	Instance    string     `json:"instance" jsonschema:"name of the PostgreSQL instance"`
+	State       string     `json:"state" jsonschema:"pending (condition holds, not for long enough yet), firing or resolved"`
+	Value       *float64   `json:"value,omitempty" jsonschema:"left operand of the condition at the last evaluation"`
+	ActiveSince time.Time  `json:"active_since" jsonschema:"when the condition started to hold"`
+	FiringSince *time.Time `json:"firing_since,omitempty" jsonschema:"when the alert started firing"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty" jsonschema:"when the condition stopped holding"`
+	EvaluatedAt time.Time  `json:"evaluated_at" jsonschema:"when the rule was last evaluated on the instance"`
+	Notified    bool       `json:"notified" jsonschema:"whether the firing notification was delivered"`
+	Silenced    bool       `json:"silenced" jsonschema:"whether notifications are currently suppressed by a silence"`
+}
+
+type AlertSilence struct {
+	ID        int       `json:"id" jsonschema:"ID of the silence"`
+	Rule      string    `json:"rule,omitempty" jsonschema:"silenced rule, empty for every rule"`
+	Instance  string    `json:"instance,omitempty" jsonschema:"silenced instance, empty for every instance"`
+	StartsAt  time.Time `json:"starts_at" jsonschema:"start of the silence"`
+	EndsAt    time.Time `json:"ends_at" jsonschema:"end of the silence"`
+	Comment   string    `json:"comment,omitempty" jsonschema:"why the alert is silenced"`
+	CreatedBy string    `json:"created_by,omitempty" jsonschema:"user who created the silence"`
+}
+
+type AlertsOutput struct {
+	Alerts   []Alert        `json:"alerts" jsonschema:"alert states tracked in the registry, firing first"`
+	Firing   int            `json:"firing" jsonschema:"number of firing alerts"`
+	Silences []AlertSilence `json:"silences" jsonschema:"silences that have not ended"`
+}
+
+type SilenceAlertOutput struct {
+	AlertSilence
+}
+
+type InstanceHealthOutput struct {
+	router.InstanceHealth
+}
+
+var nullSchemas = map[reflect.Type]*jsonschema.Schema{
+	reflect.TypeFor[pg.NullString]():  {Types: []string{"null", "string"}},
+	reflect.TypeFor[pg.NullInt64]():   {Types: []string{"null", "integer"}},
+	reflect.TypeFor[pg.NullFloat64](): {Types: []string{"null", "number"}},
+	reflect.TypeFor[pg.NullTime]():    {Types: []string{"null", "string"}, Format: "date-time"},
+}
+
+// outputSchema infers the schema of a tool output, mapping pg.Null* types to nullable scalars
+func outputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{TypeSchemas: nullSchemas})
+	if err != nil {
+		panic(fmt.Sprintf("output schema for %T: %v", *new(T), err))
+	}
+	return schema
+}
+
+// addTool registers a tool with the output schema of Out
+func addTool[In, Out any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, *Out]) {
+	tool.OutputSchema = outputSchema[Out]()
+	mcp.AddTool(server, tool, handler)
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
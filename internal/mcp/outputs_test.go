@@ -0,0 +1,337 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outputVariant selects how sampleOutput fills an output
+type outputVariant string
+
+const (
+	// every field set, one element in every list and map
+	variantFilled outputVariant = "filled"
+	// zero values and empty lists, as a tool returns when there is nothing to report
+	variantEmpty outputVariant = "empty"
+	// one element in every list, with every nullable column null and every optional object absent
+	variantNull outputVariant = "null"
+)
+
+var sampleTime = time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+
+// sampleOutput builds an output of type T the way the handlers do: lists are never nil
+func sampleOutput[T any](variant outputVariant) *T {
+	output := new(T)
+	fillSample(reflect.ValueOf(output).Elem(), variant, 0)
+	return output
+}
+
+func fillSample(v reflect.Value, variant outputVariant, depth int) {
+	if !v.CanSet() {
+		return
+	}
+
+	if _, nullable := nullSchemas[v.Type()]; nullable {
+		if variant == variantFilled {
+			v.Set(reflect.ValueOf(validNull(v.Type())))
+		}
+		return
+	}
+	if v.Type() == reflect.TypeFor[time.Time]() {
+		v.Set(reflect.ValueOf(sampleTime))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillSample(v.Field(i), variant, depth)
+		}
+	case reflect.Pointer:
+		if variant == variantFilled && depth < 3 {
+			v.Set(reflect.New(v.Type().Elem()))
+			fillSample(v.Elem(), variant, depth+1)
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 0, 1))
+		if variant != variantEmpty && depth < 3 {
+			item := reflect.New(v.Type().Elem()).Elem()
+			fillSample(item, variant, depth+1)
+			v.Set(reflect.Append(v, item))
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		if variant != variantEmpty && depth < 3 {
+			key := reflect.New(v.Type().Key()).Elem()
+			fillSample(key, variantFilled, depth+1)
+			item := reflect.New(v.Type().Elem()).Elem()
+			fillSample(item, variant, depth+1)
+			v.SetMapIndex(key, item)
+		}
+	case reflect.Interface:
+		if variant != variantEmpty {
+			v.Set(reflect.ValueOf("sample"))
+		}
+	case reflect.String:
+		if variant != variantEmpty {
+			v.SetString("sample")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if variant != variantEmpty {
+			v.SetInt(7)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if variant != variantEmpty {
+			v.SetUint(7)
+		}
+	case reflect.Float32, reflect.Float64:
+		if variant != variantEmpty {
+			v.SetFloat(0.25)
+		}
+	case reflect.Bool:
+		v.SetBool(variant != variantEmpty)
+	}
+}
+
+func validNull(t reflect.Type) any {
+	switch t {
+	case reflect.TypeFor[pg.NullString]():
+		return pg.NewNullString("sample")
+	case reflect.TypeFor[pg.NullInt64]():
+		return pg.NewNullInt64(7)
+	case reflect.TypeFor[pg.NullFloat64]():
+		return pg.NewNullFloat64(0.25)
+	default:
+		return pg.NewNullTime(sampleTime)
+	}
+}
+
+type outputCase struct {
+	name   string
+	schema func() *jsonschema.Schema
+	sample func(variant outputVariant) textOutput
+}
+
+func outputCaseFor[T any, PT interface {
+	*T
+	textOutput
+}]() outputCase {
+	return outputCase{
+		name:   reflect.TypeFor[T]().Name(),
+		schema: outputSchema[T],
+		sample: func(variant outputVariant) textOutput { return PT(sampleOutput[T](variant)) },
+	}
+}
+
+// outputCases lists every tool output registered with addTool
+var outputCases = []outputCase{
+	outputCaseFor[DatabaseOverviewOutput](),
+	outputCaseFor[CacheHitRateOutput](),
+	outputCaseFor[CheckpointsStatsOutput](),
+	outputCaseFor[WalActivityOutput](),
+	outputCaseFor[IOStatsOutput](),
+	outputCaseFor[TablesInfoOutput](),
+	outputCaseFor[LockingInfoOutput](),
+	outputCaseFor[ChangedSettingsOutput](),
+	outputCaseFor[VersionOutput](),
+	outputCaseFor[IndexStatsOutput](),
+	outputCaseFor[ActiveQueriesOutput](),
+	outputCaseFor[ConnectionStatsOutput](),
+	outputCaseFor[WaitProfileOutput](),
+	outputCaseFor[WaitProfileHistoryOutput](),
+	outputCaseFor[SlowQueriesOutput](),
+	outputCaseFor[TopStatementsOutput](),
+	outputCaseFor[CaptureStatementsSnapshotOutput](),
+	outputCaseFor[StatementsSnapshotsOutput](),
+	outputCaseFor[StatementsDiffOutput](),
+	outputCaseFor[DatabaseSizesOutput](),
+	outputCaseFor[ReplicationStatusOutput](),
+	outputCaseFor[ReplicationSlotsOutput](),
+	outputCaseFor[XidWraparoundOutput](),
+	outputCaseFor[TableFreezeAgeOutput](),
+	outputCaseFor[MaintenanceProgressOutput](),
+	outputCaseFor[TableBloatOutput](),
+	outputCaseFor[IndexBloatOutput](),
+	outputCaseFor[IndexAdvisorOutput](),
+	outputCaseFor[LockGraphOutput](),
+	outputCaseFor[BackendActionOutput](),
+	outputCaseFor[SchemasOutput](),
+	outputCaseFor[SchemaTablesOutput](),
+	outputCaseFor[DescribeTableOutput](),
+	outputCaseFor[SchemaViewsOutput](),
+	outputCaseFor[SchemaFunctionsOutput](),
+	outputCaseFor[SchemaDiffOutput](),
+	outputCaseFor[SettingsDriftOutput](),
+	outputCaseFor[SettingsBaselineOutput](),
+	outputCaseFor[SettingsBaselinesOutput](),
+	outputCaseFor[TuningAdvisorOutput](),
+	outputCaseFor[HealthReportOutput](),
+	outputCaseFor[SaveAlertRuleOutput](),
+	outputCaseFor[AlertRulesOutput](),
+	outputCaseFor[AlertsOutput](),
+	outputCaseFor[SilenceAlertOutput](),
+	outputCaseFor[InstanceHealthOutput](),
+}
+
+func TestOutputs_MatchTheirSchemas(t *testing.T) {
+	for _, tt := range outputCases {
+		resolved, err := tt.schema().Resolve(nil)
+		require.NoError(t, err, tt.name)
+
+		for _, variant := range []outputVariant{variantFilled, variantEmpty, variantNull} {
+			data, err := json.Marshal(tt.sample(variant))
+			require.NoError(t, err, "%s %s", tt.name, variant)
+
+			var instance any
+			require.NoError(t, json.Unmarshal(data, &instance), "%s %s", tt.name, variant)
+			assert.NoError(t, resolved.Validate(instance), "%s %s: %s", tt.name, variant, data)
+		}
+	}
+}
+
+func TestOutputs_RejectNilLists(t *testing.T) {
+	resolved, err := outputSchema[TablesInfoOutput]().Resolve(nil)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(TablesInfoOutput{Instance: "prod"})
+	require.NoError(t, err)
+
+	var instance any
+	require.NoError(t, json.Unmarshal(data, &instance))
+	assert.Error(t, resolved.Validate(instance), "a nil list serializes as null, which the schema does not allow")
+}
+
+func TestOutputs_TextRendersEveryVariant(t *testing.T) {
+	for _, tt := range outputCases {
+		for _, variant := range []outputVariant{variantFilled, variantEmpty, variantNull} {
+			output := tt.sample(variant)
+
+			var text string
+			require.NotPanics(t, func() { text = output.Text() }, "%s %s", tt.name, variant)
+			assert.NotEmpty(t, strings.TrimSpace(text), "%s %s", tt.name, variant)
+		}
+	}
+}
+
+func TestOutputs_Text(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 18, 12, 35, 0, 0, time.UTC)
+	nextProbeAt := time.Date(2026, 10, 18, 12, 31, 0, 0, time.UTC)
+	value := 0.5
+
+	tests := []struct {
+		name     string
+		output   textOutput
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "cache hit rate",
+			output:   &CacheHitRateOutput{Instance: "prod", Database: "shop", HitRate: pg.NewNullFloat64(0.9912)},
+			contains: []string{"Cache hit rate for database shop on instance prod: 99.12%"},
+		},
+		{
+			name:     "cache hit rate without activity",
+			output:   &CacheHitRateOutput{Instance: "prod"},
+			contains: []string{"Cache hit rate for all databases on instance prod: no block activity yet"},
+		},
+		{
+			name:     "checkpoints without buffer counters",
+			output:   &CheckpointsStatsOutput{Instance: "prod"},
+			contains: []string{"Checkpoints on instance prod", "Timed: 0, requested: 0"},
+			excludes: []string{"Buffers written"},
+		},
+		{
+			name:     "no tables",
+			output:   &TablesInfoOutput{Instance: "prod", Tables: []pg.TableInfo{}},
+			contains: []string{"No tables found on instance prod"},
+		},
+		{
+			name: "tables with null columns",
+			output: &TablesInfoOutput{Instance: "prod", Tables: []pg.TableInfo{
+				{SchemaName: "public", TableName: "orders", TotalBytes: 2048, NLiveTup: pg.NewNullInt64(10)},
+			}},
+			contains: []string{"1 tables on instance prod", "public.orders", "2.0 KB", "10"},
+		},
+		{
+			name:     "settings at defaults",
+			output:   &ChangedSettingsOutput{Instance: "prod", Settings: []pg.SettingInfo{}},
+			contains: []string{"All settings on instance prod are at their defaults"},
+		},
+		{
+			name:     "version",
+			output:   &VersionOutput{Instance: "prod", Version: pg.Version{Major: 17, Minor: 2}},
+			contains: []string{"Instance prod runs PostgreSQL 17.2"},
+		},
+		{
+			name: "active queries",
+			output: &ActiveQueriesOutput{Instance: "prod", Database: "shop", MinDurationSeconds: 5, Queries: []pg.ActiveQuery{
+				{PID: 42, Query: pg.NewNullString("select " + strings.Repeat("x", 200))},
+			}},
+			contains: []string{"1 queries running longer than 5s in database shop on instance prod", "42", "..."},
+			excludes: []string{strings.Repeat("x", 200)},
+		},
+		{
+			name:     "backend action awaiting confirmation",
+			output:   &BackendActionOutput{Instance: "prod", Action: "terminate_backend", Status: backendStatusConfirmationRequired, ConfirmToken: "abc", ExpiresAt: pg.NewNullTime(expiresAt)},
+			contains: []string{"terminate_backend on instance prod needs confirmation", "confirm_token=abc", "2026-10-18 12:35:00Z"},
+		},
+		{
+			name:     "backend not signalled",
+			output:   &BackendActionOutput{Instance: "prod", Action: "cancel_backend", Status: backendStatusNotSignalled},
+			contains: []string{"cancel_backend on instance prod: the backend was not signalled"},
+			excludes: []string{"pid"},
+		},
+		{
+			name:     "describe table without a kind",
+			output:   &DescribeTableOutput{Instance: "prod", TableDetail: pg.TableDetail{TableSchema: pg.TableSchema{SchemaName: "public", TableName: "orders"}}},
+			contains: []string{"Relation public.orders in"},
+		},
+		{
+			name:     "no alert rules",
+			output:   &AlertRulesOutput{Rules: []AlertRule{}},
+			contains: []string{"No alert rules stored"},
+		},
+		{
+			name: "silenced firing alert",
+			output: &AlertsOutput{Firing: 1, Alerts: []Alert{
+				{Rule: "many-connections", Severity: "warning", Instance: "prod", State: "firing", Value: &value, Silenced: true, FiringSince: &sampleTime},
+			}, Silences: []AlertSilence{}},
+			contains: []string{"Alerts (1 firing, * silenced)", "firing*", "many-connections", "2026-10-18 12:30:00Z"},
+			excludes: []string{"Silences"},
+		},
+		{
+			name:     "silence of every rule",
+			output:   &SilenceAlertOutput{AlertSilence{ID: 3, EndsAt: sampleTime}},
+			contains: []string{"Silenced all rules until 2026-10-18 12:30:00Z (silence 3)"},
+		},
+		{
+			name: "unreachable instance with an open breaker",
+			output: &InstanceHealthOutput{router.InstanceHealth{
+				Instance: "prod",
+				Error:    "connection refused",
+				Breaker:  router.BreakerStatus{State: router.BreakerStateOpen, ConsecutiveFailures: 5, FailureThreshold: 5, NextProbeAt: &nextProbeAt},
+			}},
+			contains: []string{"Instance prod is not reachable: connection refused", "Circuit breaker: open (5/5 consecutive failures), next probe at 2026-10-18 12:31:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		text := tt.output.Text()
+		for _, s := range tt.contains {
+			assert.Contains(t, text, s, tt.name)
+		}
+		for _, s := range tt.excludes {
+			assert.NotContains(t, text, s, tt.name)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultPromptDbName = "postgres"
+
+// promptStep is one tool call of a diagnostic workflow with a hint on how to read its result
+type promptStep struct {
+	Tool      string
+	Args      string
+	Interpret string
+}
+
+// promptContext carries the resolved prompt arguments and the detected server version
+type promptContext struct {
+	InstanceName string
+	DbName       string
+	Version      *pg.Version
+}
+
+func (s *MCPServer) registerPrompts() {
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "diagnose_slow_instance",
+		Title:       "Diagnose slow instance",
+		Description: "Step-by-step workflow to find out why a PostgreSQL instance is slow: load, waits, cache, expensive statements and checkpoints",
+		Arguments:   instancePromptArguments(),
+	}, s.handleDiagnoseSlowInstancePrompt)
+
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "investigate_lock_contention",
+		Title:       "Investigate lock contention",
+		Description: "Workflow to find blocked sessions, the sessions blocking them and the transactions holding locks for too long",
+		Arguments:   instancePromptArguments(),
+	}, s.handleLockContentionPrompt)
+
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "capacity_review",
+		Title:       "Capacity review",
+		Description: "Workflow to review disk usage, largest relations, connection headroom and write volume of an instance",
+		Arguments:   instancePromptArguments(),
+	}, s.handleCapacityReviewPrompt)
+
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "vacuum_health",
+		Title:       "Vacuum health",
+		Description: "Workflow to check dead tuple accumulation, autovacuum activity and configuration, and transaction ID wraparound risk",
+		Arguments:   instancePromptArguments(),
+	}, s.handleVacuumHealthPrompt)
+}
+
+func instancePromptArguments() []*mcp.PromptArgument {
+	return []*mcp.PromptArgument{
+		{
+			Name:        "instance_name",
+			Description: "name of the registered PostgreSQL instance",
+			Required:    true,
+		},
+		{
+			Name:        "db_name",
+			Description: "database to inspect (default: postgres)",
+		},
+	}
+}
+
+func (s *MCPServer) handleDiagnoseSlowInstancePrompt(
+	ctx context.Context,
+	req *mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	pc, err := s.resolvePromptContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []promptStep{
+		{
+			Tool:      "connection_stats",
+			Interpret: "Compare total_connections with max_connections. Many active sessions point to CPU or I/O saturation, many idle_in_transaction sessions point to application-side transaction leaks, a high waiting count points to lock or I/O waits.",
+		},
+		{
+			Tool:      "active_queries",
+			Args:      fmt.Sprintf("db_name=%q, min_duration_seconds=5", pc.DbName),
+			Interpret: "Look for long-running statements and group them by wait_event_type: Lock means contention, IO means disk pressure, LWLock means internal contention, no wait event means the query is on CPU.",
+		},
+		{
+			Tool:      "locking_info",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "Any session with non-empty blocking_pids is stuck behind another one. If there are many, switch to the investigate_lock_contention workflow.",
+		},
+		{
+			Tool:      "cache_hit_rate",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "hit_rate below 0.99 on an OLTP workload means the working set does not fit in shared_buffers and reads go to disk.",
+		},
+		{
+			Tool:      "database_overview",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "Growing temp_files/temp_bytes mean sorts and hashes spill to disk (work_mem too small). Deadlocks and a high xact_rollback share point to application errors.",
+		},
+	}
+
+	if pc.supportsStatementsExecTime() {
+		steps = append(steps, promptStep{
+			Tool:      "slow_queries",
+			Args:      "limit=10",
+			Interpret: "Statements with the highest total_exec_time dominate the load. High mean_exec_time with few calls points to a bad plan, low mean with many calls points to chatty application code, low cache_hit_percent points to large scans.",
+		})
+		steps = append(steps, promptStep{
+			Tool:      "describe_table",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "Run it for the tables of the slowest statements. Check the real column names and types before suggesting a rewrite, and compare the WHERE and JOIN columns of the statement with the index definitions; a filter without a matching leading index column explains a sequential scan.",
+		})
+	}
+
+	steps = append(steps, checkpointsStep(pc))
+
+	if pc.Version != nil && pc.Version.SupportsWalStats() {
+		steps = append(steps, promptStep{
+			Tool:      "wal_activity",
+			Interpret: "A large wal_fpi share of wal_records means frequent checkpoints generate full-page images; wal_buffers_full above zero means wal_buffers is too small for the write rate.",
+		})
+	}
+
+	if pc.Version != nil && pc.Version.SupportsReplicationStats() {
+		steps = append(steps, promptStep{
+			Tool:      "replication_status",
+			Interpret: "On a primary, a standby with sync_state 'sync' or 'quorum' and growing flush_lag_seconds delays every commit. On a standby, a large replay_delay_seconds means reads see stale data; replay_paused or a missing wal_receiver explains it.",
+		})
+	}
+
+	return buildPromptResult(
+		"Diagnose a slow PostgreSQL instance",
+		pc,
+		"Find out why the instance is slow. Start from the overall load, then narrow down to waits, cache efficiency and the most expensive statements.",
+		steps,
+		"Summarize the most likely bottleneck first, back it with the numbers you observed, and list concrete next actions ordered by expected impact.",
+	), nil
+}
+
+func (s *MCPServer) handleLockContentionPrompt(
+	ctx context.Context,
+	req *mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	pc, err := s.resolvePromptContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	lockingInterpret := "Sessions with non-empty blocking_pids are waiting. A PID that appears in blocking_pids but is not waiting itself is the head of a lock chain: that is the session to look at."
+	if pc.Version != nil && !pc.Version.SupportsBlockingPids() {
+		lockingInterpret = "pg_blocking_pids is not available before PostgreSQL 9.6, so blocking_pids will be empty. Rely on wait_event_type = 'Lock' to find waiting sessions."
+	}
+
+	steps := []promptStep{
+		{
+			Tool:      "locking_info",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: lockingInterpret,
+		},
+		{
+			Tool:      "active_queries",
+			Args:      fmt.Sprintf("db_name=%q, min_duration_seconds=1", pc.DbName),
+			Interpret: "Match the blocking PIDs to their queries and durations. A blocker in state 'idle in transaction' is an application that opened a transaction and stopped talking to the database.",
+		},
+		{
+			Tool:      "connection_stats",
+			Interpret: "A high idle_in_transaction count confirms that transactions are left open and keep holding locks.",
+		},
+		{
+			Tool:      "database_overview",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "A growing deadlocks counter means the application acquires locks in inconsistent order.",
+		},
+	}
+
+	if pc.Version == nil || pc.Version.SupportsBlockingPids() {
+		// The graph answers "who is the head blocker" directly, so it goes first
+		steps = append([]promptStep{{
+			Tool:      "lock_graph",
+			Interpret: "Each root blocker is the head of a chain; total_waiters shows how much of the workload is stuck behind it. Edges with blocker_granted=false are sessions queued behind a stronger lock request rather than behind a held lock. Waiting sessions without any root form a deadlock that deadlock_timeout has not resolved yet.",
+		}}, steps...)
+	}
+
+	return buildPromptResult(
+		"Investigate lock contention",
+		pc,
+		"Find which sessions are blocked, which sessions block them and why the blockers hold their locks.",
+		steps,
+		"Describe each lock chain from its head blocker down to the waiting sessions, explain what the head blocker is doing, and suggest how to resolve it (commit/rollback in the application, lock_timeout, shorter transactions). Do not suggest terminating backends without stating the impact.",
+	), nil
+}
+
+func (s *MCPServer) handleCapacityReviewPrompt(
+	ctx context.Context,
+	req *mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	pc, err := s.resolvePromptContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []promptStep{
+		{
+			Tool:      "database_sizes",
+			Interpret: "Shows where the disk space goes across databases.",
+		},
+		{
+			Tool:      "tables_info",
+			Args:      "limit=20",
+			Interpret: "The largest tables by total_bytes. A large indexes_bytes share or a high dead_ratio means space can be reclaimed rather than added.",
+		},
+		{
+			Tool:      "index_stats",
+			Args:      "limit=20",
+			Interpret: "Large indexes with idx_scan close to zero cost disk space and write amplification without serving reads.",
+		},
+		{
+			Tool:      "index_advisor",
+			Args:      fmt.Sprintf("db_name=%q", pc.DbName),
+			Interpret: "Duplicate, redundant and invalid indexes can be dropped with the suggested DDL after review. Treat unused findings with care: idx_scan is counted per node since stats_reset, so an index can serve reads on a standby or in a monthly job.",
+		},
+		{
+			Tool:      "table_bloat",
+			Args:      fmt.Sprintf("db_name=%q, limit=20", pc.DbName),
+			Interpret: "wasted_bytes is space that VACUUM FULL, pg_repack or a rewrite would give back. The estimate can be off by 10-20% on tables with wide or variable-length rows; re-run with exact=true on the top entries if pgstattuple is installed.",
+		},
+		{
+			Tool:      "index_bloat",
+			Args:      fmt.Sprintf("db_name=%q, limit=20", pc.DbName),
+			Interpret: "Btree indexes with bloat_pct above 30-40% shrink noticeably after REINDEX CONCURRENTLY (PG 12+).",
+		},
+		{
+			Tool:      "connection_stats",
+			Interpret: "Connection headroom is max_connections minus total_connections. Less than 20% headroom means a pooler or a higher limit is needed before the next traffic peak.",
+		},
+		checkpointsStep(pc),
+	}
+
+	if pc.Version != nil && pc.Version.SupportsWalStats() {
+		steps = append(steps, promptStep{
+			Tool:      "wal_activity",
+			Interpret: "wal_bytes since stats_reset gives the average WAL write rate, which drives archive storage and replication bandwidth.",
+		})
+	}
+
+	if pc.Version != nil && pc.Version.SupportsReplicationStats() {
+		steps = append(steps, promptStep{
+			Tool:      "replication_slots",
+			Interpret: "Slots with at_risk=true are inactive and pin WAL on disk until they are dropped or their consumer comes back. Compare retained_wal_bytes with the free space of the WAL volume; wal_status 'lost' means the slot is already broken.",
+		})
+	}
+
+	return buildPromptResult(
+		"Capacity review",
+		pc,
+		"Review how much headroom the instance has in disk space, connections and write throughput.",
+		steps,
+		"Report current usage and headroom per resource, point out space that can be reclaimed, and flag any resource that is likely to run out first.",
+	), nil
+}
+
+func (s *MCPServer) handleVacuumHealthPrompt(
+	ctx context.Context,
+	req *mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	pc, err := s.resolvePromptContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []promptStep{
+		{
+			Tool:      "tables_info",
+			Args:      "limit=50",
+			Interpret: "Tables with dead_ratio above 10-20% or a last_autovacuum far in the past are not vacuumed often enough. Compare autovacuum_count between tables with similar write rates.",
+		},
+		{
+			Tool:      "table_bloat",
+			Args:      fmt.Sprintf("db_name=%q, limit=20", pc.DbName),
+			Interpret: "dead_ratio only counts dead tuples; bloat_pct shows the free space vacuum left behind. A table with low dead_ratio and high bloat_pct was vacuumed too late and will only shrink after a rewrite.",
+		},
+		{
+			Tool:      "changed_settings",
+			Interpret: "Check autovacuum, autovacuum_vacuum_scale_factor, autovacuum_vacuum_cost_limit, autovacuum_max_workers and maintenance_work_mem. Defaults are too conservative for large tables.",
+		},
+		{
+			Tool:      "active_queries",
+			Args:      fmt.Sprintf("db_name=%q, min_duration_seconds=60", pc.DbName),
+			Interpret: "Long-running transactions hold back the xmin horizon so vacuum cannot remove dead tuples. Running autovacuum workers show up here as 'autovacuum: VACUUM ...' queries.",
+		},
+		{
+			Tool:      "maintenance_progress",
+			Interpret: "Shows whether (auto)vacuum is actually working on the tables found above. A vacuum that stays in one phase with a Lock or IO wait event, or one whose elapsed time is hours with low progress_pct, is throttled by autovacuum_vacuum_cost_limit or blocked.",
+		},
+		{
+			Tool:      "connection_stats",
+			Interpret: "idle_in_transaction sessions hold back vacuum the same way long queries do.",
+		},
+		{
+			Tool:      "xid_wraparound",
+			Interpret: "xid_freeze_max_age_pct above 100 means anti-wraparound autovacuum should already be running; xid_wraparound_pct above 50 needs attention now. Every entry in horizon_holders with a large xmin_age (old transactions, forgotten prepared transactions, inactive slots) prevents freezing.",
+		},
+		{
+			Tool:      "table_freeze_age",
+			Args:      fmt.Sprintf("db_name=%q, limit=10", pc.DbName),
+			Interpret: "These tables hold the database's datfrozenxid back. Large ones need a manual VACUUM (FREEZE) scheduled before autovacuum is forced to do it at a bad time.",
+		},
+	}
+
+	return buildPromptResult(
+		"Vacuum health",
+		pc,
+		"Check whether autovacuum keeps up with the write load and what prevents it from cleaning up dead tuples.",
+		steps,
+		"List the tables that need attention with their dead tuple numbers, name what holds vacuum back (if anything), and suggest per-table or global autovacuum settings.",
+	), nil
+}
+
+// resolvePromptContext validates the prompt arguments and detects the server version.
+// An unreachable instance still yields a prompt, just without version-specific guidance.
+func (s *MCPServer) resolvePromptContext(ctx context.Context, req *mcp.GetPromptRequest) (*promptContext, error) {
+	args := req.Params.Arguments
+
+	instanceName := strings.TrimSpace(args["instance_name"])
+	if instanceName == "" {
+		return nil, fmt.Errorf("argument instance_name is required")
+	}
+
+	dbName := strings.TrimSpace(args["db_name"])
+	if dbName == "" {
+		dbName = defaultPromptDbName
+	}
+
+	if _, err := s.manager.GetInstance(ctx, instanceName); err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	pc := &promptContext{
+		InstanceName: instanceName,
+		DbName:       dbName,
+	}
+
+	if data, err := s.executeRouterQuery(ctx, instanceName, model.ActionNameVersion, nil); err == nil {
+		if version, ok := data.(*pg.Version); ok {
+			pc.Version = version
+		}
+	}
+
+	return pc, nil
+}
+
+func (pc *promptContext) supportsStatementsExecTime() bool {
+	// Unknown version: let the model try, the tool reports its own error
+	return pc.Version == nil || pc.Version.SupportsStatementsExecTime()
+}
+
+func checkpointsStep(pc *promptContext) promptStep {
+	step := promptStep{
+		Tool:      "checkpoints_stats",
+		Interpret: "checkpoints_req should be a small fraction of checkpoints_timed. Many requested checkpoints mean max_wal_size is too small for the write load.",
+	}
+	if pc.Version != nil && !pc.Version.SupportsCheckpointer() {
+		step.Interpret += " buffers_backend close to buffers_checkpoint means backends write dirty pages themselves because the background writer does not keep up."
+	}
+	return step
+}
+
+func buildPromptResult(title string, pc *promptContext, goal string, steps []promptStep, report string) *mcp.GetPromptResult {
+	var intro strings.Builder
+	fmt.Fprintf(&intro, "Task: %s.\nInstance: %q, database: %q.\n", title, pc.InstanceName, pc.DbName)
+	if pc.Version != nil {
+		fmt.Fprintf(&intro, "Server version: PostgreSQL %d.%d.\n", pc.Version.Major, pc.Version.Minor)
+	} else {
+		intro.WriteString("Server version could not be detected; call the version tool first and skip steps the server does not support.\n")
+	}
+	intro.WriteString(goal)
+
+	var plan strings.Builder
+	plan.WriteString("Call the following tools in order. Pass instance_name=")
+	fmt.Fprintf(&plan, "%q to every tool.\n", pc.InstanceName)
+	for i, step := range steps {
+		fmt.Fprintf(&plan, "\n%d. %s", i+1, step.Tool)
+		if step.Args != "" {
+			fmt.Fprintf(&plan, " (%s)", step.Args)
+		}
+		fmt.Fprintf(&plan, "\n   %s\n", step.Interpret)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: fmt.Sprintf("%s for instance %s", title, pc.InstanceName),
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: intro.String()}},
+			{Role: "user", Content: &mcp.TextContent{Text: plan.String()}},
+			{Role: "user", Content: &mcp.TextContent{Text: report}},
+		},
+	}
+}
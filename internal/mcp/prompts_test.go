@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var promptStepPattern = regexp.MustCompile(`(?m)^\d+\. (\w+)`)
+
+// getPrompt renders a prompt for an instance whose client reports version, nil when undetected
+func getPrompt(t *testing.T, name string, version *pg.Version) (intro, plan string) {
+	t.Helper()
+
+	client := pgmocks.NewClientInterface(t)
+	client.On("Version").Return(version)
+
+	_, session := connect(t, client, Stores{}, model.Instance{Name: "prod"})
+	result, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      name,
+		Arguments: map[string]string{"instance_name": "prod", "db_name": "shop"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 3)
+
+	return result.Messages[0].Content.(*mcp.TextContent).Text, result.Messages[1].Content.(*mcp.TextContent).Text
+}
+
+func promptSteps(plan string) []string {
+	var steps []string
+	for _, match := range promptStepPattern.FindAllStringSubmatch(plan, -1) {
+		steps = append(steps, match[1])
+	}
+	return steps
+}
+
+func TestPrompts_StepsFollowTheServerVersion(t *testing.T) {
+	pg12 := &pg.Version{Major: 12, Minor: 4}
+	pg17 := &pg.Version{Major: 17, Minor: 2}
+
+	tests := []struct {
+		prompt  string
+		version *pg.Version
+		steps   []string
+	}{
+		{
+			// Unknown version: statements are tried, version-specific views are skipped
+			prompt: "diagnose_slow_instance",
+			steps: []string{"connection_stats", "active_queries", "locking_info", "cache_hit_rate", "database_overview",
+				"slow_queries", "describe_table", "checkpoints_stats"},
+		},
+		{
+			prompt:  "diagnose_slow_instance",
+			version: pg12,
+			steps: []string{"connection_stats", "active_queries", "locking_info", "cache_hit_rate", "database_overview",
+				"checkpoints_stats", "replication_status"},
+		},
+		{
+			prompt:  "diagnose_slow_instance",
+			version: pg17,
+			steps: []string{"connection_stats", "active_queries", "locking_info", "cache_hit_rate", "database_overview",
+				"slow_queries", "describe_table", "checkpoints_stats", "wal_activity", "replication_status"},
+		},
+		{
+			prompt: "capacity_review",
+			steps: []string{"database_sizes", "tables_info", "index_stats", "index_advisor", "table_bloat", "index_bloat",
+				"connection_stats", "checkpoints_stats"},
+		},
+		{
+			prompt:  "capacity_review",
+			version: pg12,
+			steps: []string{"database_sizes", "tables_info", "index_stats", "index_advisor", "table_bloat", "index_bloat",
+				"connection_stats", "checkpoints_stats", "replication_slots"},
+		},
+		{
+			prompt:  "capacity_review",
+			version: pg17,
+			steps: []string{"database_sizes", "tables_info", "index_stats", "index_advisor", "table_bloat", "index_bloat",
+				"connection_stats", "checkpoints_stats", "wal_activity", "replication_slots"},
+		},
+		{
+			prompt: "investigate_lock_contention",
+			steps:  []string{"lock_graph", "locking_info", "active_queries", "connection_stats", "database_overview"},
+		},
+		{
+			prompt:  "investigate_lock_contention",
+			version: &pg.Version{Major: 9, Minor: 5},
+			steps:   []string{"locking_info", "active_queries", "connection_stats", "database_overview"},
+		},
+	}
+
+	for _, tt := range tests {
+		_, plan := getPrompt(t, tt.prompt, tt.version)
+		assert.Equal(t, tt.steps, promptSteps(plan), "%s on %v", tt.prompt, tt.version)
+	}
+}
+
+func TestPrompts_GuidanceFollowsTheServerVersion(t *testing.T) {
+	intro, plan := getPrompt(t, "diagnose_slow_instance", nil)
+	assert.Contains(t, intro, "Server version could not be detected")
+	assert.NotContains(t, plan, "buffers_backend")
+
+	// The checkpointer counters moved out of pg_stat_bgwriter in PostgreSQL 17
+	intro, plan = getPrompt(t, "diagnose_slow_instance", &pg.Version{Major: 12, Minor: 4})
+	assert.Contains(t, intro, "Server version: PostgreSQL 12.4.")
+	assert.Contains(t, plan, "buffers_backend close to buffers_checkpoint")
+
+	intro, plan = getPrompt(t, "diagnose_slow_instance", &pg.Version{Major: 17, Minor: 2})
+	assert.Contains(t, intro, "Server version: PostgreSQL 17.2.")
+	assert.NotContains(t, plan, "buffers_backend")
+	assert.Contains(t, plan, `instance_name="prod"`)
+	assert.Contains(t, plan, `db_name="shop"`)
+}
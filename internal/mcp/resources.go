@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const instanceResourceScheme = "instances://"
+
+// schemaResourceTableLimit caps the schema resource; list_tables pages through larger catalogs
+const schemaResourceTableLimit = 500
+
+func (s *MCPServer) registerResourceTemplates() {
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: instanceResourceScheme + "{name}",
+		Name:        "instance",
+		Description: "Registration details and current health of a PostgreSQL instance",
+		MIMEType:    "application/json",
+	}, s.handleInstanceResource)
+
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: instanceResourceScheme + "{name}/settings",
+		Name:        "instance_settings",
+		Description: "PostgreSQL settings of an instance that differ from defaults",
+		MIMEType:    "application/json",
+	}, s.handleInstanceSettingsResource)
+
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: instanceResourceScheme + "{name}/databases",
+		Name:        "instance_databases",
+		Description: "Databases of an instance with their sizes",
+		MIMEType:    "application/json",
+	}, s.handleInstanceDatabasesResource)
+
+	s.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: instanceResourceScheme + "{name}/schema/{db}",
+		Name:        "instance_schema",
+		Description: "Tables of a database on an instance with their columns, types and sizes",
+		MIMEType:    "application/json",
+	}, s.handleInstanceSchemaResource)
+}
+
+// SyncInstanceResources lists every registered instance as concrete resources
+func (s *MCPServer) SyncInstanceResources(ctx context.Context) error {
+	instances, err := s.manager.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	for _, instance := range instances {
+		s.AddInstanceResources(instance)
+	}
+
+	return nil
+}
+
+// AddInstanceResources adds the concrete resources of an instance. Every
+// addition sends notifications/resources/list_changed to connected clients.
+// An instance whose name cannot be the host of a URI, such as one with a space,
+// is skipped: the resource templates cannot address it either.
+func (s *MCPServer) AddInstanceResources(instance model.Instance) {
+	base, err := instanceResourceURI(instance.Name)
+	if err != nil {
+		log.Printf("MCP resources skipped for instance %s: %v", instance.Name, err)
+		return
+	}
+
+	s.server.AddResource(&mcp.Resource{
+		URI:         base,
+		Name:        instance.Name,
+		Title:       fmt.Sprintf("Instance %s", instance.Name),
+		Description: instanceResourceDescription(instance),
+		MIMEType:    "application/json",
+	}, s.handleInstanceResource)
+
+	s.server.AddResource(&mcp.Resource{
+		URI:         base + "/settings",
+		Name:        instance.Name + "_settings",
+		Title:       fmt.Sprintf("Instance %s: changed settings", instance.Name),
+		Description: "PostgreSQL settings that differ from defaults",
+		MIMEType:    "application/json",
+	}, s.handleInstanceSettingsResource)
+
+	s.server.AddResource(&mcp.Resource{
+		URI:         base + "/databases",
+		Name:        instance.Name + "_databases",
+		Title:       fmt.Sprintf("Instance %s: databases", instance.Name),
+		Description: "Databases with their sizes",
+		MIMEType:    "application/json",
+	}, s.handleInstanceDatabasesResource)
+
+	if instance.DatabaseName != "" {
+		s.server.AddResource(&mcp.Resource{
+			URI:         base + "/schema/" + url.PathEscape(instance.DatabaseName),
+			Name:        instance.Name + "_schema_" + instance.DatabaseName,
+			Title:       fmt.Sprintf("Instance %s: schema of %s", instance.Name, instance.DatabaseName),
+			Description: "Tables with their columns, types and sizes",
+			MIMEType:    "application/json",
+		}, s.handleInstanceSchemaResource)
+	}
+
+	log.Printf("MCP resources added for instance %s", instance.Name)
+}
+
+func (s *MCPServer) handleInstanceResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	instance, _, err := s.resolveInstanceResource(ctx, req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceData := map[string]interface{}{
+		"name":          instance.Name,
+		"database_name": instance.DatabaseName,
+		"description":   instance.Description,
+		"status":        instance.Status,
+		"labels":        instance.Labels,
+		"created_at":    instance.CreatedAt,
+		"updated_at":    instance.UpdatedAt,
+	}
+
+	if health, err := s.executeRouterQuery(ctx, instance.Name, model.ActionNameInstanceHealth, nil); err == nil {
+		instanceData["health"] = health
+	}
+
+	return jsonResourceResult(req.Params.URI, instanceData), nil
+}
+
+func (s *MCPServer) handleInstanceSettingsResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	instance, _, err := s.resolveInstanceResource(ctx, req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.executeRouterQuery(ctx, instance.Name, model.ActionNameChangedSettings, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := routerData[[]pg.SettingInfo](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResourceResult(req.Params.URI, nonNil(settings)), nil
+}
+
+func (s *MCPServer) handleInstanceDatabasesResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	instance, _, err := s.resolveInstanceResource(ctx, req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.executeRouterQuery(ctx, instance.Name, model.ActionNameDatabaseSizes, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResourceResult(req.Params.URI, data), nil
+}
+
+func (s *MCPServer) handleInstanceSchemaResource(
+	ctx context.Context,
+	req *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	instance, path, err := s.resolveInstanceResource(ctx, req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) != 2 || path[1] == "" {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+
+	data, err := s.executeRouterQuery(ctx, instance.Name, model.ActionNameListTables, map[string]interface{}{
+		"dbName": path[1],
+		"limit":  schemaResourceTableLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := routerData[[]pg.TableSchema](data)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResourceResult(req.Params.URI, nonNil(tables)), nil
+}
+
+// resolveInstanceResource splits instances://{name}/{path...}, unescapes the parts and
+// loads the instance
+func (s *MCPServer) resolveInstanceResource(ctx context.Context, uri string) (*model.Instance, []string, error) {
+	rest, ok := strings.CutPrefix(uri, instanceResourceScheme)
+	if !ok || rest == "" {
+		return nil, nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	parts := strings.Split(rest, "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, nil, mcp.ResourceNotFoundError(uri)
+		}
+		parts[i] = unescaped
+	}
+
+	instance, err := s.manager.GetInstance(ctx, parts[0])
+	if err != nil {
+		return nil, nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	return instance, parts[1:], nil
+}
+
+// instanceResourceURI returns instances://{name} with the name escaped. Escapes are
+// not allowed in the host part of a URI except %25, so names with a space, a slash
+// or a colon are rejected instead of making mcp.Server.AddResource panic.
+func instanceResourceURI(name string) (string, error) {
+	uri := instanceResourceScheme + url.PathEscape(name)
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("name is not valid in a resource URI: %w", err)
+	}
+	if parsed.Host != name || parsed.Path != "" {
+		return "", fmt.Errorf("name is not valid in a resource URI %s", uri)
+	}
+	return uri, nil
+}
+
+func instanceResourceDescription(instance model.Instance) string {
+	if instance.Description != "" {
+		return instance.Description
+	}
+	return fmt.Sprintf("PostgreSQL instance %s", instance.Name)
+}
+
+func jsonResourceResult(uri string, data interface{}) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     formatJSON(data),
+			},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncInstanceResources_EscapesOrSkipsNamesInvalidInURIs(t *testing.T) {
+	ctx := context.Background()
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetDatabaseSizes", mock.Anything).Return([]pg.DatabaseSize{{DatabaseName: "shop"}}, nil)
+
+	server, session := connect(t, client, Stores{},
+		model.Instance{Name: "prod db", DatabaseName: "shop"},
+		model.Instance{Name: "db:primary"},
+		model.Instance{Name: "50%", DatabaseName: "my shop"},
+	)
+
+	require.NotPanics(t, func() { require.NoError(t, server.SyncInstanceResources(ctx)) })
+
+	result, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+
+	var uris []string
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	assert.ElementsMatch(t, []string{
+		"instances://list",
+		"instances://50%25",
+		"instances://50%25/settings",
+		"instances://50%25/databases",
+		"instances://50%25/schema/my%20shop",
+	}, uris)
+
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "instances://50%25/databases"})
+	require.NoError(t, err)
+	assert.Contains(t, read.Contents[0].Text, `"shop"`)
+}
@@ -2,41 +2,122 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"psql-mcp-registry/internal/access"
 	"psql-mcp-registry/internal/model"
 	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/waitprofile"
 
+	"github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	// TransportHTTP serves streamable HTTP and SSE on the MCP port
+	TransportHTTP = "http"
+	// TransportStdio serves a single client over stdin/stdout
+	TransportStdio = "stdio"
+
+	StreamableHTTPPath = "/mcp"
+	SSEPath            = "/sse"
+)
+
 type InstanceManager interface {
 	GetInstance(ctx context.Context, name string) (*model.Instance, error)
 	ListInstances(ctx context.Context) ([]model.Instance, error)
 }
 
+// AuditLog stores the steps of state-changing actions such as cancel_backend
+type AuditLog interface {
+	RecordAudit(ctx context.Context, record *model.AuditRecord) error
+}
+
+// SettingsBaselines stores the named settings profiles used by settings_drift
+type SettingsBaselines interface {
+	SaveSettingsBaseline(ctx context.Context, baseline *model.SettingsBaseline) error
+	GetSettingsBaseline(ctx context.Context, name string) (*model.SettingsBaseline, error)
+	ListSettingsBaselines(ctx context.Context) ([]model.SettingsBaseline, error)
+}
+
+// AlertStore stores alert rules, the alert states written by the alerting engine and silences
+type AlertStore interface {
+	SaveAlertRule(ctx context.Context, rule *model.AlertRule) error
+	ListAlertRules(ctx context.Context) ([]model.AlertRule, error)
+	ListAlertStates(ctx context.Context) ([]model.AlertState, error)
+	SaveAlertSilence(ctx context.Context, silence *model.AlertSilence) error
+	ListActiveSilences(ctx context.Context, at time.Time) ([]model.AlertSilence, error)
+}
+
+// StatementsSnapshots stores pg_stat_statements snapshots taken on demand or by the collector
+type StatementsSnapshots interface {
+	SaveStatementsSnapshot(ctx context.Context, snapshot *model.StatementsSnapshot) error
+	GetStatementsSnapshot(ctx context.Context, id int) (*model.StatementsSnapshot, error)
+	FindStatementsSnapshot(ctx context.Context, instanceName string, at time.Time) (*model.StatementsSnapshot, error)
+	ListStatementsSnapshots(ctx context.Context, instanceName string, limit int) ([]model.StatementsSnapshot, error)
+}
+
+// WaitProfiles stores the wait profile windows recorded by the history recorder
+type WaitProfiles interface {
+	ListWaitProfiles(ctx context.Context, instanceName string, since time.Time) ([]waitprofile.Profile, error)
+}
+
+// Stores are the registry tables the MCP server reads and writes besides instances.
+// A nil store disables the tools that need it.
+type Stores struct {
+	Audit        AuditLog
+	Baselines    SettingsBaselines
+	Alerts       AlertStore
+	Snapshots    StatementsSnapshots
+	WaitProfiles WaitProfiles
+}
+
 type MCPServer struct {
 	server  *mcp.Server
 	router  *router.Router
 	manager InstanceManager
+
+	access        *access.Config
+	confirmations *access.Confirmations
+	audit         AuditLog
+	baselines     SettingsBaselines
+	alerts        AlertStore
+	snapshots     StatementsSnapshots
+	waitProfiles  WaitProfiles
 }
 
-func NewMCPServer(router *router.Router, manager InstanceManager) *MCPServer {
+// NewMCPServer creates the MCP server. accessConfig may be nil, in which case every
+// caller is a viewer and state-changing tools are refused.
+func NewMCPServer(router *router.Router, manager InstanceManager, accessConfig *access.Config, stores Stores) *MCPServer {
 	impl := &mcp.Implementation{
 		Name:    "psql-mcp-registry",
 		Version: "v1.0.0",
 	}
 
+	if accessConfig == nil {
+		accessConfig = access.DefaultConfig()
+	}
+
 	server := mcp.NewServer(impl, nil)
 	mcpServer := &MCPServer{
-		server:  server,
-		router:  router,
-		manager: manager,
+		server:        server,
+		router:        router,
+		manager:       manager,
+		access:        accessConfig,
+		confirmations: access.NewConfirmations(access.DefaultConfirmTTL),
+		audit:         stores.Audit,
+		baselines:     stores.Baselines,
+		alerts:        stores.Alerts,
+		snapshots:     stores.Snapshots,
+		waitProfiles:  stores.WaitProfiles,
 	}
 
 	mcpServer.registerTools()
 	mcpServer.registerResources()
+	mcpServer.registerPrompts()
 
 	return mcpServer
 }
@@ -49,86 +130,286 @@ func (s *MCPServer) registerResources() {
 		Description: "List of all registered PostgreSQL instances",
 		MIMEType:    "application/json",
 	}, s.handleListInstancesResource)
+
+	s.registerResourceTemplates()
 }
 
 func (s *MCPServer) registerTools() {
 	// Database Overview
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "database_overview",
 		Description: "Get overview statistics for a PostgreSQL database including transactions, blocks, tuples, and other metrics",
 	}, s.handleDatabaseOverview)
 
 	// Cache Hit Rate
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "cache_hit_rate",
 		Description: "Get cache hit rate statistics (global or per database) to monitor buffer cache efficiency",
 	}, s.handleCacheHitRate)
 
 	// Checkpoints Stats
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "checkpoints_stats",
 		Description: "Get checkpoint statistics including timed and requested checkpoints, buffers written, and sync times",
 	}, s.handleCheckpointsStats)
 
 	// WAL Activity
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "wal_activity",
 		Description: "Get Write-Ahead Log activity statistics including WAL records, bytes, and FPI",
 	}, s.handleWalActivity)
 
 	// Tables Info
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "tables_info",
 		Description: "Get information about tables including size, row count, and access patterns",
 	}, s.handleTablesInfo)
 
 	// Locking Info
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "locking_info",
 		Description: "Get locking information for a database to identify blocking queries and lock conflicts",
 	}, s.handleLockingInfo)
 
 	// Changed Settings
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "changed_settings",
 		Description: "Get PostgreSQL settings that differ from defaults to review configuration changes",
 	}, s.handleChangedSettings)
 
 	// Version
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "version",
 		Description: "Get PostgreSQL version information",
 	}, s.handleVersion)
 
 	// Index Stats
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "index_stats",
 		Description: "Get index usage statistics to identify unused or inefficient indexes",
 	}, s.handleIndexStats)
 
 	// Active Queries
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "active_queries",
 		Description: "Get currently running queries with duration exceeding threshold for real-time performance diagnostics",
 	}, s.handleActiveQueries)
 
 	// Connection Stats
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "connection_stats",
 		Description: "Get connection pool statistics including active, idle, and waiting connections",
 	}, s.handleConnectionStats)
 
 	// Slow Queries
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "slow_queries",
 		Description: "Get top slow queries from pg_stat_statements with execution time and cache hit rate metrics",
 	}, s.handleSlowQueries)
 
 	// Database Sizes
-	mcp.AddTool(s.server, &mcp.Tool{
+	addTool(s.server, &mcp.Tool{
 		Name:        "database_sizes",
 		Description: "Get sizes of all databases to monitor disk space usage and data growth",
 	}, s.handleDatabaseSizes)
+
+	// Instance Health
+	addTool(s.server, &mcp.Tool{
+		Name:        "instance_health",
+		Description: "Check reachability of a PostgreSQL instance, the state of its circuit breaker (closed, open, half_open) and its query slot usage and queue depth",
+	}, s.handleInstanceHealth)
+
+	// Replication Status
+	addTool(s.server, &mcp.Tool{
+		Name:        "replication_status",
+		Description: "Get replication health: the instance role and, on a primary, per-standby write/flush/replay lag in bytes and time and sync state; on a standby, WAL receiver state and replay delay",
+	}, s.handleReplicationStatus)
+
+	// Replication Slots
+	addTool(s.server, &mcp.Tool{
+		Name:        "replication_slots",
+		Description: "List replication slots with type, active flag, restart_lsn, retained WAL bytes, wal_status and safe_wal_size, and flag inactive slots retaining more WAL than a threshold. Runs on all registered instances when instance_name is omitted",
+	}, s.handleReplicationSlots)
+
+	// XID Wraparound
+	addTool(s.server, &mcp.Tool{
+		Name:        "xid_wraparound",
+		Description: "Get transaction ID and multixact age (age(datfrozenxid), mxid_age(datminmxid)) per database as a percentage of autovacuum_freeze_max_age and of the wraparound limit, and the backends, prepared transactions and replication slots holding back the xmin horizon",
+	}, s.handleXidWraparound)
+
+	// Table Freeze Age
+	addTool(s.server, &mcp.Tool{
+		Name:        "table_freeze_age",
+		Description: "Get the tables of a database with the oldest relfrozenxid, including TOAST tables, with their freeze age as a percentage of autovacuum_freeze_max_age and of the wraparound limit",
+	}, s.handleTableFreezeAge)
+
+	// Maintenance Progress
+	addTool(s.server, &mcp.Tool{
+		Name:        "maintenance_progress",
+		Description: "Get the progress of running VACUUM and autovacuum, ANALYZE (PG 13+), CREATE INDEX/REINDEX and CLUSTER/VACUUM FULL (PG 12+): phase, percentage of blocks scanned and elapsed time, with the table and the session running it",
+	}, s.handleMaintenanceProgress)
+
+	// Table Bloat
+	addTool(s.server, &mcp.Tool{
+		Name:        "table_bloat",
+		Description: "Get the tables of a database with the most wasted space: real size, wasted bytes and bloat percentage, estimated from pg_stats or, with exact, measured with pgstattuple on the largest tables",
+	}, s.handleTableBloat)
+
+	// Index Bloat
+	addTool(s.server, &mcp.Tool{
+		Name:        "index_bloat",
+		Description: "Get the btree indexes of a database with the most wasted space: real size, wasted bytes and bloat percentage, estimated from pg_stats or, with exact, measured with pgstatindex on the largest indexes",
+	}, s.handleIndexBloat)
+
+	// Index Advisor
+	addTool(s.server, &mcp.Tool{
+		Name:        "index_advisor",
+		Description: "Find unused, duplicate, redundant (prefix of another index) and invalid indexes and foreign keys without a supporting index in a database, with their size and a suggested DDL statement. Nothing is executed",
+	}, s.handleIndexAdvisor)
+
+	// Lock Graph
+	addTool(s.server, &mcp.Tool{
+		Name:        "lock_graph",
+		Description: "Build the lock wait graph of an instance from pg_locks and pg_blocking_pids: root blockers with the number of sessions waiting behind them and the chain depth, and one edge per waiter/blocker pair with lock type, relation, lock modes and the queries of both sides",
+	}, s.handleLockGraph)
+
+	// Cancel Backend
+	addTool(s.server, &mcp.Tool{
+		Name:        "cancel_backend",
+		Description: "Cancel the running query of a backend (pg_cancel_backend). Requires the operator role. The first call checks the backend and returns a confirm_token; call again with the same arguments and the token within two minutes to cancel. pid and query_start must come from active_queries; superuser and replication backends are refused",
+	}, s.handleCancelBackend)
+
+	// Terminate Backend
+	addTool(s.server, &mcp.Tool{
+		Name:        "terminate_backend",
+		Description: "Terminate a backend and its connection (pg_terminate_backend). Requires the admin role. The first call checks the backend and returns a confirm_token; call again with the same arguments and the token within two minutes to terminate. pid and query_start must come from active_queries; superuser and replication backends are refused",
+	}, s.handleTerminateBackend)
+
+	// List Schemas
+	addTool(s.server, &mcp.Tool{
+		Name:        "list_schemas",
+		Description: "List the user schemas of a database with their owner and the number of tables, views and functions",
+	}, s.handleListSchemas)
+
+	// List Tables
+	addTool(s.server, &mcp.Tool{
+		Name:        "list_tables",
+		Description: "List the tables of a database, optionally of one schema, with their columns and types, estimated rows and size. Paginated with limit and offset; next_offset is set while more tables follow",
+	}, s.handleListTables)
+
+	// Describe Table
+	addTool(s.server, &mcp.Tool{
+		Name:        "describe_table",
+		Description: "Describe one table or view: columns with types, defaults and nullability, primary key, foreign key, unique and check constraints, index definitions (pg_get_indexdef) and, for views, the view definition",
+	}, s.handleDescribeTable)
+
+	// List Views
+	addTool(s.server, &mcp.Tool{
+		Name:        "list_views",
+		Description: "List the views and materialized views of a database, optionally of one schema, with their definitions. Paginated with limit and offset",
+	}, s.handleListViews)
+
+	// List Functions
+	addTool(s.server, &mcp.Tool{
+		Name:        "list_functions",
+		Description: "List the functions and procedures of a database, optionally of one schema, with their arguments, result type, language and volatility. Paginated with limit and offset",
+	}, s.handleListFunctions)
+
+	// Schema Diff
+	addTool(s.server, &mcp.Tool{
+		Name:        "schema_diff",
+		Description: "Compare the schema of two databases, on two instances or on one: missing and extra tables and columns, column type and nullability changes, constraint and index differences and extension version mismatches. Modes: changes (default), summary, or patch with a DDL script that would make the target match the source. Nothing is executed",
+	}, s.handleSchemaDiff)
+
+	// Settings Drift
+	addTool(s.server, &mcp.Tool{
+		Name:        "settings_drift",
+		Description: "Compare pg_settings between instances: the named ones, every instance matching a label selector such as env=prod, or all registered instances, optionally against a baseline stored in the registry. Values are normalized (8GB, 5min, on) before comparing; settings changed in the configuration but waiting for a restart are flagged",
+	}, s.handleSettingsDrift)
+
+	// Save Settings Baseline
+	addTool(s.server, &mcp.Tool{
+		Name:        "save_settings_baseline",
+		Description: "Store a named settings baseline in the registry for settings_drift, captured from the non-default settings of an instance and/or given explicitly in postgresql.conf syntax. Replaces a baseline with the same name. Requires the operator role",
+	}, s.handleSaveSettingsBaseline)
+
+	// Settings Baselines
+	addTool(s.server, &mcp.Tool{
+		Name:        "settings_baselines",
+		Description: "List the settings baselines stored in the registry",
+	}, s.handleSettingsBaselines)
+
+	// Tuning Advisor
+	addTool(s.server, &mcp.Tool{
+		Name:        "tuning_advisor",
+		Description: "Review shared_buffers, effective_cache_size, work_mem, max_wal_size, checkpoint_timeout, random_page_cost, autovacuum and max_connections against host RAM (optional) and observed load: requested vs timed checkpoints, temp file usage and connection headroom. Each finding has a severity, the current and suggested value and a rationale. Nothing is changed",
+	}, s.handleTuningAdvisor)
+
+	// Health Report
+	addTool(s.server, &mcp.Tool{
+		Name:        "health_report",
+		Description: "Run the cache hit, dead tuple, connection, checkpoint, autovacuum, deadlock and temp file checks of an instance at once and score them against configurable thresholds. Returns a score from 0 to 100, a status and findings ranked by severity with the evidence behind them",
+	}, s.handleHealthReport)
+
+	// Alerting
+	addTool(s.server, &mcp.Tool{
+		Name:        "save_alert_rule",
+		Description: "Store an alert rule in the registry: a condition on the output of a read-only action, such as connection_stats with active / max_connections > 0.9 for 5m, evaluated on a schedule on every instance matching a label selector. Replaces a rule with the same name and returns the condition evaluated once on each instance. Requires the operator role",
+	}, s.handleSaveAlertRule)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "alert_rules",
+		Description: "List the alert rules stored in the registry",
+	}, s.handleAlertRules)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "alerts",
+		Description: "List pending, firing and resolved alerts tracked by the alerting engine, with notification and silence status, and the active silences",
+	}, s.handleAlerts)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "silence_alert",
+		Description: "Suppress notifications of a rule, of an instance, or of a rule on an instance for a duration. Alerts keep being evaluated; a firing alert is announced when the silence ends if it still fires. Requires the operator role",
+	}, s.handleSilenceAlert)
+
+	// Top Statements
+	addTool(s.server, &mcp.Tool{
+		Name:        "top_statements",
+		Description: "Get the top pg_stat_statements entries ordered by total time, mean time, calls, rows, shared reads, temp blocks or WAL bytes, filtered by database and user, with queryid, planning, I/O timing and WAL columns and the deallocation and reset info of pg_stat_statements",
+	}, s.handleTopStatements)
+
+	// Statements Snapshots
+	addTool(s.server, &mcp.Tool{
+		Name:        "capture_statements_snapshot",
+		Description: "Store the current pg_stat_statements counters of an instance in the registry, keyed by queryid, for later diffing with statements_diff. Requires the operator role",
+	}, s.handleCaptureStatementsSnapshot)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "statements_snapshots",
+		Description: "List the stored pg_stat_statements snapshots of an instance, newest first",
+	}, s.handleStatementsSnapshots)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "statements_diff",
+		Description: "Diff two pg_stat_statements snapshots, by snapshot IDs or over a time window ending now, to find workload regressions: statements whose calls, mean execution time or shared reads grew the most between them, and statements that are new",
+	}, s.handleStatementsDiff)
+
+	// Wait Profile
+	addTool(s.server, &mcp.Tool{
+		Name:        "wait_profile",
+		Description: "Sample the active sessions of pg_stat_activity at a fixed interval (default every 100ms for 10s) and aggregate them into a wait event profile: share of active session time and average active sessions per wait event (CPU when not waiting), per query and per user",
+	}, s.handleWaitProfile)
+
+	addTool(s.server, &mcp.Tool{
+		Name:        "wait_profile_history",
+		Description: "Merge the wait profiles recorded continuously for an instance over a time window (default 1h) into one profile per wait event, query and user. Requires the history recorder to be enabled",
+	}, s.handleWaitProfileHistory)
+
+	// I/O Stats
+	addTool(s.server, &mcp.Tool{
+		Name:        "io_stats",
+		Description: "Get I/O statistics. On PostgreSQL 16+ from pg_stat_io by backend type, object and context: reads, writes, writebacks, extends, hits, evictions, reuses and fsyncs with their timings. On older versions per-table heap, index and TOAST block reads with cache hit rates, and per-index block reads, from pg_statio_user_tables and pg_statio_user_indexes of the connected database",
+	}, s.handleIOStats)
 }
 
 // Run starts the MCP server over stdio transport
@@ -141,15 +422,31 @@ func (s *MCPServer) RunWithTransport(ctx context.Context, transport mcp.Transpor
 	return s.server.Run(ctx, transport)
 }
 
-// RunWithSSE starts the MCP server with SSE transport over HTTP
-func (s *MCPServer) RunWithSSE(ctx context.Context, port string) error {
-	handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
+// RunWithHTTP starts the MCP server over HTTP on the given port, serving both
+// the streamable HTTP transport (/mcp) and the legacy SSE transport (/sse).
+// Streamable sessions are tracked by the Mcp-Session-Id header and keep their
+// events in memory, so clients can resume a broken stream with Last-Event-ID.
+// When auth tokens are configured both endpoints require a bearer token.
+func (s *MCPServer) RunWithHTTP(ctx context.Context, port string) error {
+	getServer := func(*http.Request) *mcp.Server {
 		return s.server
-	}, nil)
+	}
+
+	var streamable http.Handler = mcp.NewStreamableHTTPHandler(getServer, nil)
+	var sse http.Handler = mcp.NewSSEHandler(getServer, nil)
+	if s.access.RequiresAuth() {
+		requireToken := auth.RequireBearerToken(s.access.Verifier(), nil)
+		streamable = requireToken(streamable)
+		sse = requireToken(sse)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(StreamableHTTPPath, streamable)
+	mux.Handle(SSEPath, sse)
 
 	httpServer := &http.Server{
 		Addr:    ":" + port,
-		Handler: handler,
+		Handler: mux,
 	}
 
 	go func() {
@@ -157,7 +454,19 @@ func (s *MCPServer) RunWithSSE(ctx context.Context, port string) error {
 		httpServer.Shutdown(context.Background())
 	}()
 
-	return httpServer.ListenAndServe()
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// principal returns the caller of a tool as identified by its bearer token
+func (s *MCPServer) principal(req *mcp.CallToolRequest) access.Principal {
+	var tokenInfo *auth.TokenInfo
+	if req != nil && req.Extra != nil {
+		tokenInfo = req.Extra.TokenInfo
+	}
+	return s.access.Principal(tokenInfo)
 }
 
 func createRouterRequest(instanceName string, action model.ActionName, params map[string]interface{}) router.QueryRequest {
@@ -186,3 +495,25 @@ func (s *MCPServer) executeRouterQuery(ctx context.Context, instanceName string,
 
 	return response.Data, nil
 }
+
+// executeRouterFanOut runs the action on the named instance or, when the name is
+// empty, on every registered instance. Per-instance failures are returned in the responses.
+func (s *MCPServer) executeRouterFanOut(ctx context.Context, instanceName string, action model.ActionName, params map[string]interface{}) ([]*router.QueryResponse, error) {
+	var instances []model.Instance
+	if instanceName != "" {
+		instance, err := s.manager.GetInstance(ctx, instanceName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get instance: %w", err)
+		}
+		instances = []model.Instance{*instance}
+	} else {
+		var err error
+		instances, err = s.manager.ListInstances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+	}
+
+	req := createRouterRequest(instanceName, action, params)
+	return s.router.RouteAll(ctx, req, instances), nil
+}
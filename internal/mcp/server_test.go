@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+	"psql-mcp-registry/internal/router"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManager serves a fixed set of registered instances
+type fakeManager struct {
+	instances []model.Instance
+}
+
+func (m fakeManager) GetInstance(_ context.Context, name string) (*model.Instance, error) {
+	for _, instance := range m.instances {
+		if instance.Name == name {
+			return &instance, nil
+		}
+	}
+	return nil, fmt.Errorf("instance %s not found", name)
+}
+
+func (m fakeManager) ListInstances(context.Context) ([]model.Instance, error) {
+	return m.instances, nil
+}
+
+// fakeRegistry hands out the same client for every instance
+type fakeRegistry struct {
+	client pg.ClientInterface
+}
+
+func (fakeRegistry) AddInstanceToRegistry(model.Instance) error { return nil }
+
+func (r fakeRegistry) GetInstanceClient(model.Instance) pg.ClientInterface { return r.client }
+
+// connect starts the MCP server over in-memory transports and returns a client session to it
+func connect(t *testing.T, client *pgmocks.ClientInterface, stores Stores, instances ...model.Instance) (*MCPServer, *mcp.ClientSession) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := NewMCPServer(router.New(fakeRegistry{client}), fakeManager{instances}, nil, stores)
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	_, err := server.server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+
+	return server, session
+}
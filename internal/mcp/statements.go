@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/statements"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func (s *MCPServer) handleCaptureStatementsSnapshot(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CaptureStatementsSnapshotInput,
+) (*mcp.CallToolResult, *CaptureStatementsSnapshotOutput, error) {
+	principal := s.principal(req)
+	if !principal.Can(model.ActionNameSaveStatementsSnapshot) {
+		return nil, nil, fmt.Errorf("user %s with role %s may not save statements snapshots, role %s is required",
+			principal.User, principal.Role, access.RequiredRole(model.ActionNameSaveStatementsSnapshot))
+	}
+	if s.snapshots == nil {
+		return nil, nil, fmt.Errorf("statements snapshots are not configured")
+	}
+
+	snapshot, err := s.captureStatements(ctx, input.InstanceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot.Comment = input.Comment
+	snapshot.CreatedBy = principal.User
+
+	if err := s.snapshots.SaveStatementsSnapshot(ctx, snapshot); err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&CaptureStatementsSnapshotOutput{StatementsSnapshot: statementsSnapshotOutput(*snapshot)})
+}
+
+func (s *MCPServer) handleStatementsSnapshots(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input StatementsSnapshotsInput,
+) (*mcp.CallToolResult, *StatementsSnapshotsOutput, error) {
+	if s.snapshots == nil {
+		return nil, nil, fmt.Errorf("statements snapshots are not configured")
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	snapshots, err := s.snapshots.ListStatementsSnapshots(ctx, input.InstanceName, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &StatementsSnapshotsOutput{
+		Instance:  input.InstanceName,
+		Snapshots: make([]StatementsSnapshot, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		output.Snapshots = append(output.Snapshots, statementsSnapshotOutput(snapshot))
+	}
+
+	return toolResult(output)
+}
+
+func (s *MCPServer) handleStatementsDiff(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input StatementsDiffInput,
+) (*mcp.CallToolResult, *StatementsDiffOutput, error) {
+	if s.snapshots == nil {
+		return nil, nil, fmt.Errorf("statements snapshots are not configured")
+	}
+	if (input.FromID > 0) == (input.Window != "") {
+		return nil, nil, fmt.Errorf("either from_id or window is required")
+	}
+
+	var from *model.StatementsSnapshot
+	if input.FromID > 0 {
+		snapshot, err := s.snapshots.GetStatementsSnapshot(ctx, input.FromID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get statements snapshot %d: %w", input.FromID, err)
+		}
+		from = snapshot
+	} else {
+		if input.InstanceName == "" {
+			return nil, nil, fmt.Errorf("instance_name is required with window")
+		}
+		window, err := time.ParseDuration(input.Window)
+		if err != nil || window <= 0 {
+			return nil, nil, fmt.Errorf("invalid window %q, expected a positive duration such as 10m", input.Window)
+		}
+		at := time.Now().Add(-window)
+		snapshot, err := s.snapshots.FindStatementsSnapshot(ctx, input.InstanceName, at)
+		if err != nil {
+			return nil, nil, fmt.Errorf("no statements snapshot of %s taken before %s (%w); take one with capture_statements_snapshot or enable the collector",
+				input.InstanceName, at.UTC().Format(timeLayout), err)
+		}
+		from = snapshot
+	}
+
+	instanceName := input.InstanceName
+	if instanceName == "" {
+		instanceName = from.InstanceName
+	}
+	if from.InstanceName != instanceName {
+		return nil, nil, fmt.Errorf("snapshot %d belongs to instance %s, not %s", from.ID, from.InstanceName, instanceName)
+	}
+
+	var to *model.StatementsSnapshot
+	if input.ToID > 0 {
+		snapshot, err := s.snapshots.GetStatementsSnapshot(ctx, input.ToID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get statements snapshot %d: %w", input.ToID, err)
+		}
+		if snapshot.InstanceName != instanceName {
+			return nil, nil, fmt.Errorf("snapshot %d belongs to instance %s, not %s", snapshot.ID, snapshot.InstanceName, instanceName)
+		}
+		to = snapshot
+	} else {
+		snapshot, err := s.captureStatements(ctx, instanceName)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = snapshot
+	}
+
+	if !to.CreatedAt.After(from.CreatedAt) {
+		return nil, nil, fmt.Errorf("snapshot %d is not older than the snapshot it is compared to", from.ID)
+	}
+
+	diff := statements.Compare(from, to, input.Limit)
+
+	return toolResult(&StatementsDiffOutput{Instance: instanceName, Diff: *diff})
+}
+
+// captureStatements reads the current pg_stat_statements counters of an instance
+// into a snapshot that is not stored
+func (s *MCPServer) captureStatements(ctx context.Context, instanceName string) (*model.StatementsSnapshot, error) {
+	data, err := s.executeRouterQuery(ctx, instanceName, model.ActionNameStatementsSnapshot, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := routerData[*model.StatementsSnapshot](data)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.InstanceName = instanceName
+	snapshot.CreatedAt = time.Now()
+
+	return snapshot, nil
+}
+
+func statementsSnapshotOutput(snapshot model.StatementsSnapshot) StatementsSnapshot {
+	return StatementsSnapshot{
+		ID:         snapshot.ID,
+		Instance:   snapshot.InstanceName,
+		TakenAt:    snapshot.CreatedAt,
+		Statements: snapshot.Count,
+		StatsReset: snapshot.StatsReset,
+		Comment:    snapshot.Comment,
+		CreatedBy:  snapshot.CreatedBy,
+	}
+}
@@ -0,0 +1,1546 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/settingsdrift"
+	"psql-mcp-registry/internal/statements"
+	"psql-mcp-registry/internal/waitprofile"
+)
+
+// Text renderings are the human-readable content block of a tool result, for
+// clients that do not use structured content. They summarize, the structured
+// output stays the source of truth.
+
+const (
+	maxQueryTextLength = 120
+	timeLayout         = "2006-01-02 15:04:05Z"
+)
+
+func (o *DatabaseOverviewOutput) Text() string {
+	v := o.Overview
+	var b strings.Builder
+	fmt.Fprintf(&b, "Database %s on instance %s\n", o.Database, o.Instance)
+	fmt.Fprintf(&b, "Transactions: %d committed, %d rolled back\n", v.XactCommit, v.XactRollback)
+	fmt.Fprintf(&b, "Blocks: %d hit, %d read\n", v.BlksHit, v.BlksRead)
+	fmt.Fprintf(&b, "Tuples: %d returned, %d fetched, %d inserted, %d updated, %d deleted\n",
+		v.TupReturned, v.TupFetched, v.TupInserted, v.TupUpdated, v.TupDeleted)
+	fmt.Fprintf(&b, "Temp files: %d (%s)\n", v.TempFiles, formatBytes(v.TempBytes))
+	fmt.Fprintf(&b, "Deadlocks: %d, conflicts: %d\n", v.Deadlocks, v.Conflicts)
+	fmt.Fprintf(&b, "Block I/O time: %.1f ms read, %.1f ms write", v.BlkReadTime, v.BlkWriteTime)
+	return b.String()
+}
+
+func (o *CacheHitRateOutput) Text() string {
+	scope := "all databases"
+	if o.Database != "" {
+		scope = "database " + o.Database
+	}
+	if !o.HitRate.Valid {
+		return fmt.Sprintf("Cache hit rate for %s on instance %s: no block activity yet", scope, o.Instance)
+	}
+	return fmt.Sprintf("Cache hit rate for %s on instance %s: %.2f%%", scope, o.Instance, o.HitRate.Float64*100)
+}
+
+func (o *CheckpointsStatsOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Checkpoints on instance %s\n", o.Instance)
+	fmt.Fprintf(&b, "Timed: %d, requested: %d\n", o.CheckpointsTimed, o.CheckpointsReq)
+	fmt.Fprintf(&b, "Write time: %.0f ms, sync time: %.0f ms", o.CheckpointWriteTime, o.CheckpointSyncTime)
+	if o.BuffersCheckpoint.Valid {
+		fmt.Fprintf(&b, "\nBuffers written: %d by checkpointer, %d by backends (%d backend fsyncs), %d allocated",
+			o.BuffersCheckpoint.Int64, o.BuffersBackend.Int64, o.BuffersBackendFsync.Int64, o.BuffersAlloc.Int64)
+	}
+	return b.String()
+}
+
+func (o *WalActivityOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "WAL activity on instance %s", o.Instance)
+	if o.StatsReset.Valid {
+		fmt.Fprintf(&b, " since %s", formatTime(o.StatsReset))
+	}
+	fmt.Fprintf(&b, "\nRecords: %d, full page images: %d, bytes: %s, buffers full: %d",
+		o.WalRecords, o.WalFpi, formatBytes(o.WalBytes), o.WalBuffersFull)
+	return b.String()
+}
+
+func (o *IOStatsOutput) Text() string {
+	var b strings.Builder
+	since := ""
+	if o.StatsReset.Valid {
+		since = " since " + formatTime(o.StatsReset)
+	}
+
+	if o.Source == pg.IOSourceStatIO {
+		if len(o.IO) == 0 {
+			return fmt.Sprintf("No I/O recorded in pg_stat_io on instance %s%s", o.Instance, since)
+		}
+		return renderTable(
+			fmt.Sprintf("I/O by backend type, object and context on instance %s%s (times in ms)", o.Instance, since),
+			[]string{"BACKEND", "OBJECT", "CONTEXT", "READS", "READ MS", "HITS", "HIT RATE", "WRITES", "WRITE MS",
+				"WRITEBACKS", "EXTENDS", "EVICTIONS", "REUSES", "FSYNCS", "FSYNC MS"},
+			len(o.IO),
+			func(i int) []string {
+				io := o.IO[i]
+				return []string{
+					io.BackendType,
+					io.Object,
+					io.Context,
+					formatOptionalInt(io.Reads),
+					formatOptionalFloat(io.ReadTime),
+					formatOptionalInt(io.Hits),
+					formatHitRate(io.HitRate),
+					formatOptionalInt(io.Writes),
+					formatOptionalFloat(io.WriteTime),
+					formatOptionalInt(io.Writebacks),
+					formatOptionalInt(io.Extends),
+					formatOptionalInt(io.Evictions),
+					formatOptionalInt(io.Reuses),
+					formatOptionalInt(io.Fsyncs),
+					formatOptionalFloat(io.FsyncTime),
+				}
+			},
+		)
+	}
+
+	fmt.Fprintf(&b, "pg_stat_io needs PostgreSQL 16, showing block I/O of the connected database on instance %s%s", o.Instance, since)
+	if len(o.Tables) == 0 {
+		b.WriteString("\n\nNo user tables found")
+		return b.String()
+	}
+	b.WriteString("\n\n")
+	b.WriteString(renderTable(
+		fmt.Sprintf("Top %d tables by blocks read", len(o.Tables)),
+		[]string{"TABLE", "HEAP READ", "HEAP HIT RATE", "INDEX READ", "INDEX HIT RATE", "TOAST READ", "TOAST HIT RATE"},
+		len(o.Tables),
+		func(i int) []string {
+			t := o.Tables[i]
+			return []string{
+				t.Schema + "." + t.Table,
+				fmt.Sprint(t.HeapBlksRead),
+				formatHitRate(t.HeapHitRate),
+				formatOptionalInt(t.IdxBlksRead),
+				formatHitRate(t.IdxHitRate),
+				formatOptionalInt(t.ToastBlksRead),
+				formatHitRate(t.ToastHitRate),
+			}
+		},
+	))
+	if len(o.Indexes) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			fmt.Sprintf("Top %d indexes by blocks read", len(o.Indexes)),
+			[]string{"INDEX", "TABLE", "READ", "HIT", "HIT RATE"},
+			len(o.Indexes),
+			func(i int) []string {
+				idx := o.Indexes[i]
+				return []string{
+					idx.Schema + "." + idx.Index,
+					idx.Table,
+					fmt.Sprint(idx.BlksRead),
+					fmt.Sprint(idx.BlksHit),
+					formatHitRate(idx.HitRate),
+				}
+			},
+		))
+	}
+	return b.String()
+}
+
+func (o *TablesInfoOutput) Text() string {
+	if len(o.Tables) == 0 {
+		return fmt.Sprintf("No tables found on instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d tables on instance %s", len(o.Tables), o.Instance),
+		[]string{"TABLE", "TOTAL", "TABLE SIZE", "INDEXES", "LIVE", "DEAD", "DEAD %", "LAST AUTOVACUUM"},
+		len(o.Tables),
+		func(i int) []string {
+			t := o.Tables[i]
+			return []string{
+				t.SchemaName + "." + t.TableName,
+				formatBytes(t.TotalBytes),
+				formatBytes(t.TableBytes),
+				formatBytes(t.IndexesBytes),
+				formatOptionalInt(t.NLiveTup),
+				formatOptionalInt(t.NDeadTup),
+				formatOptionalFloat(t.DeadRatio),
+				formatTime(t.LastAutovacuum),
+			}
+		},
+	)
+}
+
+func (o *LockingInfoOutput) Text() string {
+	if len(o.Locks) == 0 {
+		return fmt.Sprintf("No waiting or blocked sessions in database %s on instance %s", o.Database, o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d waiting or blocked sessions in database %s on instance %s", len(o.Locks), o.Database, o.Instance),
+		[]string{"PID", "USER", "STATE", "WAIT", "QUERY START", "BLOCKED BY"},
+		len(o.Locks),
+		func(i int) []string {
+			l := o.Locks[i]
+			return []string{
+				fmt.Sprint(l.PID),
+				formatOptionalString(l.Username),
+				formatOptionalString(l.State),
+				formatWait(l.WaitEventType, l.WaitEvent),
+				formatTime(l.QueryStart),
+				formatPids(l.BlockingPids),
+			}
+		},
+	)
+}
+
+func (o *ChangedSettingsOutput) Text() string {
+	if len(o.Settings) == 0 {
+		return fmt.Sprintf("All settings on instance %s are at their defaults", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d non-default settings on instance %s", len(o.Settings), o.Instance),
+		[]string{"NAME", "VALUE", "UNIT", "SOURCE", "PENDING RESTART"},
+		len(o.Settings),
+		func(i int) []string {
+			s := o.Settings[i]
+			pending := ""
+			if s.PendingRestart {
+				pending = "yes"
+			}
+			return []string{s.Name, s.Setting, formatOptionalString(s.Unit), s.Source, pending}
+		},
+	)
+}
+
+func (o *VersionOutput) Text() string {
+	text := fmt.Sprintf("Instance %s runs PostgreSQL %d.%d", o.Instance, o.Version.Major, o.Version.Minor)
+	if o.Version.FullString != "" {
+		text += "\n" + o.Version.FullString
+	}
+	return text
+}
+
+func (o *IndexStatsOutput) Text() string {
+	if len(o.Indexes) == 0 {
+		return fmt.Sprintf("No indexes found on instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d indexes on instance %s", len(o.Indexes), o.Instance),
+		[]string{"INDEX", "TABLE", "SIZE", "SCANS", "TUP READ", "TUP FETCH"},
+		len(o.Indexes),
+		func(i int) []string {
+			idx := o.Indexes[i]
+			return []string{
+				idx.SchemaName + "." + idx.IndexName,
+				idx.TableName,
+				formatBytes(idx.SizeBytes),
+				formatOptionalInt(idx.IdxScan),
+				formatOptionalInt(idx.IdxTupRead),
+				formatOptionalInt(idx.IdxTupFetch),
+			}
+		},
+	)
+}
+
+func (o *ActiveQueriesOutput) Text() string {
+	if len(o.Queries) == 0 {
+		return fmt.Sprintf("No queries running longer than %ds in database %s on instance %s",
+			o.MinDurationSeconds, o.Database, o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d queries running longer than %ds in database %s on instance %s",
+			len(o.Queries), o.MinDurationSeconds, o.Database, o.Instance),
+		[]string{"PID", "USER", "STATE", "QUERY START", "DURATION", "WAIT", "QUERY"},
+		len(o.Queries),
+		func(i int) []string {
+			q := o.Queries[i]
+			return []string{
+				fmt.Sprint(q.PID),
+				formatOptionalString(q.Username),
+				formatOptionalString(q.State),
+				formatQueryStart(q.QueryStart),
+				formatOptionalSeconds(q.DurationSeconds),
+				formatWait(q.WaitEventType, q.WaitEvent),
+				truncateQuery(formatOptionalString(q.Query)),
+			}
+		},
+	)
+}
+
+func (o *ConnectionStatsOutput) Text() string {
+	c := o.Connections
+	return fmt.Sprintf("Connections on instance %s: %d of %d (%d active, %d idle, %d idle in transaction, %d waiting)",
+		o.Instance, c.TotalConnections, c.MaxConnections, c.Active, c.Idle, c.IdleInTransaction, c.Waiting)
+}
+
+func (o *SlowQueriesOutput) Text() string {
+	if len(o.Queries) == 0 {
+		return fmt.Sprintf("No statements recorded in pg_stat_statements on instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("Top %d statements by total execution time on instance %s", len(o.Queries), o.Instance),
+		[]string{"TOTAL MS", "CALLS", "MEAN MS", "ROWS", "HIT %", "QUERY"},
+		len(o.Queries),
+		func(i int) []string {
+			q := o.Queries[i]
+			return []string{
+				fmt.Sprintf("%.0f", q.TotalExecTime),
+				fmt.Sprint(q.Calls),
+				fmt.Sprintf("%.2f", q.MeanExecTime),
+				fmt.Sprint(q.Rows),
+				formatOptionalFloat(q.CacheHitPercent),
+				truncateQuery(q.Query),
+			}
+		},
+	)
+}
+
+func (o *TopStatementsOutput) Text() string {
+	var b strings.Builder
+	if len(o.Statements) == 0 {
+		fmt.Fprintf(&b, "No statements recorded in pg_stat_statements on instance %s", o.Instance)
+	} else {
+		b.WriteString(renderTable(
+			fmt.Sprintf("Top %d statements by %s on instance %s", len(o.Statements), o.OrderBy, o.Instance),
+			[]string{"QUERYID", "DATABASE", "USER", "CALLS", "TOTAL MS", "MEAN MS", "ROWS", "SHARED READS", "TEMP BLKS", "WAL", "QUERY"},
+			len(o.Statements),
+			func(i int) []string {
+				st := o.Statements[i]
+				return []string{
+					formatOptionalInt(st.QueryID),
+					formatOptionalString(st.Database),
+					formatOptionalString(st.Username),
+					fmt.Sprint(st.Calls),
+					fmt.Sprintf("%.0f", st.TotalExecTime),
+					fmt.Sprintf("%.2f", st.MeanExecTime),
+					fmt.Sprint(st.Rows),
+					fmt.Sprint(st.SharedBlksRead),
+					fmt.Sprint(st.TempBlksRead + st.TempBlksWritten),
+					formatOptionalBytes(st.WalBytes),
+					truncateQuery(st.Query),
+				}
+			},
+		))
+	}
+
+	info := o.Info
+	fmt.Fprintf(&b, "\n\npg_stat_statements tracks %d of %s statements", info.Tracked, formatOptionalInt(info.Max))
+	if info.Dealloc.Valid {
+		fmt.Fprintf(&b, ", %d deallocations", info.Dealloc.Int64)
+	}
+	if info.StatsReset.Valid {
+		fmt.Fprintf(&b, ", statistics reset at %s", formatTime(info.StatsReset))
+	}
+	return b.String()
+}
+
+func (o *CaptureStatementsSnapshotOutput) Text() string {
+	return fmt.Sprintf("Saved statements snapshot %d of instance %s with %d statements at %s",
+		o.ID, o.Instance, o.Statements, o.TakenAt.UTC().Format(timeLayout))
+}
+
+func (o *StatementsSnapshotsOutput) Text() string {
+	if len(o.Snapshots) == 0 {
+		return fmt.Sprintf("No statements snapshots stored for instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d statements snapshots of instance %s", len(o.Snapshots), o.Instance),
+		[]string{"ID", "TAKEN", "STATEMENTS", "BY", "COMMENT"},
+		len(o.Snapshots),
+		func(i int) []string {
+			sn := o.Snapshots[i]
+			return []string{
+				fmt.Sprint(sn.ID),
+				sn.TakenAt.UTC().Format(timeLayout),
+				fmt.Sprint(sn.Statements),
+				formatOptionalString(pg.NewNullString(sn.CreatedBy)),
+				formatOptionalString(pg.NewNullString(sn.Comment)),
+			}
+		},
+	)
+}
+
+func (o *StatementsDiffOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Statements of instance %s from %s to %s (%s): %d active, %d calls, %.0f ms",
+		o.Instance, formatSnapshotRef(o.From), formatSnapshotRef(o.To),
+		time.Duration(o.Seconds*float64(time.Second)).Round(time.Second), o.Active, o.Calls, o.ExecTime)
+	switch {
+	case o.StatsReset == nil:
+		b.WriteString("\nThe server does not record pg_stat_statements resets (PostgreSQL 14+ does); a reset in between goes unnoticed")
+	case *o.StatsReset:
+		b.WriteString("\npg_stat_statements was reset in between; counters of known statements start from zero")
+	}
+
+	rankings := []struct {
+		title   string
+		changes []statements.Change
+	}{
+		{"Most calls", o.ByCalls},
+		{"Mean time grew most", o.ByMeanTime},
+		{"Most shared reads", o.BySharedReads},
+		{"New statements", o.New},
+	}
+	for _, ranking := range rankings {
+		if len(ranking.changes) == 0 {
+			continue
+		}
+		changes := ranking.changes
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			ranking.title,
+			[]string{"QUERYID", "CALLS", "MEAN MS", "WAS MS", "TOTAL MS", "SHARED READS", "QUERY"},
+			len(changes),
+			func(i int) []string {
+				c := changes[i]
+				was := "-"
+				if c.PreviousMeanExecTime != nil {
+					was = fmt.Sprintf("%.2f", *c.PreviousMeanExecTime)
+				}
+				return []string{
+					fmt.Sprint(c.QueryID),
+					fmt.Sprint(c.Calls),
+					fmt.Sprintf("%.2f", c.MeanExecTime),
+					was,
+					fmt.Sprintf("%.0f", c.ExecTime),
+					fmt.Sprint(c.SharedBlksRead),
+					truncateQuery(c.Query),
+				}
+			},
+		))
+	}
+	if o.Active == 0 {
+		b.WriteString("\n\nNo statements were called in between")
+	}
+	return b.String()
+}
+
+func (o *WaitProfileOutput) Text() string {
+	title := fmt.Sprintf("Wait profile of instance %s: %d polls every %dms from %s to %s",
+		o.Instance, o.Polls, o.IntervalMS, o.Start.UTC().Format(timeLayout), o.End.UTC().Format(timeLayout))
+	return renderWaitProfile(title, o.Profile)
+}
+
+func (o *WaitProfileHistoryOutput) Text() string {
+	if o.Windows == 0 {
+		return fmt.Sprintf("No wait profiles recorded for instance %s in this window; the history recorder runs when WAIT_PROFILE_HISTORY_INTERVAL is set", o.Instance)
+	}
+	title := fmt.Sprintf("Wait profile history of instance %s: %d windows, %d polls from %s to %s",
+		o.Instance, o.Windows, o.Polls, o.Start.UTC().Format(timeLayout), o.End.UTC().Format(timeLayout))
+	return renderWaitProfile(title, o.Profile)
+}
+
+func renderWaitProfile(title string, profile waitprofile.Profile) string {
+	var b strings.Builder
+	b.WriteString(title)
+	if profile.FailedPolls > 0 {
+		fmt.Fprintf(&b, " (%d polls failed)", profile.FailedPolls)
+	}
+	fmt.Fprintf(&b, "\n%d samples, %.2f active sessions on average", profile.Samples, profile.AverageActive)
+	if profile.Samples == 0 {
+		b.WriteString("\n\nNo active sessions were seen")
+		return b.String()
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(renderShares("By wait event", "WAIT EVENT", profile.ByWaitEvent))
+
+	b.WriteString("\n\n")
+	b.WriteString(renderTable("By query",
+		[]string{"QUERYID", "SAMPLES", "%", "AAS", "TOP WAITS", "QUERY"},
+		len(profile.ByQuery),
+		func(i int) []string {
+			q := profile.ByQuery[i]
+			waits := make([]string, 0, len(q.Waits))
+			for _, wait := range q.Waits {
+				waits = append(waits, fmt.Sprintf("%s %.0f%%", wait.Key, 100*float64(wait.Samples)/float64(q.Samples)))
+			}
+			queryID := "-"
+			if q.QueryID != 0 {
+				queryID = fmt.Sprint(q.QueryID)
+			}
+			return []string{
+				queryID,
+				fmt.Sprint(q.Samples),
+				fmt.Sprintf("%.1f", q.Percent),
+				fmt.Sprintf("%.2f", q.AverageActive),
+				strings.Join(waits, ", "),
+				formatOptionalString(pg.NewNullString(truncateQuery(q.Query))),
+			}
+		},
+	))
+
+	b.WriteString("\n\n")
+	b.WriteString(renderShares("By user", "USER", profile.ByUser))
+	return b.String()
+}
+
+func renderShares(title, key string, shares []waitprofile.Share) string {
+	return renderTable(title, []string{key, "SAMPLES", "%", "AAS"}, len(shares), func(i int) []string {
+		share := shares[i]
+		return []string{
+			share.Key,
+			fmt.Sprint(share.Samples),
+			fmt.Sprintf("%.1f", share.Percent),
+			fmt.Sprintf("%.2f", share.AverageActive),
+		}
+	})
+}
+
+func (o *DatabaseSizesOutput) Text() string {
+	if len(o.Databases) == 0 {
+		return fmt.Sprintf("No databases found on instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d databases on instance %s", len(o.Databases), o.Instance),
+		[]string{"DATABASE", "SIZE"},
+		len(o.Databases),
+		func(i int) []string {
+			return []string{o.Databases[i].DatabaseName, formatBytes(o.Databases[i].SizeBytes)}
+		},
+	)
+}
+
+func (o *ReplicationStatusOutput) Text() string {
+	if o.Role == pg.ReplicationRoleStandby {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Instance %s is a standby", o.Instance)
+		if o.WalReceiver == nil {
+			b.WriteString("\nWAL receiver is not running")
+		} else {
+			w := o.WalReceiver
+			fmt.Fprintf(&b, "\nWAL receiver: %s, flushed up to %s, last message at %s",
+				w.Status, formatOptionalString(w.FlushedLsn), formatTime(w.LastMsgReceiptTime))
+		}
+		if r := o.Replay; r != nil {
+			fmt.Fprintf(&b, "\nReplay: %s behind received WAL, delay %s, last replayed commit at %s",
+				formatOptionalBytes(r.ReplayLagBytes), formatOptionalSeconds(r.ReplayDelaySeconds), formatTime(r.LastReplayTime))
+			if r.ReplayPaused {
+				b.WriteString("\nReplay is paused")
+			}
+		}
+		return b.String()
+	}
+
+	if len(o.Replicas) == 0 {
+		return fmt.Sprintf("Instance %s is a primary with no connected standbys", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("Instance %s is a primary with %d connected standbys", o.Instance, len(o.Replicas)),
+		[]string{"APPLICATION", "CLIENT", "STATE", "SYNC", "WRITE LAG", "FLUSH LAG", "REPLAY LAG", "REPLAY LAG TIME"},
+		len(o.Replicas),
+		func(i int) []string {
+			r := o.Replicas[i]
+			return []string{
+				r.ApplicationName,
+				formatOptionalString(r.ClientAddr),
+				formatOptionalString(r.State),
+				formatOptionalString(r.SyncState),
+				formatOptionalBytes(r.WriteLagBytes),
+				formatOptionalBytes(r.FlushLagBytes),
+				formatOptionalBytes(r.ReplayLagBytes),
+				formatOptionalSeconds(r.ReplayLagSeconds),
+			}
+		},
+	)
+}
+
+func (o *ReplicationSlotsOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d replication slots at risk (inactive and retaining more than %d MB of WAL, or losing WAL)",
+		o.AtRisk, o.RetainedWalThresholdMB)
+
+	for _, instance := range o.Instances {
+		b.WriteString("\n\n")
+		switch {
+		case instance.Error != "":
+			fmt.Fprintf(&b, "Instance %s: %s", instance.Instance, instance.Error)
+		case len(instance.Slots) == 0:
+			fmt.Fprintf(&b, "Instance %s has no replication slots", instance.Instance)
+		default:
+			b.WriteString(renderTable(
+				fmt.Sprintf("Instance %s", instance.Instance),
+				[]string{"SLOT", "TYPE", "ACTIVE", "RESTART LSN", "RETAINED WAL", "WAL STATUS", "SAFE WAL SIZE", "AT RISK"},
+				len(instance.Slots),
+				func(i int) []string {
+					slot := instance.Slots[i]
+					return []string{
+						slot.SlotName,
+						slot.SlotType,
+						formatBool(slot.Active),
+						formatOptionalString(slot.RestartLsn),
+						formatOptionalBytes(slot.RetainedWalBytes),
+						formatOptionalString(slot.WalStatus),
+						formatOptionalBytes(slot.SafeWalSize),
+						formatBool(slot.AtRisk),
+					}
+				},
+			))
+		}
+	}
+
+	return b.String()
+}
+
+func (o *XidWraparoundOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Transaction ID age on instance %s (autovacuum_freeze_max_age %d, autovacuum_multixact_freeze_max_age %d)",
+		o.Instance, o.FreezeMaxAge, o.MultixactFreezeMaxAge)
+
+	if len(o.Databases) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			"Databases",
+			[]string{"DATABASE", "XID AGE", "% FREEZE MAX", "% WRAPAROUND", "MXID AGE", "% MXID FREEZE MAX", "% MXID WRAPAROUND"},
+			len(o.Databases),
+			func(i int) []string {
+				db := o.Databases[i]
+				return []string{
+					db.DatabaseName,
+					fmt.Sprint(db.XidAge),
+					fmt.Sprintf("%.2f", db.XidFreezeMaxAgePct),
+					fmt.Sprintf("%.2f", db.XidWraparoundPct),
+					fmt.Sprint(db.MxidAge),
+					fmt.Sprintf("%.2f", db.MxidFreezeMaxAgePct),
+					fmt.Sprintf("%.2f", db.MxidWraparoundPct),
+				}
+			},
+		))
+	}
+
+	b.WriteString("\n\n")
+	if len(o.HorizonHolders) == 0 {
+		b.WriteString("Nothing is holding back the xmin horizon")
+	} else {
+		b.WriteString(renderTable(
+			"Holding back the xmin horizon",
+			[]string{"KIND", "HOLDER", "USER", "DATABASE", "XMIN AGE", "STATE", "SINCE", "QUERY"},
+			len(o.HorizonHolders),
+			func(i int) []string {
+				h := o.HorizonHolders[i]
+				return []string{
+					h.Kind,
+					h.Holder,
+					formatOptionalString(h.Username),
+					formatOptionalString(h.Database),
+					formatOptionalInt(h.XminAge),
+					formatOptionalString(h.State),
+					formatTime(h.Since),
+					truncateQuery(formatOptionalString(h.Query)),
+				}
+			},
+		))
+	}
+
+	return b.String()
+}
+
+func (o *TableFreezeAgeOutput) Text() string {
+	scope := fmt.Sprintf("database %s on instance %s", o.Database, o.Instance)
+	if o.Database == "" {
+		scope = fmt.Sprintf("the database of instance %s", o.Instance)
+	}
+	if len(o.Tables) == 0 {
+		return fmt.Sprintf("No tables found in %s", scope)
+	}
+	return renderTable(
+		fmt.Sprintf("Oldest relfrozenxid in %s", scope),
+		[]string{"TABLE", "KIND", "XID AGE", "% FREEZE MAX", "% WRAPAROUND", "MXID AGE", "SIZE", "LAST AUTOVACUUM"},
+		len(o.Tables),
+		func(i int) []string {
+			t := o.Tables[i]
+			return []string{
+				t.SchemaName + "." + t.TableName,
+				t.RelKind,
+				fmt.Sprint(t.XidAge),
+				fmt.Sprintf("%.2f", t.XidFreezeMaxAgePct),
+				fmt.Sprintf("%.2f", t.XidWraparoundPct),
+				fmt.Sprint(t.MxidAge),
+				formatBytes(t.TotalBytes),
+				formatTime(t.LastAutovacuum),
+			}
+		},
+	)
+}
+
+func (o *MaintenanceProgressOutput) Text() string {
+	if len(o.Operations) == 0 {
+		return fmt.Sprintf("No vacuum, analyze, index build or cluster running on instance %s", o.Instance)
+	}
+	return renderTable(
+		fmt.Sprintf("%d maintenance operations running on instance %s", len(o.Operations), o.Instance),
+		[]string{"PID", "COMMAND", "DATABASE", "RELATION", "PHASE", "PROGRESS", "ELAPSED", "WAIT"},
+		len(o.Operations),
+		func(i int) []string {
+			op := o.Operations[i]
+			relation := formatOptionalString(op.Relation)
+			if !op.Relation.Valid {
+				relation = fmt.Sprintf("oid %d", op.RelID)
+			}
+			progress := "-"
+			if op.ProgressPct.Valid {
+				progress = fmt.Sprintf("%.1f%%", op.ProgressPct.Float64)
+			}
+			return []string{
+				fmt.Sprint(op.PID),
+				op.Command,
+				formatOptionalString(op.Database),
+				relation,
+				op.Phase,
+				progress,
+				formatOptionalSeconds(op.ElapsedSeconds),
+				formatWait(op.WaitEventType, op.WaitEvent),
+			}
+		},
+	)
+}
+
+func (o *TableBloatOutput) Text() string {
+	scope := fmt.Sprintf("database %s on instance %s", o.Database, o.Instance)
+	if o.Database == "" {
+		scope = fmt.Sprintf("the database of instance %s", o.Instance)
+	}
+	if len(o.Tables) == 0 {
+		return fmt.Sprintf("No table bloat found in %s", scope)
+	}
+	header := []string{"TABLE", "SIZE", "WASTED", "BLOAT %", "FILLFACTOR"}
+	if o.Exact {
+		header = append(header, "DEAD TUPLES", "FREE SPACE")
+	}
+	return renderTable(
+		fmt.Sprintf("Table bloat (%s) in %s", bloatMode(o.Exact), scope),
+		header,
+		len(o.Tables),
+		func(i int) []string {
+			t := o.Tables[i]
+			row := []string{
+				t.SchemaName + "." + t.TableName,
+				formatBytes(t.RealBytes),
+				formatBytes(t.WastedBytes),
+				fmt.Sprintf("%.2f", t.BloatPct),
+				fmt.Sprint(t.Fillfactor),
+			}
+			if o.Exact {
+				row = append(row, formatOptionalBytes(t.DeadTupleBytes), formatOptionalBytes(t.FreeBytes))
+			}
+			return row
+		},
+	)
+}
+
+func (o *IndexBloatOutput) Text() string {
+	scope := fmt.Sprintf("database %s on instance %s", o.Database, o.Instance)
+	if o.Database == "" {
+		scope = fmt.Sprintf("the database of instance %s", o.Instance)
+	}
+	if len(o.Indexes) == 0 {
+		return fmt.Sprintf("No btree index bloat found in %s", scope)
+	}
+	header := []string{"INDEX", "TABLE", "SIZE", "WASTED", "BLOAT %", "FILLFACTOR"}
+	if o.Exact {
+		header = append(header, "AVG LEAF DENSITY")
+	}
+	return renderTable(
+		fmt.Sprintf("Btree index bloat (%s) in %s", bloatMode(o.Exact), scope),
+		header,
+		len(o.Indexes),
+		func(i int) []string {
+			idx := o.Indexes[i]
+			row := []string{
+				idx.SchemaName + "." + idx.IndexName,
+				idx.TableName,
+				formatBytes(idx.RealBytes),
+				formatBytes(idx.WastedBytes),
+				fmt.Sprintf("%.2f", idx.BloatPct),
+				fmt.Sprint(idx.Fillfactor),
+			}
+			if o.Exact {
+				row = append(row, formatOptionalFloat(idx.AvgLeafDensity))
+			}
+			return row
+		},
+	)
+}
+
+func (o *IndexAdvisorOutput) Text() string {
+	scope := fmt.Sprintf("database %s on instance %s", o.Database, o.Instance)
+	if o.Database == "" {
+		scope = fmt.Sprintf("the database of instance %s", o.Instance)
+	}
+	if len(o.Findings) == 0 {
+		return fmt.Sprintf("No index findings in %s", scope)
+	}
+
+	title := fmt.Sprintf("Index findings in %s", scope)
+	if o.StatsReset.Valid {
+		title += fmt.Sprintf(" (scans counted since %s)", formatTime(o.StatsReset))
+	}
+
+	var b strings.Builder
+	b.WriteString(renderTable(
+		title,
+		[]string{"KIND", "TABLE", "INDEX", "SIZE", "REASON"},
+		len(o.Findings),
+		func(i int) []string {
+			f := o.Findings[i]
+			return []string{
+				f.Kind,
+				f.SchemaName + "." + f.TableName,
+				formatOptionalString(f.IndexName),
+				formatBytes(f.SizeBytes),
+				f.Reason,
+			}
+		},
+	))
+	b.WriteString("\n\nSuggested DDL (not executed):")
+	for _, f := range o.Findings {
+		b.WriteString("\n" + f.Suggestion)
+	}
+	return b.String()
+}
+
+func (o *LockGraphOutput) Text() string {
+	if len(o.Edges) == 0 {
+		return fmt.Sprintf("No sessions are waiting for locks on instance %s", o.Instance)
+	}
+
+	children := make(map[int][]pg.LockWaitEdge)
+	for _, e := range o.Edges {
+		children[e.BlockerPID] = append(children[e.BlockerPID], e)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Lock waits on instance %s: %d root blockers", o.Instance, len(o.Roots))
+
+	for _, r := range o.Roots {
+		fmt.Fprintf(&b, "\n\npid %d (%s@%s, %s, transaction open %s): %d sessions waiting, depth %d\n  %s",
+			r.PID, formatOptionalString(r.Username), formatOptionalString(r.Database), formatOptionalString(r.State),
+			formatOptionalSeconds(r.XactSeconds), r.TotalWaiters, r.MaxDepth, truncateQuery(r.Query.String))
+		writeLockWaiters(&b, children, r.PID, 1, map[int]bool{r.PID: true})
+	}
+
+	if len(o.Roots) == 0 {
+		b.WriteString("\n\nEvery waiting session is blocked by another waiting session: this is a deadlock that has not been detected yet")
+	}
+	return b.String()
+}
+
+// writeLockWaiters renders the sessions waiting behind pid as an indented tree
+func writeLockWaiters(b *strings.Builder, children map[int][]pg.LockWaitEdge, pid, level int, visited map[int]bool) {
+	indent := strings.Repeat("  ", level)
+	for _, e := range children[pid] {
+		if visited[e.WaiterPID] {
+			continue
+		}
+		visited[e.WaiterPID] = true
+
+		target := e.LockType
+		if e.Relation.Valid {
+			target += " " + e.Relation.String
+		}
+		held := formatOptionalString(e.BlockerMode)
+		if e.BlockerMode.Valid && !e.BlockerGranted {
+			held += " (queued)"
+		}
+		fmt.Fprintf(b, "\n%s-> pid %d waits %s for %s on %s (blocker holds %s)\n%s   %s",
+			indent, e.WaiterPID, formatOptionalSeconds(e.WaiterWaitSeconds), e.WaiterMode, target, held,
+			indent, truncateQuery(e.WaiterQuery.String))
+		writeLockWaiters(b, children, e.WaiterPID, level+1, visited)
+	}
+}
+
+func (o *BackendActionOutput) Text() string {
+	var b strings.Builder
+	switch o.Status {
+	case backendStatusConfirmationRequired:
+		fmt.Fprintf(&b, "%s on instance %s needs confirmation. Call %s again with confirm_token=%s before %s",
+			o.Action, o.Instance, o.Action, o.ConfirmToken, formatTime(o.ExpiresAt))
+	case backendStatusNotSignalled:
+		fmt.Fprintf(&b, "%s on instance %s: the backend was not signalled", o.Action, o.Instance)
+	default:
+		fmt.Fprintf(&b, "Backend on instance %s %s, requested by %s", o.Instance, o.Status, o.RequestedBy)
+	}
+	if o.Backend != nil {
+		fmt.Fprintf(&b, "\npid %d (%s@%s, %s, query started %s)\n%s",
+			o.Backend.PID, formatOptionalString(o.Backend.Username), formatOptionalString(o.Backend.Database),
+			formatOptionalString(o.Backend.State), formatTime(o.Backend.QueryStart), truncateQuery(o.Backend.Query.String))
+	}
+	return b.String()
+}
+
+func (o *SchemasOutput) Text() string {
+	scope := schemaScope(o.Instance, o.Database, "")
+	if len(o.Schemas) == 0 {
+		return fmt.Sprintf("No user schemas in %s", scope)
+	}
+	return renderTable(
+		fmt.Sprintf("Schemas in %s", scope),
+		[]string{"SCHEMA", "OWNER", "TABLES", "VIEWS", "FUNCTIONS", "COMMENT"},
+		len(o.Schemas),
+		func(i int) []string {
+			s := o.Schemas[i]
+			return []string{
+				s.Name,
+				s.Owner,
+				fmt.Sprint(s.Tables),
+				fmt.Sprint(s.Views),
+				fmt.Sprint(s.Functions),
+				formatOptionalString(s.Comment),
+			}
+		},
+	)
+}
+
+func (o *SchemaTablesOutput) Text() string {
+	scope := schemaScope(o.Instance, o.Database, o.Schema)
+	if len(o.Tables) == 0 {
+		return fmt.Sprintf("No tables in %s", scope)
+	}
+
+	var b strings.Builder
+	b.WriteString(renderTable(
+		fmt.Sprintf("Tables in %s", scope),
+		[]string{"TABLE", "KIND", "ROWS (EST.)", "SIZE", "COLUMNS"},
+		len(o.Tables),
+		func(i int) []string {
+			t := o.Tables[i]
+			return []string{
+				t.SchemaName + "." + t.TableName,
+				t.Kind,
+				formatOptionalInt(t.EstimatedRows),
+				formatBytes(t.TotalBytes),
+				formatColumnList(t.Columns),
+			}
+		},
+	))
+	b.WriteString(nextPageText(o.NextOffset))
+	return b.String()
+}
+
+func (o *DescribeTableOutput) Text() string {
+	var b strings.Builder
+	kind := "Relation"
+	if o.Kind != "" {
+		kind = strings.ToUpper(o.Kind[:1]) + o.Kind[1:]
+	}
+	fmt.Fprintf(&b, "%s %s.%s in %s", kind, o.SchemaName, o.TableName, schemaScope(o.Instance, o.Database, ""))
+	fmt.Fprintf(&b, "\nRows (est.): %s, total size: %s", formatOptionalInt(o.EstimatedRows), formatBytes(o.TotalBytes))
+	if o.Comment.Valid {
+		fmt.Fprintf(&b, "\nComment: %s", o.Comment.String)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(renderTable(
+		"Columns",
+		[]string{"COLUMN", "TYPE", "NULLABLE", "DEFAULT", "COMMENT"},
+		len(o.Columns),
+		func(i int) []string {
+			c := o.Columns[i]
+			return []string{
+				c.Name,
+				c.Type,
+				fmt.Sprint(c.Nullable),
+				formatOptionalString(c.Default),
+				formatOptionalString(c.Comment),
+			}
+		},
+	))
+
+	if len(o.Constraints) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			"Constraints",
+			[]string{"CONSTRAINT", "TYPE", "DEFINITION"},
+			len(o.Constraints),
+			func(i int) []string {
+				c := o.Constraints[i]
+				return []string{c.Name, c.Type, c.Definition}
+			},
+		))
+	}
+
+	if len(o.Indexes) > 0 {
+		b.WriteString("\n\nIndexes:")
+		for _, index := range o.Indexes {
+			fmt.Fprintf(&b, "\n%s (%s)", index.Definition, formatBytes(index.SizeBytes))
+			if !index.Valid {
+				b.WriteString(" INVALID")
+			}
+		}
+	}
+
+	if o.ViewDefinition.Valid {
+		b.WriteString("\n\nDefinition:\n")
+		b.WriteString(strings.TrimSpace(o.ViewDefinition.String))
+	}
+
+	return b.String()
+}
+
+func (o *SchemaViewsOutput) Text() string {
+	scope := schemaScope(o.Instance, o.Database, o.Schema)
+	if len(o.Views) == 0 {
+		return fmt.Sprintf("No views in %s", scope)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Views in %s", scope)
+	for _, v := range o.Views {
+		kind := "VIEW"
+		if v.Materialized {
+			kind = "MATERIALIZED VIEW"
+		}
+		fmt.Fprintf(&b, "\n\n%s %s.%s AS\n%s", kind, v.SchemaName, v.ViewName, strings.TrimSpace(v.Definition))
+	}
+	b.WriteString(nextPageText(o.NextOffset))
+	return b.String()
+}
+
+func (o *SchemaFunctionsOutput) Text() string {
+	scope := schemaScope(o.Instance, o.Database, o.Schema)
+	if len(o.Functions) == 0 {
+		return fmt.Sprintf("No functions in %s", scope)
+	}
+
+	var b strings.Builder
+	b.WriteString(renderTable(
+		fmt.Sprintf("Functions in %s", scope),
+		[]string{"FUNCTION", "KIND", "RETURNS", "LANGUAGE", "VOLATILITY"},
+		len(o.Functions),
+		func(i int) []string {
+			f := o.Functions[i]
+			return []string{
+				fmt.Sprintf("%s.%s(%s)", f.SchemaName, f.Name, f.Arguments),
+				f.Kind,
+				formatOptionalString(f.ReturnType),
+				f.Language,
+				f.Volatility,
+			}
+		},
+	))
+	b.WriteString(nextPageText(o.NextOffset))
+	return b.String()
+}
+
+func (o *SchemaDiffOutput) Text() string {
+	source := schemaScope(o.Source.Instance, o.Source.Database, o.Schema)
+	target := schemaScope(o.Target.Instance, o.Target.Database, o.Schema)
+	if o.Summary.Total == 0 {
+		return fmt.Sprintf("No schema differences between %s and %s", source, target)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Schema differences of %s compared with %s: %d total", target, source, o.Summary.Total)
+	fmt.Fprintf(&b, "\nExtensions: %d, tables: %d, columns: %d, constraints: %d, indexes: %d",
+		o.Summary.Extensions, o.Summary.Tables, o.Summary.Columns, o.Summary.Constraints, o.Summary.Indexes)
+
+	if len(o.Changes) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			"Changes (missing: only in the source, extra: only in the target)",
+			[]string{"OBJECT", "KIND", "TABLE", "NAME", "SOURCE", "TARGET"},
+			len(o.Changes),
+			func(i int) []string {
+				c := o.Changes[i]
+				return []string{
+					c.Object,
+					c.Kind,
+					formatOptionalString(pg.NewNullString(c.Table)),
+					c.Name,
+					formatOptionalString(pg.NewNullString(truncateQuery(c.Source))),
+					formatOptionalString(pg.NewNullString(truncateQuery(c.Target))),
+				}
+			},
+		))
+	}
+
+	if o.Patch != "" {
+		b.WriteString("\n\nPatch for the target (not executed):\n")
+		b.WriteString(o.Patch)
+	}
+
+	return b.String()
+}
+
+func (o *SettingsDriftOutput) Text() string {
+	var b strings.Builder
+	scope := fmt.Sprintf("instances %s", strings.Join(o.Instances, ", "))
+	if o.Baseline != "" {
+		scope += fmt.Sprintf(" against baseline %s", o.Baseline)
+	}
+
+	if len(o.Drifts) == 0 {
+		fmt.Fprintf(&b, "No settings drift between %s", scope)
+	} else {
+		header := append([]string{"SETTING"}, o.Instances...)
+		if o.Baseline != "" {
+			header = append(header, "BASELINE")
+		}
+		header = append(header, "NOTE")
+
+		b.WriteString(renderTable(
+			fmt.Sprintf("Settings drift between %s (* pending restart)", scope),
+			header,
+			len(o.Drifts),
+			func(i int) []string {
+				d := o.Drifts[i]
+				row := []string{d.Name}
+				for _, v := range d.Values {
+					value := formatOptionalString(pg.NewNullString(v.Value))
+					if v.PendingRestart {
+						value += "*"
+					}
+					row = append(row, value)
+				}
+				if o.Baseline != "" {
+					row = append(row, formatOptionalString(pg.NewNullString(d.Baseline)))
+				}
+				return append(row, driftNote(d))
+			},
+		))
+	}
+
+	for _, e := range o.Errors {
+		fmt.Fprintf(&b, "\nFailed to read %s: %s", e.Instance, e.Error)
+	}
+	return b.String()
+}
+
+func (o *SettingsBaselineOutput) Text() string {
+	return fmt.Sprintf("Saved settings baseline %s with %d settings\n\n%s",
+		o.Name, len(o.Settings), formatBaselineSettings(o.Settings))
+}
+
+func (o *SettingsBaselinesOutput) Text() string {
+	if len(o.Baselines) == 0 {
+		return "No settings baselines stored"
+	}
+	return renderTable(
+		"Settings baselines",
+		[]string{"NAME", "SETTINGS", "UPDATED", "BY", "DESCRIPTION"},
+		len(o.Baselines),
+		func(i int) []string {
+			b := o.Baselines[i]
+			return []string{
+				b.Name,
+				fmt.Sprint(len(b.Settings)),
+				b.UpdatedAt.UTC().Format(timeLayout),
+				formatOptionalString(pg.NewNullString(b.CreatedBy)),
+				formatOptionalString(pg.NewNullString(b.Description)),
+			}
+		},
+	)
+}
+
+func (o *TuningAdvisorOutput) Text() string {
+	var b strings.Builder
+	scope := fmt.Sprintf("instance %s", o.Instance)
+	if o.HostRAMBytes.Valid {
+		scope += fmt.Sprintf(" (host RAM %s)", formatBytes(o.HostRAMBytes.Int64))
+	}
+
+	if len(o.Findings) == 0 {
+		fmt.Fprintf(&b, "No tuning recommendations for %s", scope)
+	} else {
+		b.WriteString(renderTable(
+			fmt.Sprintf("Tuning recommendations for %s", scope),
+			[]string{"SEVERITY", "SETTING", "CURRENT", "SUGGESTED"},
+			len(o.Findings),
+			func(i int) []string {
+				f := o.Findings[i]
+				return []string{f.Severity, f.Setting, formatOptionalString(pg.NewNullString(f.Current)), f.Suggested}
+			},
+		))
+		b.WriteString("\n")
+		for _, f := range o.Findings {
+			fmt.Fprintf(&b, "\n%s: %s", f.Setting, f.Rationale)
+		}
+	}
+
+	for _, u := range o.Unavailable {
+		fmt.Fprintf(&b, "\nNot available: %s", u)
+	}
+	return b.String()
+}
+
+func (o *HealthReportOutput) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Health of %s/%s: score %d, %s\n", o.Instance, o.Database, o.Score, o.Status)
+
+	if len(o.Findings) == 0 {
+		fmt.Fprintf(&b, "\nNo findings (%d rules checked)", len(o.Checked))
+	} else {
+		b.WriteString("\n")
+		b.WriteString(renderTable(
+			fmt.Sprintf("Findings (%d rules checked)", len(o.Checked)),
+			[]string{"#", "SEVERITY", "RULE", "PENALTY", "SUMMARY"},
+			len(o.Findings),
+			func(i int) []string {
+				f := o.Findings[i]
+				return []string{fmt.Sprintf("%d", i+1), f.Severity, f.Rule, fmt.Sprintf("-%d", f.Penalty), f.Summary}
+			},
+		))
+		for i, f := range o.Findings {
+			if len(f.Evidence) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n\n%d. %s:", i+1, f.Rule)
+			for _, e := range f.Evidence {
+				fmt.Fprintf(&b, "\n  %s", e)
+			}
+		}
+	}
+
+	if len(o.Skipped) > 0 {
+		fmt.Fprintf(&b, "\n\nSkipped: %s", strings.Join(o.Skipped, ", "))
+	}
+	for _, u := range o.Unavailable {
+		fmt.Fprintf(&b, "\nNot available: %s", u)
+	}
+	return b.String()
+}
+
+func (o *SaveAlertRuleOutput) Text() string {
+	var b strings.Builder
+	state := "enabled"
+	if !o.Enabled {
+		state = "disabled"
+	}
+	fmt.Fprintf(&b, "Saved alert rule %s (%s, %s): %s %s for %s", o.Name, o.Severity, state, o.Action, o.Expression, o.For)
+	if o.Labels != "" {
+		fmt.Fprintf(&b, " on instances with %s", o.Labels)
+	}
+
+	if len(o.Preview) == 0 {
+		b.WriteString("\n\nNo registered instance matches the rule yet")
+		return b.String()
+	}
+	b.WriteString("\n\n")
+	b.WriteString(renderTable(
+		"Condition right now",
+		[]string{"INSTANCE", "MATCHED", "VALUE", "ERROR"},
+		len(o.Preview),
+		func(i int) []string {
+			p := o.Preview[i]
+			return []string{p.Instance, formatBool(p.Matched), formatAlertValue(p.Value), formatOptionalString(pg.NewNullString(p.Error))}
+		},
+	))
+	return b.String()
+}
+
+func (o *AlertRulesOutput) Text() string {
+	if len(o.Rules) == 0 {
+		return "No alert rules stored"
+	}
+	return renderTable(
+		"Alert rules",
+		[]string{"NAME", "SEVERITY", "ACTION", "CONDITION", "FOR", "LABELS", "ENABLED"},
+		len(o.Rules),
+		func(i int) []string {
+			r := o.Rules[i]
+			return []string{
+				r.Name,
+				r.Severity,
+				r.Action,
+				r.Expression,
+				r.For,
+				formatOptionalString(pg.NewNullString(r.Labels)),
+				formatBool(r.Enabled),
+			}
+		},
+	)
+}
+
+func (o *AlertsOutput) Text() string {
+	var b strings.Builder
+	if len(o.Alerts) == 0 {
+		b.WriteString("No alerts")
+	} else {
+		b.WriteString(renderTable(
+			fmt.Sprintf("Alerts (%d firing, * silenced)", o.Firing),
+			[]string{"STATE", "SEVERITY", "RULE", "INSTANCE", "VALUE", "SINCE", "NOTIFIED"},
+			len(o.Alerts),
+			func(i int) []string {
+				a := o.Alerts[i]
+				state := a.State
+				if a.Silenced {
+					state += "*"
+				}
+				since := a.ActiveSince
+				switch {
+				case a.ResolvedAt != nil:
+					since = *a.ResolvedAt
+				case a.FiringSince != nil:
+					since = *a.FiringSince
+				}
+				return []string{
+					state,
+					formatOptionalString(pg.NewNullString(a.Severity)),
+					a.Rule,
+					a.Instance,
+					formatAlertValue(a.Value),
+					since.UTC().Format(timeLayout),
+					formatBool(a.Notified),
+				}
+			},
+		))
+	}
+
+	if len(o.Silences) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(renderTable(
+			"Silences",
+			[]string{"ID", "RULE", "INSTANCE", "UNTIL", "BY", "COMMENT"},
+			len(o.Silences),
+			func(i int) []string {
+				s := o.Silences[i]
+				return []string{
+					fmt.Sprint(s.ID),
+					formatOptionalString(pg.NewNullString(s.Rule)),
+					formatOptionalString(pg.NewNullString(s.Instance)),
+					s.EndsAt.UTC().Format(timeLayout),
+					formatOptionalString(pg.NewNullString(s.CreatedBy)),
+					formatOptionalString(pg.NewNullString(s.Comment)),
+				}
+			},
+		))
+	}
+	return b.String()
+}
+
+func (o *SilenceAlertOutput) Text() string {
+	scope := "all rules"
+	if o.Rule != "" {
+		scope = "rule " + o.Rule
+	}
+	if o.Instance != "" {
+		scope += " on " + o.Instance
+	}
+	return fmt.Sprintf("Silenced %s until %s (silence %d)", scope, o.EndsAt.UTC().Format(timeLayout), o.ID)
+}
+
+func (o *InstanceHealthOutput) Text() string {
+	var b strings.Builder
+	if o.Reachable {
+		fmt.Fprintf(&b, "Instance %s is reachable (ping %.1f ms)", o.Instance, o.PingLatencyMs)
+	} else {
+		fmt.Fprintf(&b, "Instance %s is not reachable: %s", o.Instance, o.Error)
+	}
+	fmt.Fprintf(&b, "\nCircuit breaker: %s (%d/%d consecutive failures)",
+		o.Breaker.State, o.Breaker.ConsecutiveFailures, o.Breaker.FailureThreshold)
+	if o.Breaker.NextProbeAt != nil {
+		fmt.Fprintf(&b, ", next probe at %s", o.Breaker.NextProbeAt.UTC().Format(timeLayout))
+	}
+	fmt.Fprintf(&b, "\nLight slots: %d/%d in use, %d queued; heavy slots: %d/%d in use, %d queued",
+		o.Load.Light.InUse, o.Load.Light.Slots, o.Load.Light.Queued,
+		o.Load.Heavy.InUse, o.Load.Heavy.Slots, o.Load.Heavy.Queued)
+	return b.String()
+}
+
+func renderTable(title string, header []string, rows int, row func(i int) []string) string {
+	var b strings.Builder
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for i := 0; i < rows; i++ {
+		fmt.Fprintln(w, strings.Join(row(i), "\t"))
+	}
+	w.Flush()
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func formatTime(t pg.NullTime) string {
+	if !t.Valid {
+		return "-"
+	}
+	return t.Time.UTC().Format(timeLayout)
+}
+
+func formatOptionalString(s pg.NullString) string {
+	if !s.Valid || s.String == "" {
+		return "-"
+	}
+	return s.String
+}
+
+func formatOptionalInt(v pg.NullInt64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return fmt.Sprint(v.Int64)
+}
+
+func formatOptionalBytes(v pg.NullInt64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return formatBytes(v.Int64)
+}
+
+func formatBool(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+func bloatMode(exact bool) string {
+	if exact {
+		return "measured"
+	}
+	return "estimated"
+}
+
+// formatQueryStart keeps the full precision and offset so the value can be
+// passed back as query_start to cancel_backend and terminate_backend
+func formatQueryStart(t pg.NullTime) string {
+	if !t.Valid {
+		return "-"
+	}
+	return t.Time.Format(time.RFC3339Nano)
+}
+
+// schemaScope describes where schema objects were listed from
+func schemaScope(instance, database, schema string) string {
+	scope := fmt.Sprintf("database %s on instance %s", database, instance)
+	if database == "" {
+		scope = fmt.Sprintf("the database of instance %s", instance)
+	}
+	if schema != "" {
+		scope = fmt.Sprintf("schema %s of %s", schema, scope)
+	}
+	return scope
+}
+
+const maxColumnListLength = 80
+
+func formatColumnList(columns []pg.ColumnInfo) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		parts[i] = c.Name + " " + c.Type
+	}
+	list := strings.Join(parts, ", ")
+	if len(list) > maxColumnListLength {
+		return list[:maxColumnListLength] + "..."
+	}
+	return list
+}
+
+func nextPageText(nextOffset pg.NullInt64) string {
+	if !nextOffset.Valid {
+		return ""
+	}
+	return fmt.Sprintf("\n\nMore objects follow, call again with offset %d", nextOffset.Int64)
+}
+
+func driftNote(d settingsdrift.Drift) string {
+	var notes []string
+	if d.Differs {
+		notes = append(notes, "differs")
+	}
+	if d.BaselineMismatch {
+		notes = append(notes, "baseline mismatch")
+	}
+	if d.PendingRestart {
+		notes = append(notes, "pending restart")
+	}
+	return strings.Join(notes, ", ")
+}
+
+func formatBaselineSettings(settings map[string]string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("%s = %s", name, settings[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatAlertValue(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%g", *v)
+}
+
+func formatSnapshotRef(ref statements.Ref) string {
+	if ref.ID == 0 {
+		return "now"
+	}
+	return fmt.Sprintf("snapshot %d at %s", ref.ID, ref.TakenAt.UTC().Format(timeLayout))
+}
+
+func formatOptionalFloat(v pg.NullFloat64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v.Float64)
+}
+
+// formatHitRate renders a hit rate fraction as a percentage
+func formatHitRate(v pg.NullFloat64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", v.Float64*100)
+}
+
+func formatOptionalSeconds(v pg.NullFloat64) string {
+	if !v.Valid {
+		return "-"
+	}
+	return (time.Duration(v.Float64 * float64(time.Second))).Round(time.Second).String()
+}
+
+func formatWait(eventType, event pg.NullString) string {
+	if !eventType.Valid || !event.Valid {
+		return "-"
+	}
+	return eventType.String + ":" + event.String
+}
+
+func formatPids(pids []int64) string {
+	if len(pids) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		parts = append(parts, fmt.Sprint(pid))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func truncateQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryTextLength {
+		return query[:maxQueryTextLength] + "..."
+	}
+	return query
+}
@@ -14,6 +14,10 @@ type CheckpointsStatsInput struct {
 type WalActivityInput struct {
 	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
 }
+type IOStatsInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"tables and indexes listed before PostgreSQL 16 (default: 20)"`
+}
 type TablesInfoInput struct {
 	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
 	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of tables to return (default: 200)"`
@@ -42,10 +46,165 @@ type ActiveQueriesInput struct {
 type ConnectionStatsInput struct {
 	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
 }
+type WaitProfileInput struct {
+	InstanceName    string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	IntervalMS      int    `json:"interval_ms,omitempty" jsonschema:"time between two polls of pg_stat_activity in milliseconds, at least 10 (default: 100)"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"how long to sample, at most 120 seconds (default: 10)"`
+	Limit           int    `json:"limit,omitempty" jsonschema:"entries listed per breakdown (default: 10)"`
+}
+type WaitProfileHistoryInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	Window       string `json:"window,omitempty" jsonschema:"how far back to merge the recorded profiles, e.g. 15m or 6h (default: 1h)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"entries listed per breakdown (default: 10)"`
+}
 type SlowQueriesInput struct {
 	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
 	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of slow queries to return (default: 20)"`
 }
+type TopStatementsInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	OrderBy      string `json:"order_by,omitempty" jsonschema:"total_time (default), mean_time, calls, rows, shared_reads, temp_blocks or wal_bytes (PostgreSQL 13+)"`
+	Database     string `json:"database,omitempty" jsonschema:"only statements run in this database"`
+	User         string `json:"user,omitempty" jsonschema:"only statements run by this role"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of statements to return (default: 20)"`
+}
+type CaptureStatementsSnapshotInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	Comment      string `json:"comment,omitempty" jsonschema:"why the snapshot was taken, e.g. before deploy 1.42"`
+}
+type StatementsSnapshotsInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of snapshots to return (default: 20)"`
+}
+type StatementsDiffInput struct {
+	InstanceName string `json:"instance_name,omitempty" jsonschema:"name of the PostgreSQL instance; required with window"`
+	FromID       int    `json:"from_id,omitempty" jsonschema:"ID of the earlier snapshot"`
+	ToID         int    `json:"to_id,omitempty" jsonschema:"ID of the later snapshot (default: the current counters, read now)"`
+	Window       string `json:"window,omitempty" jsonschema:"compare against the latest snapshot at least this old instead of from_id, e.g. 10m or 1h"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"statements listed per ranking (default: 10)"`
+}
 type DatabaseSizesInput struct {
 	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
 }
+type InstanceHealthInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+}
+type ReplicationStatusInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+}
+type ReplicationSlotsInput struct {
+	InstanceName           string `json:"instance_name,omitempty" jsonschema:"name of the PostgreSQL instance (default: all registered instances)"`
+	RetainedWalThresholdMB int    `json:"retained_wal_threshold_mb,omitempty" jsonschema:"inactive slots retaining more WAL than this are flagged as at risk (default: 1024)"`
+}
+type XidWraparoundInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	HoldersLimit int    `json:"holders_limit,omitempty" jsonschema:"maximum number of xmin horizon holders to return (default: 20)"`
+}
+type TableFreezeAgeInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of tables to return (default: 20)"`
+}
+type MaintenanceProgressInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+}
+type TableBloatInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of tables to return (default: 50)"`
+	Exact        bool   `json:"exact,omitempty" jsonschema:"measure the largest tables with pgstattuple instead of estimating from pg_stats; reads every page, requires the pgstattuple extension"`
+}
+type IndexBloatInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of indexes to return (default: 50)"`
+	Exact        bool   `json:"exact,omitempty" jsonschema:"measure the largest btree indexes with pgstatindex instead of estimating from pg_stats; reads every page, requires the pgstattuple extension"`
+}
+type IndexAdvisorInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+}
+type LockGraphInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+}
+type ListSchemasInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+}
+type SchemaPageInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+	Schema       string `json:"schema,omitempty" jsonschema:"only objects of this schema (default: all user schemas)"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"page size (default: 50, max: 500)"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"number of objects to skip; pass next_offset of the previous page"`
+}
+type DescribeTableInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database name (default: the database of the instance)"`
+	Schema       string `json:"schema,omitempty" jsonschema:"schema of the table (default: public)"`
+	Table        string `json:"table" jsonschema:"table or view name,required"`
+}
+type SchemaDiffInput struct {
+	SourceInstance string `json:"source_instance" jsonschema:"instance whose schema is the reference, e.g. prod,required"`
+	SourceDb       string `json:"source_db,omitempty" jsonschema:"database on the source instance (default: the database of the instance)"`
+	TargetInstance string `json:"target_instance" jsonschema:"instance compared with the source, e.g. staging; may equal source_instance to compare two databases,required"`
+	TargetDb       string `json:"target_db,omitempty" jsonschema:"database on the target instance (default: the database of the instance)"`
+	Schema         string `json:"schema,omitempty" jsonschema:"compare only this schema (default: all user schemas)"`
+	Mode           string `json:"mode,omitempty" jsonschema:"changes (default): summary and every change; summary: counts only; patch: summary and a DDL script that makes the target match the source"`
+}
+type SettingsDriftInput struct {
+	Instances []string `json:"instances,omitempty" jsonschema:"instances to compare (default: every registered instance, or every instance matching labels)"`
+	Labels    string   `json:"labels,omitempty" jsonschema:"label selector such as env=prod,region=eu; only instances with all of these labels are compared"`
+	Baseline  string   `json:"baseline,omitempty" jsonschema:"name of a settings baseline stored in the registry to compare every instance against"`
+}
+type SaveSettingsBaselineInput struct {
+	Name         string            `json:"name" jsonschema:"name of the baseline; an existing baseline with this name is replaced,required"`
+	Description  string            `json:"description,omitempty" jsonschema:"what the baseline is for"`
+	InstanceName string            `json:"instance_name,omitempty" jsonschema:"capture the settings this instance sets in its configuration (non-default values)"`
+	Settings     map[string]string `json:"settings,omitempty" jsonschema:"expected values in postgresql.conf syntax, e.g. {\"shared_buffers\": \"8GB\"}; added to or overriding the captured ones"`
+}
+type SettingsBaselinesInput struct {
+}
+type TuningAdvisorInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	HostRAMMB    int    `json:"host_ram_mb,omitempty" jsonschema:"RAM of the database host in MB; enables memory sizing against the host (default: unknown)"`
+	DbName       string `json:"db_name,omitempty" jsonschema:"database whose temp file usage is reviewed (default: postgres)"`
+}
+type HealthReportInput struct {
+	InstanceName string                     `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	DbName       string                     `json:"db_name,omitempty" jsonschema:"database whose statistics are checked (default: database of the instance)"`
+	Thresholds   map[string]HealthThreshold `json:"thresholds,omitempty" jsonschema:"thresholds for this call by rule name, e.g. {\"cache_hit_rate\": {\"warning\": 98, \"critical\": 90}}; other rules keep the server defaults"`
+	Disable      []string                   `json:"disable,omitempty" jsonschema:"rules to skip: cache_hit_rate, dead_tuples, connection_saturation, requested_checkpoints, stale_autovacuum, deadlocks, temp_bytes"`
+}
+type HealthThreshold struct {
+	Warning  float64 `json:"warning" jsonschema:"value at which the rule reports a warning,required"`
+	Critical float64 `json:"critical" jsonschema:"value at which the rule reports a critical finding,required"`
+}
+type SaveAlertRuleInput struct {
+	Name        string                 `json:"name" jsonschema:"name of the rule; an existing rule with this name is replaced,required"`
+	Description string                 `json:"description,omitempty" jsonschema:"what the alert means and what to do about it; sent with every notification"`
+	Action      string                 `json:"action" jsonschema:"read-only action whose output is checked, e.g. connection_stats, cache_hit_rate, replication_slots,required"`
+	Params      map[string]interface{} `json:"params,omitempty" jsonschema:"parameters of the action, named as in its tool input, e.g. {\"db_name\": \"shop\", \"limit\": 10}; the camelCase router names (dbName) are accepted too, unknown names are refused"`
+	Expression  string                 `json:"expression" jsonschema:"condition on the JSON fields of the action output, e.g. active / max_connections > 0.9; a list output matches when any element does,required"`
+	For         string                 `json:"for,omitempty" jsonschema:"how long the condition must hold before the alert fires, e.g. 5m (default: 0, fire at the first match)"`
+	Severity    string                 `json:"severity,omitempty" jsonschema:"critical, warning or info (default: warning)"`
+	Labels      string                 `json:"labels,omitempty" jsonschema:"label selector of the instances the rule applies to, such as env=prod (default: all instances)"`
+	Disabled    bool                   `json:"disabled,omitempty" jsonschema:"store the rule without evaluating it"`
+}
+type AlertRulesInput struct {
+}
+type AlertsInput struct {
+	State string `json:"state,omitempty" jsonschema:"only alerts in this state: pending, firing or resolved (default: all)"`
+}
+type SilenceAlertInput struct {
+	Rule         string `json:"rule,omitempty" jsonschema:"rule to silence (default: every rule of instance_name)"`
+	InstanceName string `json:"instance_name,omitempty" jsonschema:"instance to silence (default: every instance of rule)"`
+	Duration     string `json:"duration" jsonschema:"how long notifications are suppressed, e.g. 2h,required"`
+	Comment      string `json:"comment,omitempty" jsonschema:"why the alert is silenced"`
+}
+type BackendActionInput struct {
+	InstanceName string `json:"instance_name" jsonschema:"name of the PostgreSQL instance,required"`
+	Pid          int    `json:"pid" jsonschema:"process ID of the backend as reported by active_queries,required"`
+	QueryStart   string `json:"query_start" jsonschema:"query_start of the backend exactly as reported by active_queries (RFC 3339 with fractional seconds); protects against PID reuse and a new query of the same backend,required"`
+	ConfirmToken string `json:"confirm_token,omitempty" jsonschema:"token returned by the first call; omit it to get one"`
+}
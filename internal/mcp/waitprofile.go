@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/waitprofile"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func (s *MCPServer) handleWaitProfile(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input WaitProfileInput,
+) (*mcp.CallToolResult, *WaitProfileOutput, error) {
+	params := make(map[string]interface{})
+	if input.IntervalMS > 0 {
+		params["intervalMs"] = input.IntervalMS
+	}
+	if input.DurationSeconds > 0 {
+		params["durationSeconds"] = input.DurationSeconds
+	}
+	if input.Limit > 0 {
+		params["limit"] = input.Limit
+	}
+
+	data, err := s.executeRouterQuery(ctx, input.InstanceName, model.ActionNameWaitProfile, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := routerData[*waitprofile.Profile](data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return toolResult(&WaitProfileOutput{Instance: input.InstanceName, Profile: *profile})
+}
+
+func (s *MCPServer) handleWaitProfileHistory(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input WaitProfileHistoryInput,
+) (*mcp.CallToolResult, *WaitProfileHistoryOutput, error) {
+	if s.waitProfiles == nil {
+		return nil, nil, fmt.Errorf("wait profile history is not configured")
+	}
+
+	window := time.Hour
+	if input.Window != "" {
+		d, err := time.ParseDuration(input.Window)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid window %q, expected a positive duration such as 1h", input.Window)
+		}
+		window = d
+	}
+
+	now := time.Now()
+	since := now.Add(-window)
+	profiles, err := s.waitProfiles.ListWaitProfiles(ctx, input.InstanceName, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Without recorded windows the empty profile still spans the requested window
+	profiler := waitprofile.NewProfiler(since, 0)
+	end := now
+	for i, profile := range profiles {
+		profiler.Merge(profile)
+		if i == 0 || profile.End.After(end) {
+			end = profile.End
+		}
+	}
+
+	return toolResult(&WaitProfileHistoryOutput{
+		Instance: input.InstanceName,
+		Windows:  len(profiles),
+		Profile:  *profiler.Profile(end, input.Limit),
+	})
+}
@@ -3,17 +3,50 @@ package model
 type ActionName string
 
 var (
-	ActionNameDatabaseOverview ActionName = "databases_overview"
-	ActionNameCacheHitRate     ActionName = "cache_hit_rate"
-	ActionNameCheckpointsStats ActionName = "checkpoints_stats"
-	ActionNameWalActivity      ActionName = "wal_activity"
-	ActionNameTablesInfo       ActionName = "tables_info"
-	ActionNameLockingInfo      ActionName = "locking_info"
-	ActionNameChangedSettings  ActionName = "changed_settings"
-	ActionNameVersion          ActionName = "version"
-	ActionNameIndexStats       ActionName = "index_stats"
-	ActionNameActiveQueries    ActionName = "active_queries"
-	ActionNameConnectionStats  ActionName = "connection_stats"
-	ActionNameSlowQueries      ActionName = "slow_queries"
-	ActionNameDatabaseSizes    ActionName = "database_sizes"
+	ActionNameDatabaseOverview    ActionName = "databases_overview"
+	ActionNameCacheHitRate        ActionName = "cache_hit_rate"
+	ActionNameCheckpointsStats    ActionName = "checkpoints_stats"
+	ActionNameWalActivity         ActionName = "wal_activity"
+	ActionNameIOStats             ActionName = "io_stats"
+	ActionNameTablesInfo          ActionName = "tables_info"
+	ActionNameLockingInfo         ActionName = "locking_info"
+	ActionNameChangedSettings     ActionName = "changed_settings"
+	ActionNameVersion             ActionName = "version"
+	ActionNameIndexStats          ActionName = "index_stats"
+	ActionNameActiveQueries       ActionName = "active_queries"
+	ActionNameConnectionStats     ActionName = "connection_stats"
+	ActionNameActivitySample      ActionName = "activity_sample"
+	ActionNameWaitProfile         ActionName = "wait_profile"
+	ActionNameSlowQueries         ActionName = "slow_queries"
+	ActionNameTopStatements       ActionName = "top_statements"
+	ActionNameStatementsSnapshot  ActionName = "statements_snapshot"
+	ActionNameDatabaseSizes       ActionName = "database_sizes"
+	ActionNameInstanceHealth      ActionName = "instance_health"
+	ActionNameReplicationStatus   ActionName = "replication_status"
+	ActionNameReplicationSlots    ActionName = "replication_slots"
+	ActionNameXidWraparound       ActionName = "xid_wraparound"
+	ActionNameTableFreezeAge      ActionName = "table_freeze_age"
+	ActionNameMaintenanceProgress ActionName = "maintenance_progress"
+	ActionNameTableBloat          ActionName = "table_bloat"
+	ActionNameIndexBloat          ActionName = "index_bloat"
+	ActionNameIndexAdvisor        ActionName = "index_advisor"
+	ActionNameLockGraph           ActionName = "lock_graph"
+	ActionNameBackendInfo         ActionName = "backend_info"
+	ActionNameCancelBackend       ActionName = "cancel_backend"
+	ActionNameTerminateBackend    ActionName = "terminate_backend"
+	ActionNameListSchemas         ActionName = "list_schemas"
+	ActionNameListTables          ActionName = "list_tables"
+	ActionNameDescribeTable       ActionName = "describe_table"
+	ActionNameListViews           ActionName = "list_views"
+	ActionNameListFunctions       ActionName = "list_functions"
+	ActionNameSchemaSnapshot      ActionName = "schema_snapshot"
+	ActionNameSettings            ActionName = "settings"
+	ActionNameHealthReport        ActionName = "health_report"
+	// ActionNameSaveSettingsBaseline writes to the registry and is not routed to an instance
+	ActionNameSaveSettingsBaseline ActionName = "save_settings_baseline"
+	// ActionNameSaveAlertRule and ActionNameSilenceAlert write alerting configuration to the registry
+	ActionNameSaveAlertRule ActionName = "save_alert_rule"
+	ActionNameSilenceAlert  ActionName = "silence_alert"
+	// ActionNameSaveStatementsSnapshot stores a pg_stat_statements snapshot in the registry
+	ActionNameSaveStatementsSnapshot ActionName = "save_statements_snapshot"
 )
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+)
+
+// Severities of alert rules
+const (
+	AlertSeverityCritical = "critical"
+	AlertSeverityWarning  = "warning"
+	AlertSeverityInfo     = "info"
+)
+
+// States of an alert. An alert is pending while its condition holds for less than the
+// rule duration, firing once it held long enough and resolved after it stopped holding.
+const (
+	AlertStatePending  = "pending"
+	AlertStateFiring   = "firing"
+	AlertStateResolved = "resolved"
+)
+
+// AlertRule is a condition on the output of an action, evaluated per instance on a schedule
+type AlertRule struct {
+	ID          int                    `db:"id"`
+	Name        string                 `db:"name"`
+	Description string                 `db:"description"`
+	Action      ActionName             `db:"action"`
+	Params      map[string]interface{} `db:"params"`
+	Expression  string                 `db:"expression"`
+	For         time.Duration          `db:"for_seconds"`
+	Severity    string                 `db:"severity"`
+	Selector    string                 `db:"selector"` // label selector of the instances, empty for all
+	Enabled     bool                   `db:"enabled"`
+	CreatedBy   string                 `db:"created_by"`
+	CreatedAt   time.Time              `db:"created_at"`
+	UpdatedAt   time.Time              `db:"updated_at"`
+}
+
+// AlertState tracks one rule on one instance
+type AlertState struct {
+	RuleName     string     `db:"rule_name"`
+	InstanceName string     `db:"instance_name"`
+	State        string     `db:"state"`
+	Value        *float64   `db:"value"` // left operand of the condition when last evaluated
+	ActiveSince  time.Time  `db:"active_since"`
+	FiringSince  *time.Time `db:"firing_since"`
+	ResolvedAt   *time.Time `db:"resolved_at"`
+	Notified     bool       `db:"notified"` // a firing notification was delivered
+	EvaluatedAt  time.Time  `db:"evaluated_at"`
+}
+
+// AlertSilence suppresses notifications of matching alerts between StartsAt and EndsAt.
+// An empty rule or instance name matches every rule or instance.
+type AlertSilence struct {
+	ID           int       `db:"id"`
+	RuleName     string    `db:"rule_name"`
+	InstanceName string    `db:"instance_name"`
+	StartsAt     time.Time `db:"starts_at"`
+	EndsAt       time.Time `db:"ends_at"`
+	Comment      string    `db:"comment"`
+	CreatedBy    string    `db:"created_by"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// Matches reports whether the silence covers the rule on the instance at the given time
+func (s AlertSilence) Matches(ruleName, instanceName string, at time.Time) bool {
+	return (s.RuleName == "" || s.RuleName == ruleName) &&
+		(s.InstanceName == "" || s.InstanceName == instanceName) &&
+		!at.Before(s.StartsAt) && at.Before(s.EndsAt)
+}
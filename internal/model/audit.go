@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// Audit outcomes of a state-changing action
+const (
+	AuditOutcomeRefused              = "refused"
+	AuditOutcomeConfirmationRequired = "confirmation_required"
+	AuditOutcomeExecuted             = "executed"
+	AuditOutcomeFailed               = "failed"
+)
+
+// AuditRecord is one step of a state-changing action requested through MCP
+type AuditRecord struct {
+	ID           int        `db:"id"`
+	InstanceName string     `db:"instance_name"`
+	Action       ActionName `db:"action"`
+	Username     string     `db:"username"`
+	Role         string     `db:"role"`
+	PID          int        `db:"pid"`
+	QueryStart   *time.Time `db:"query_start"`
+	TargetUser   string     `db:"target_user"`
+	TargetQuery  string     `db:"target_query"`
+	Outcome      string     `db:"outcome"`
+	Detail       string     `db:"detail"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
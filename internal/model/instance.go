@@ -11,6 +11,7 @@ type Instance struct {
 	Description     string    `db:"description"`
 	CreatorUsername string    `db:"creator_username"`
 	Status          string    `db:"status"`
+	Labels          Labels    `db:"labels"`
 	CreatedAt       time.Time `db:"created_at"`
 	UpdatedAt       time.Time `db:"updated_at"`
 }
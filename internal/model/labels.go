@@ -0,0 +1,81 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Labels are free-form key/value tags of an instance, e.g. env=prod
+type Labels map[string]string
+
+// Value stores labels as a JSON object
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(l))
+}
+
+// Scan reads labels from a JSON object
+func (l *Labels) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*l = Labels{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into labels", src)
+	}
+
+	labels := Labels{}
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return fmt.Errorf("failed to decode labels: %w", err)
+	}
+	*l = labels
+	return nil
+}
+
+// Matches reports whether the labels contain every key/value pair of the selector
+func (l Labels) Matches(selector Labels) bool {
+	for key, value := range selector {
+		if l[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders labels as a sorted key=value list
+func (l Labels) String() string {
+	pairs := make([]string, 0, len(l))
+	for key, value := range l {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseLabelSelector parses a comma-separated key=value list such as "env=prod,region=eu"
+func ParseLabelSelector(selector string) (Labels, error) {
+	labels := Labels{}
+	for _, pair := range strings.Split(selector, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q, expected key=value pairs separated by commas", selector)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
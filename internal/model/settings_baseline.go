@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// SettingsBaseline is a named profile of expected PostgreSQL settings. Values use
+// postgresql.conf syntax; a value without a unit is in the unit of pg_settings.
+type SettingsBaseline struct {
+	ID          int               `db:"id"`
+	Name        string            `db:"name"`
+	Description string            `db:"description"`
+	Settings    map[string]string `db:"settings"`
+	CreatedBy   string            `db:"created_by"`
+	CreatedAt   time.Time         `db:"created_at"`
+	UpdatedAt   time.Time         `db:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+// StatementCounters are the cumulative pg_stat_statements counters of one queryid,
+// summed over the users and databases that ran it
+type StatementCounters struct {
+	QueryID        int64   `json:"queryid"`
+	Query          string  `json:"query"`
+	Calls          int64   `json:"calls"`
+	TotalExecTime  float64 `json:"total_exec_time"`
+	Rows           int64   `json:"rows"`
+	SharedBlksHit  int64   `json:"shared_blks_hit"`
+	SharedBlksRead int64   `json:"shared_blks_read"`
+	TempBlks       int64   `json:"temp_blks"`
+	WalBytes       int64   `json:"wal_bytes"`
+}
+
+// StatementsSnapshot is pg_stat_statements of an instance at one point in time.
+// Statements is empty when the snapshot is listed without its contents.
+type StatementsSnapshot struct {
+	ID           int                 `db:"id"`
+	InstanceName string              `db:"instance_name"`
+	StatsReset   *time.Time          `db:"stats_reset"`
+	Statements   []StatementCounters `db:"statements"`
+	Count        int                 `db:"count"`
+	Comment      string              `db:"comment"`
+	CreatedBy    string              `db:"created_by"`
+	CreatedAt    time.Time           `db:"created_at"`
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -16,22 +17,68 @@ type ClientInterface interface {
 	GetCacheHitRateGlobal(ctx context.Context) (*CacheHitRate, error)
 	GetCheckpointsStats(ctx context.Context) (*CheckpointsStats, error)
 	GetWalActivity(ctx context.Context) (*WalActivity, error)
+	GetIOStats(ctx context.Context, limit int) (*IOReport, error)
 	GetTablesInfo(ctx context.Context, limit int) ([]TableInfo, error)
+	GetTablesInfoDB(ctx context.Context, dbName string, limit int) ([]TableInfo, error)
 	GetLockingInfo(ctx context.Context, dbName string) ([]LockInfo, error)
 	GetChangedSettings(ctx context.Context) ([]SettingInfo, error)
+	GetSettings(ctx context.Context) ([]SettingInfo, error)
 	GetIndexStats(ctx context.Context, limit int) ([]IndexStats, error)
 	GetActiveQueries(ctx context.Context, dbName string, minDuration int) ([]ActiveQuery, error)
 	GetConnectionStats(ctx context.Context) (*ConnectionSummary, error)
+	GetActivitySample(ctx context.Context) ([]ActivitySample, error)
 	GetSlowQueries(ctx context.Context, limit int) ([]SlowQuery, error)
+	GetStatements(ctx context.Context, orderBy, database, user string, limit int) (*StatementsReport, error)
 	GetDatabaseSizes(ctx context.Context) ([]DatabaseSize, error)
+	GetReplicationStatus(ctx context.Context) (*ReplicationStatus, error)
+	GetReplicationSlots(ctx context.Context, retainedWalThreshold int64) ([]ReplicationSlot, error)
+	GetXidWraparound(ctx context.Context, holdersLimit int) (*XidWraparound, error)
+	GetTableFreezeAge(ctx context.Context, dbName string, limit int) ([]TableFreezeAge, error)
+	GetMaintenanceProgress(ctx context.Context) ([]MaintenanceProgress, error)
+	GetTableBloat(ctx context.Context, dbName string, limit int, exact bool) ([]TableBloat, error)
+	GetIndexBloat(ctx context.Context, dbName string, limit int, exact bool) ([]IndexBloat, error)
+	GetIndexAdvice(ctx context.Context, dbName string) (*IndexAdvice, error)
+	GetLockGraph(ctx context.Context) (*LockGraph, error)
+	GetBackend(ctx context.Context, pid int) (*Backend, error)
+	CancelBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error)
+	TerminateBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error)
+	ListSchemas(ctx context.Context, dbName string) ([]SchemaInfo, error)
+	ListTables(ctx context.Context, dbName, schema string, limit, offset int) ([]TableSchema, error)
+	DescribeTable(ctx context.Context, dbName, schema, table string) (*TableDetail, error)
+	ListViews(ctx context.Context, dbName, schema string, limit, offset int) ([]ViewInfo, error)
+	ListFunctions(ctx context.Context, dbName, schema string, limit, offset int) ([]FunctionInfo, error)
+	GetSchemaSnapshot(ctx context.Context, dbName, schema string) (*SchemaSnapshot, error)
+	Ping(ctx context.Context) error
 	Version() *Version
 }
 
+// Пулы к остальным базам инстанса небольшие: через них идут только запросы к каталогу конкретной БД.
+// Число пулов тоже ограничено, так что сверх основного пула клиент держит не больше
+// maxDatabasePools * databasePoolMaxOpenConns соединений
+const (
+	databasePoolMaxOpenConns = 2
+	databasePoolMaxIdleConns = 1
+	maxDatabasePools         = 4
+)
+
+// databasePool - пул к другой базе инстанса, время последнего обращения и число вызовов, которые им пользуются.
+// Вытесненный пул убирается из карты сразу, а закрывается, когда его отпустит последний вызов
+type databasePool struct {
+	db       *sql.DB
+	lastUsed time.Time
+	users    int
+	evicted  bool
+}
+
 type Client struct {
 	db      *sql.DB
 	config  *Config
 	version *Version
 	mu      sync.RWMutex
+
+	// databases - лениво открытые пулы к другим базам инстанса (pg_class и статистика таблиц видны только изнутри БД)
+	databases   map[string]*databasePool
+	databasesMu sync.Mutex
 }
 
 func NewClient(config *Config) (*Client, error) {
@@ -50,8 +97,9 @@ func NewClient(config *Config) (*Client, error) {
 	db.SetConnMaxLifetime(config.ConnMaxLifetime)
 
 	client := &Client{
-		db:     db,
-		config: config,
+		db:        db,
+		config:    config,
+		databases: make(map[string]*databasePool),
 	}
 
 	return client, nil
@@ -77,12 +125,128 @@ func (c *Client) Connect(ctx context.Context) error {
 }
 
 func (c *Client) Close() error {
+	c.databasesMu.Lock()
+	for name, pool := range c.databases {
+		pool.db.Close()
+		delete(c.databases, name)
+	}
+	c.databasesMu.Unlock()
+
 	if c.db != nil {
 		return c.db.Close()
 	}
 	return nil
 }
 
+// dbFor возвращает пул соединений к базе dbName и функцию, которую вызывающий вызывает, когда закончил с пулом.
+// Для базы из конфига используется основной пул, для остальных пул открывается при первом обращении.
+// Имя проверяется по pg_database, поэтому в строку подключения попадают только существующие базы.
+// Сверх maxDatabasePools из карты убирается пул, к которому дольше всего не обращались
+func (c *Client) dbFor(ctx context.Context, dbName string) (*sql.DB, func(), error) {
+	if dbName == "" || dbName == c.config.Database {
+		return c.db, func() {}, nil
+	}
+
+	c.databasesMu.Lock()
+	if pool, exists := c.databases[dbName]; exists {
+		db, release := c.acquireDatabasePool(pool)
+		c.databasesMu.Unlock()
+		return db, release, nil
+	}
+	c.databasesMu.Unlock()
+
+	// Проверка идёт по сети, поэтому без мьютекса: медленная база не задерживает обращения к остальным
+	var exists bool
+	if err := c.db.QueryRowContext(ctx, SelectDatabaseConnectable, dbName).Scan(&exists); err != nil {
+		return nil, nil, fmt.Errorf("failed to check database %s: %w", dbName, err)
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("database %s not found or does not allow connections", dbName)
+	}
+
+	config := *c.config
+	config.Database = dbName
+
+	db, err := sql.Open("postgres", config.ConnectionString())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open connection to database %s: %w", dbName, err)
+	}
+
+	db.SetMaxOpenConns(databasePoolMaxOpenConns)
+	db.SetMaxIdleConns(databasePoolMaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	c.databasesMu.Lock()
+	defer c.databasesMu.Unlock()
+
+	// Пока шла проверка, пул мог открыть параллельный вызов; новый ещё не подключался
+	if pool, exists := c.databases[dbName]; exists {
+		db.Close()
+		db, release := c.acquireDatabasePool(pool)
+		return db, release, nil
+	}
+
+	if len(c.databases) >= maxDatabasePools {
+		c.evictDatabasePool()
+	}
+
+	pool := &databasePool{db: db}
+	c.databases[dbName] = pool
+	db, release := c.acquireDatabasePool(pool)
+	return db, release, nil
+}
+
+// acquireDatabasePool отмечает обращение к пулу. Вызывается под databasesMu
+func (c *Client) acquireDatabasePool(pool *databasePool) (*sql.DB, func()) {
+	pool.lastUsed = time.Now()
+	pool.users++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			c.databasesMu.Lock()
+			defer c.databasesMu.Unlock()
+
+			pool.users--
+			if pool.evicted && pool.users == 0 {
+				go pool.db.Close()
+			}
+		})
+	}
+	return pool.db, release
+}
+
+// evictDatabasePool убирает из карты пул, к которому дольше всего не обращались, предпочитая свободные.
+// Свободный пул закрывается сразу, занятый - когда его отпустит последний вызов. Вызывается под databasesMu
+func (c *Client) evictDatabasePool() {
+	var oldest string
+	for name, pool := range c.databases {
+		if oldest == "" {
+			oldest = name
+			continue
+		}
+		current := c.databases[oldest]
+		idle, currentIdle := pool.users == 0, current.users == 0
+		if idle != currentIdle {
+			if idle {
+				oldest = name
+			}
+			continue
+		}
+		if pool.lastUsed.Before(current.lastUsed) {
+			oldest = name
+		}
+	}
+
+	pool := c.databases[oldest]
+	delete(c.databases, oldest)
+	pool.evicted = true
+	if pool.users == 0 {
+		// Close ждёт соединения пула, поэтому не под мьютексом
+		go pool.db.Close()
+	}
+}
+
 func (c *Client) DB() *sql.DB {
 	return c.db
 }
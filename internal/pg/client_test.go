@@ -0,0 +1,126 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolClosed проверяет, закрыт ли пул, не подключаясь: у закрытого пула ошибка раньше проверки контекста
+func poolClosed(db *sql.DB) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := db.PingContext(ctx)
+	return err != nil && err.Error() == "sql: database is closed"
+}
+
+func newPoolsClient(t *testing.T) (*Client, sqlmock.Sqlmock) {
+	client, mock := newMockClient(t, 16)
+	client.config = &Config{Host: "localhost", Port: 5432, Database: "postgres", SSLMode: "disable"}
+	return client, mock
+}
+
+func expectDatabaseExists(mock sqlmock.Sqlmock, dbName string) {
+	mock.ExpectQuery(SelectDatabaseConnectable).
+		WithArgs(dbName).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+}
+
+func TestDBFor_EvictsTheLeastRecentlyUsedIdlePool(t *testing.T) {
+	ctx := context.Background()
+	client, mock := newPoolsClient(t)
+
+	dbs := map[string]*sql.DB{}
+	var held func()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		expectDatabaseExists(mock, name)
+		db, release, err := client.dbFor(ctx, name)
+		require.NoError(t, err)
+		dbs[name] = db
+		if name == "a" {
+			held = release // a используется дольше всех и всё ещё занят
+			continue
+		}
+		release()
+	}
+
+	expectDatabaseExists(mock, "e")
+	_, release, err := client.dbFor(ctx, "e")
+	require.NoError(t, err)
+	release()
+
+	assert.Len(t, client.databases, maxDatabasePools)
+	assert.Contains(t, client.databases, "a", "a pool in use is not evicted while idle ones remain")
+	assert.NotContains(t, client.databases, "b")
+	assert.Eventually(t, func() bool { return poolClosed(dbs["b"]) }, time.Second, 10*time.Millisecond)
+
+	db, release, err := client.dbFor(ctx, "postgres")
+	require.NoError(t, err)
+	assert.Same(t, client.db, db, "the configured database uses the main pool")
+	release()
+
+	held()
+	assert.False(t, poolClosed(dbs["a"]))
+}
+
+func TestDBFor_ClosesAnEvictedPoolOnlyAfterItsLastUser(t *testing.T) {
+	ctx := context.Background()
+
+	// Сколько раз пул откроется заново, зависит от порядка вызовов, поэтому проверки
+	// существования ожидаются с запасом, по одной на вызов, и не все будут выполнены
+	main, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer main.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	client := &Client{
+		db:        main,
+		config:    &Config{Host: "localhost", Port: 5432, Database: "postgres", SSLMode: "disable"},
+		version:   &Version{Major: 16},
+		databases: make(map[string]*databasePool),
+	}
+	defer client.Close()
+
+	const databases, callers = 8, 32
+	for i := 0; i < callers; i++ {
+		expectDatabaseExists(mock, fmt.Sprintf("db%d", i%databases))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db, release, err := client.dbFor(ctx, fmt.Sprintf("db%d", i%databases))
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer release()
+
+			time.Sleep(time.Duration(i%4) * time.Millisecond)
+			if poolClosed(db) {
+				errs <- fmt.Errorf("db%d: pool closed while in use", i%databases)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.LessOrEqual(t, len(client.databases), maxDatabasePools)
+	for name, pool := range client.databases {
+		assert.Zero(t, pool.users, name)
+		assert.False(t, poolClosed(pool.db), name)
+	}
+}
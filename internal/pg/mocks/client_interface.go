@@ -7,6 +7,8 @@ import (
 	pg "psql-mcp-registry/internal/pg"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ClientInterface is an autogenerated mock type for the ClientInterface type
@@ -14,6 +16,64 @@ type ClientInterface struct {
 	mock.Mock
 }
 
+// CancelBackend provides a mock function with given fields: ctx, pid, queryStart
+func (_m *ClientInterface) CancelBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error) {
+	ret := _m.Called(ctx, pid, queryStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelBackend")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (bool, error)); ok {
+		return rf(ctx, pid, queryStart)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) bool); ok {
+		r0 = rf(ctx, pid, queryStart)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, pid, queryStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DescribeTable provides a mock function with given fields: ctx, dbName, schema, table
+func (_m *ClientInterface) DescribeTable(ctx context.Context, dbName string, schema string, table string) (*pg.TableDetail, error) {
+	ret := _m.Called(ctx, dbName, schema, table)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeTable")
+	}
+
+	var r0 *pg.TableDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*pg.TableDetail, error)); ok {
+		return rf(ctx, dbName, schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *pg.TableDetail); ok {
+		r0 = rf(ctx, dbName, schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.TableDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, dbName, schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetActiveQueries provides a mock function with given fields: ctx, dbName, minDuration
 func (_m *ClientInterface) GetActiveQueries(ctx context.Context, dbName string, minDuration int) ([]pg.ActiveQuery, error) {
 	ret := _m.Called(ctx, dbName, minDuration)
@@ -44,6 +104,66 @@ func (_m *ClientInterface) GetActiveQueries(ctx context.Context, dbName string,
 	return r0, r1
 }
 
+// GetActivitySample provides a mock function with given fields: ctx
+func (_m *ClientInterface) GetActivitySample(ctx context.Context) ([]pg.ActivitySample, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActivitySample")
+	}
+
+	var r0 []pg.ActivitySample
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]pg.ActivitySample, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []pg.ActivitySample); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.ActivitySample)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBackend provides a mock function with given fields: ctx, pid
+func (_m *ClientInterface) GetBackend(ctx context.Context, pid int) (*pg.Backend, error) {
+	ret := _m.Called(ctx, pid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackend")
+	}
+
+	var r0 *pg.Backend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*pg.Backend, error)); ok {
+		return rf(ctx, pid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *pg.Backend); ok {
+		r0 = rf(ctx, pid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.Backend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, pid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCacheHitRateDB provides a mock function with given fields: ctx, dbName
 func (_m *ClientInterface) GetCacheHitRateDB(ctx context.Context, dbName string) (*pg.CacheHitRate, error) {
 	ret := _m.Called(ctx, dbName)
@@ -254,6 +374,96 @@ func (_m *ClientInterface) GetDatabaseSizes(ctx context.Context) ([]pg.DatabaseS
 	return r0, r1
 }
 
+// GetIOStats provides a mock function with given fields: ctx, limit
+func (_m *ClientInterface) GetIOStats(ctx context.Context, limit int) (*pg.IOReport, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIOStats")
+	}
+
+	var r0 *pg.IOReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*pg.IOReport, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *pg.IOReport); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.IOReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIndexAdvice provides a mock function with given fields: ctx, dbName
+func (_m *ClientInterface) GetIndexAdvice(ctx context.Context, dbName string) (*pg.IndexAdvice, error) {
+	ret := _m.Called(ctx, dbName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexAdvice")
+	}
+
+	var r0 *pg.IndexAdvice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*pg.IndexAdvice, error)); ok {
+		return rf(ctx, dbName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *pg.IndexAdvice); ok {
+		r0 = rf(ctx, dbName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.IndexAdvice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, dbName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIndexBloat provides a mock function with given fields: ctx, dbName, limit, exact
+func (_m *ClientInterface) GetIndexBloat(ctx context.Context, dbName string, limit int, exact bool) ([]pg.IndexBloat, error) {
+	ret := _m.Called(ctx, dbName, limit, exact)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexBloat")
+	}
+
+	var r0 []pg.IndexBloat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, bool) ([]pg.IndexBloat, error)); ok {
+		return rf(ctx, dbName, limit, exact)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, bool) []pg.IndexBloat); ok {
+		r0 = rf(ctx, dbName, limit, exact)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.IndexBloat)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, bool) error); ok {
+		r1 = rf(ctx, dbName, limit, exact)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetIndexStats provides a mock function with given fields: ctx, limit
 func (_m *ClientInterface) GetIndexStats(ctx context.Context, limit int) ([]pg.IndexStats, error) {
 	ret := _m.Called(ctx, limit)
@@ -284,6 +494,36 @@ func (_m *ClientInterface) GetIndexStats(ctx context.Context, limit int) ([]pg.I
 	return r0, r1
 }
 
+// GetLockGraph provides a mock function with given fields: ctx
+func (_m *ClientInterface) GetLockGraph(ctx context.Context) (*pg.LockGraph, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLockGraph")
+	}
+
+	var r0 *pg.LockGraph
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*pg.LockGraph, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *pg.LockGraph); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.LockGraph)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLockingInfo provides a mock function with given fields: ctx, dbName
 func (_m *ClientInterface) GetLockingInfo(ctx context.Context, dbName string) ([]pg.LockInfo, error) {
 	ret := _m.Called(ctx, dbName)
@@ -314,6 +554,156 @@ func (_m *ClientInterface) GetLockingInfo(ctx context.Context, dbName string) ([
 	return r0, r1
 }
 
+// GetMaintenanceProgress provides a mock function with given fields: ctx
+func (_m *ClientInterface) GetMaintenanceProgress(ctx context.Context) ([]pg.MaintenanceProgress, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMaintenanceProgress")
+	}
+
+	var r0 []pg.MaintenanceProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]pg.MaintenanceProgress, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []pg.MaintenanceProgress); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.MaintenanceProgress)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicationSlots provides a mock function with given fields: ctx, retainedWalThreshold
+func (_m *ClientInterface) GetReplicationSlots(ctx context.Context, retainedWalThreshold int64) ([]pg.ReplicationSlot, error) {
+	ret := _m.Called(ctx, retainedWalThreshold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplicationSlots")
+	}
+
+	var r0 []pg.ReplicationSlot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]pg.ReplicationSlot, error)); ok {
+		return rf(ctx, retainedWalThreshold)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []pg.ReplicationSlot); ok {
+		r0 = rf(ctx, retainedWalThreshold)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.ReplicationSlot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, retainedWalThreshold)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicationStatus provides a mock function with given fields: ctx
+func (_m *ClientInterface) GetReplicationStatus(ctx context.Context) (*pg.ReplicationStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReplicationStatus")
+	}
+
+	var r0 *pg.ReplicationStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*pg.ReplicationStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *pg.ReplicationStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.ReplicationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchemaSnapshot provides a mock function with given fields: ctx, dbName, schema
+func (_m *ClientInterface) GetSchemaSnapshot(ctx context.Context, dbName string, schema string) (*pg.SchemaSnapshot, error) {
+	ret := _m.Called(ctx, dbName, schema)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchemaSnapshot")
+	}
+
+	var r0 *pg.SchemaSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*pg.SchemaSnapshot, error)); ok {
+		return rf(ctx, dbName, schema)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *pg.SchemaSnapshot); ok {
+		r0 = rf(ctx, dbName, schema)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.SchemaSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, dbName, schema)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSettings provides a mock function with given fields: ctx
+func (_m *ClientInterface) GetSettings(ctx context.Context) ([]pg.SettingInfo, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSettings")
+	}
+
+	var r0 []pg.SettingInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]pg.SettingInfo, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []pg.SettingInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.SettingInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSlowQueries provides a mock function with given fields: ctx, limit
 func (_m *ClientInterface) GetSlowQueries(ctx context.Context, limit int) ([]pg.SlowQuery, error) {
 	ret := _m.Called(ctx, limit)
@@ -344,6 +734,96 @@ func (_m *ClientInterface) GetSlowQueries(ctx context.Context, limit int) ([]pg.
 	return r0, r1
 }
 
+// GetStatements provides a mock function with given fields: ctx, orderBy, database, user, limit
+func (_m *ClientInterface) GetStatements(ctx context.Context, orderBy string, database string, user string, limit int) (*pg.StatementsReport, error) {
+	ret := _m.Called(ctx, orderBy, database, user, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatements")
+	}
+
+	var r0 *pg.StatementsReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int) (*pg.StatementsReport, error)); ok {
+		return rf(ctx, orderBy, database, user, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int) *pg.StatementsReport); ok {
+		r0 = rf(ctx, orderBy, database, user, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.StatementsReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int) error); ok {
+		r1 = rf(ctx, orderBy, database, user, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTableBloat provides a mock function with given fields: ctx, dbName, limit, exact
+func (_m *ClientInterface) GetTableBloat(ctx context.Context, dbName string, limit int, exact bool) ([]pg.TableBloat, error) {
+	ret := _m.Called(ctx, dbName, limit, exact)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTableBloat")
+	}
+
+	var r0 []pg.TableBloat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, bool) ([]pg.TableBloat, error)); ok {
+		return rf(ctx, dbName, limit, exact)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, bool) []pg.TableBloat); ok {
+		r0 = rf(ctx, dbName, limit, exact)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.TableBloat)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, bool) error); ok {
+		r1 = rf(ctx, dbName, limit, exact)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTableFreezeAge provides a mock function with given fields: ctx, dbName, limit
+func (_m *ClientInterface) GetTableFreezeAge(ctx context.Context, dbName string, limit int) ([]pg.TableFreezeAge, error) {
+	ret := _m.Called(ctx, dbName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTableFreezeAge")
+	}
+
+	var r0 []pg.TableFreezeAge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]pg.TableFreezeAge, error)); ok {
+		return rf(ctx, dbName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []pg.TableFreezeAge); ok {
+		r0 = rf(ctx, dbName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.TableFreezeAge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, dbName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTablesInfo provides a mock function with given fields: ctx, limit
 func (_m *ClientInterface) GetTablesInfo(ctx context.Context, limit int) ([]pg.TableInfo, error) {
 	ret := _m.Called(ctx, limit)
@@ -374,6 +854,36 @@ func (_m *ClientInterface) GetTablesInfo(ctx context.Context, limit int) ([]pg.T
 	return r0, r1
 }
 
+// GetTablesInfoDB provides a mock function with given fields: ctx, dbName, limit
+func (_m *ClientInterface) GetTablesInfoDB(ctx context.Context, dbName string, limit int) ([]pg.TableInfo, error) {
+	ret := _m.Called(ctx, dbName, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTablesInfoDB")
+	}
+
+	var r0 []pg.TableInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]pg.TableInfo, error)); ok {
+		return rf(ctx, dbName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []pg.TableInfo); ok {
+		r0 = rf(ctx, dbName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.TableInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, dbName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWalActivity provides a mock function with given fields: ctx
 func (_m *ClientInterface) GetWalActivity(ctx context.Context) (*pg.WalActivity, error) {
 	ret := _m.Called(ctx)
@@ -404,6 +914,202 @@ func (_m *ClientInterface) GetWalActivity(ctx context.Context) (*pg.WalActivity,
 	return r0, r1
 }
 
+// GetXidWraparound provides a mock function with given fields: ctx, holdersLimit
+func (_m *ClientInterface) GetXidWraparound(ctx context.Context, holdersLimit int) (*pg.XidWraparound, error) {
+	ret := _m.Called(ctx, holdersLimit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetXidWraparound")
+	}
+
+	var r0 *pg.XidWraparound
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*pg.XidWraparound, error)); ok {
+		return rf(ctx, holdersLimit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *pg.XidWraparound); ok {
+		r0 = rf(ctx, holdersLimit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pg.XidWraparound)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, holdersLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFunctions provides a mock function with given fields: ctx, dbName, schema, limit, offset
+func (_m *ClientInterface) ListFunctions(ctx context.Context, dbName string, schema string, limit int, offset int) ([]pg.FunctionInfo, error) {
+	ret := _m.Called(ctx, dbName, schema, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFunctions")
+	}
+
+	var r0 []pg.FunctionInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) ([]pg.FunctionInfo, error)); ok {
+		return rf(ctx, dbName, schema, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) []pg.FunctionInfo); ok {
+		r0 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.FunctionInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
+		r1 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSchemas provides a mock function with given fields: ctx, dbName
+func (_m *ClientInterface) ListSchemas(ctx context.Context, dbName string) ([]pg.SchemaInfo, error) {
+	ret := _m.Called(ctx, dbName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSchemas")
+	}
+
+	var r0 []pg.SchemaInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]pg.SchemaInfo, error)); ok {
+		return rf(ctx, dbName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []pg.SchemaInfo); ok {
+		r0 = rf(ctx, dbName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.SchemaInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, dbName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTables provides a mock function with given fields: ctx, dbName, schema, limit, offset
+func (_m *ClientInterface) ListTables(ctx context.Context, dbName string, schema string, limit int, offset int) ([]pg.TableSchema, error) {
+	ret := _m.Called(ctx, dbName, schema, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTables")
+	}
+
+	var r0 []pg.TableSchema
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) ([]pg.TableSchema, error)); ok {
+		return rf(ctx, dbName, schema, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) []pg.TableSchema); ok {
+		r0 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.TableSchema)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
+		r1 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListViews provides a mock function with given fields: ctx, dbName, schema, limit, offset
+func (_m *ClientInterface) ListViews(ctx context.Context, dbName string, schema string, limit int, offset int) ([]pg.ViewInfo, error) {
+	ret := _m.Called(ctx, dbName, schema, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListViews")
+	}
+
+	var r0 []pg.ViewInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) ([]pg.ViewInfo, error)); ok {
+		return rf(ctx, dbName, schema, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) []pg.ViewInfo); ok {
+		r0 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pg.ViewInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
+		r1 = rf(ctx, dbName, schema, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *ClientInterface) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TerminateBackend provides a mock function with given fields: ctx, pid, queryStart
+func (_m *ClientInterface) TerminateBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error) {
+	ret := _m.Called(ctx, pid, queryStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TerminateBackend")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (bool, error)); ok {
+		return rf(ctx, pid, queryStart)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) bool); ok {
+		r0 = rf(ctx, pid, queryStart)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, pid, queryStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Version provides a mock function with no fields
 func (_m *ClientInterface) Version() *pg.Version {
 	ret := _m.Called()
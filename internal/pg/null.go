@@ -0,0 +1,116 @@
+package pg
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Nullable-типы оборачивают database/sql, чтобы колонки с NULL сканировались
+// как обычно, а в JSON сериализовались значением или null вместо {"String": ..., "Valid": ...}
+
+var jsonNull = []byte("null")
+
+// NullString - строка, которая может быть NULL
+type NullString struct {
+	sql.NullString
+}
+
+// NewNullString создаёт валидную NullString
+func NewNullString(s string) NullString {
+	return NullString{sql.NullString{String: s, Valid: true}}
+}
+
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.String)
+}
+
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	if string(data) == string(jsonNull) {
+		*n = NullString{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.String)
+}
+
+// NullInt64 - целое число, которое может быть NULL
+type NullInt64 struct {
+	sql.NullInt64
+}
+
+// NewNullInt64 создаёт валидный NullInt64
+func NewNullInt64(v int64) NullInt64 {
+	return NullInt64{sql.NullInt64{Int64: v, Valid: true}}
+}
+
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Int64)
+}
+
+func (n *NullInt64) UnmarshalJSON(data []byte) error {
+	if string(data) == string(jsonNull) {
+		*n = NullInt64{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Int64)
+}
+
+// NullFloat64 - число с плавающей точкой, которое может быть NULL
+type NullFloat64 struct {
+	sql.NullFloat64
+}
+
+// NewNullFloat64 создаёт валидный NullFloat64
+func NewNullFloat64(v float64) NullFloat64 {
+	return NullFloat64{sql.NullFloat64{Float64: v, Valid: true}}
+}
+
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+func (n *NullFloat64) UnmarshalJSON(data []byte) error {
+	if string(data) == string(jsonNull) {
+		*n = NullFloat64{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Float64)
+}
+
+// NullTime - метка времени, которая может быть NULL; сериализуется в RFC 3339
+type NullTime struct {
+	sql.NullTime
+}
+
+// NewNullTime создаёт валидный NullTime
+func NewNullTime(t time.Time) NullTime {
+	return NullTime{sql.NullTime{Time: t, Valid: true}}
+}
+
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Time)
+}
+
+func (n *NullTime) UnmarshalJSON(data []byte) error {
+	if string(data) == string(jsonNull) {
+		*n = NullTime{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Time)
+}
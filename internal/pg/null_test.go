@@ -0,0 +1,46 @@
+package pg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullRow struct {
+	String  NullString  `json:"string"`
+	Int64   NullInt64   `json:"int64"`
+	Float64 NullFloat64 `json:"float64"`
+	Time    NullTime    `json:"time"`
+}
+
+func TestNullTypes_JSONRoundTrip(t *testing.T) {
+	valid := nullRow{
+		String:  NewNullString("idle in transaction"),
+		Int64:   NewNullInt64(-42),
+		Float64: NewNullFloat64(0.25),
+		Time:    NewNullTime(time.Date(2026, 10, 18, 12, 30, 0, 500, time.UTC)),
+	}
+
+	tests := []struct {
+		name string
+		row  nullRow
+		json string
+	}{
+		{"valid", valid, `{"string":"idle in transaction","int64":-42,"float64":0.25,"time":"2026-10-18T12:30:00.0000005Z"}`},
+		{"null", nullRow{}, `{"string":null,"int64":null,"float64":null,"time":null}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.row)
+		require.NoError(t, err, tt.name)
+		assert.JSONEq(t, tt.json, string(data), tt.name)
+
+		// Заполненные значения, чтобы null их сбрасывал
+		decoded := valid
+		require.NoError(t, json.Unmarshal(data, &decoded), tt.name)
+		assert.Equal(t, tt.row, decoded, tt.name)
+	}
+}
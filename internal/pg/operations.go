@@ -4,6 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 // GetDatabaseOverview возвращает основную статистику по БД
@@ -105,6 +111,136 @@ func (c *Client) GetCheckpointsStats(ctx context.Context) (*CheckpointsStats, er
 	return &stats, nil
 }
 
+// GetIOStats возвращает статистику ввода-вывода (version-aware)
+func (c *Client) GetIOStats(ctx context.Context, limit int) (*IOReport, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	// PG ≥16 использует pg_stat_io
+	if version.SupportsStatIO() {
+		return c.getStatIO(ctx)
+	}
+
+	// PG ≤15 - только по отношениям текущей БД из pg_statio_user_*
+	report := &IOReport{Source: IOSourceRelations, Tables: []TableIO{}, Indexes: []IndexIO{}}
+
+	err := c.db.QueryRowContext(ctx, SelectDatabaseStatsReset).Scan(&report.StatsReset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database stats reset: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, SelectTablesIO, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables io: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table TableIO
+		err := rows.Scan(
+			&table.Schema,
+			&table.Table,
+			&table.HeapBlksRead,
+			&table.HeapBlksHit,
+			&table.HeapHitRate,
+			&table.IdxBlksRead,
+			&table.IdxBlksHit,
+			&table.IdxHitRate,
+			&table.ToastBlksRead,
+			&table.ToastBlksHit,
+			&table.ToastHitRate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table io: %w", err)
+		}
+		report.Tables = append(report.Tables, table)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables io: %w", err)
+	}
+
+	indexRows, err := c.db.QueryContext(ctx, SelectIndexesIO, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes io: %w", err)
+	}
+	defer indexRows.Close()
+
+	for indexRows.Next() {
+		var index IndexIO
+		err := indexRows.Scan(
+			&index.Schema,
+			&index.Table,
+			&index.Index,
+			&index.BlksRead,
+			&index.BlksHit,
+			&index.HitRate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan index io: %w", err)
+		}
+		report.Indexes = append(report.Indexes, index)
+	}
+
+	if err = indexRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indexes io: %w", err)
+	}
+
+	return report, nil
+}
+
+func (c *Client) getStatIO(ctx context.Context) (*IOReport, error) {
+	rows, err := c.db.QueryContext(ctx, SelectIOStatsV16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_io: %w", err)
+	}
+	defer rows.Close()
+
+	report := &IOReport{Source: IOSourceStatIO, IO: []IOStat{}}
+
+	for rows.Next() {
+		var stat IOStat
+		var statsReset NullTime
+		err := rows.Scan(
+			&stat.BackendType,
+			&stat.Object,
+			&stat.Context,
+			&stat.Reads,
+			&stat.ReadTime,
+			&stat.Writes,
+			&stat.WriteTime,
+			&stat.Writebacks,
+			&stat.WritebackTime,
+			&stat.Extends,
+			&stat.ExtendTime,
+			&stat.Hits,
+			&stat.HitRate,
+			&stat.Evictions,
+			&stat.Reuses,
+			&stat.Fsyncs,
+			&stat.FsyncTime,
+			&statsReset,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pg_stat_io: %w", err)
+		}
+		// stats_reset общий для всех строк одного backend_type; берём самый поздний
+		if statsReset.Valid && (!report.StatsReset.Valid || statsReset.Time.After(report.StatsReset.Time)) {
+			report.StatsReset = statsReset
+		}
+		report.IO = append(report.IO, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pg_stat_io: %w", err)
+	}
+
+	return report, nil
+}
+
 // GetWalActivity возвращает статистику WAL (только для PG ≥14)
 func (c *Client) GetWalActivity(ctx context.Context) (*WalActivity, error) {
 	version := c.Version()
@@ -137,11 +273,26 @@ func (c *Client) GetWalActivity(ctx context.Context) (*WalActivity, error) {
 
 // GetTablesInfo возвращает статистику по таблицам
 func (c *Client) GetTablesInfo(ctx context.Context, limit int) ([]TableInfo, error) {
+	return c.getTablesInfo(ctx, c.db, limit)
+}
+
+// GetTablesInfoDB возвращает статистику по таблицам конкретной БД
+func (c *Client) GetTablesInfoDB(ctx context.Context, dbName string, limit int) ([]TableInfo, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.getTablesInfo(ctx, db, limit)
+}
+
+func (c *Client) getTablesInfo(ctx context.Context, db *sql.DB, limit int) ([]TableInfo, error) {
 	if limit <= 0 {
 		limit = 200 // значение по умолчанию
 	}
 
-	rows, err := c.db.QueryContext(ctx, SelectTablesInfoLight, limit)
+	rows, err := db.QueryContext(ctx, SelectTablesInfoLight, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables info: %w", err)
 	}
@@ -202,7 +353,7 @@ func (c *Client) GetLockingInfo(ctx context.Context, dbName string) ([]LockInfo,
 			&lock.WaitEvent,
 			&lock.State,
 			&lock.QueryStart,
-			&lock.BlockingPids,
+			pq.Array(&lock.BlockingPids),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan lock info: %w", err)
@@ -219,7 +370,16 @@ func (c *Client) GetLockingInfo(ctx context.Context, dbName string) ([]LockInfo,
 
 // GetChangedSettings возвращает настройки, изменённые от дефолта
 func (c *Client) GetChangedSettings(ctx context.Context) ([]SettingInfo, error) {
-	rows, err := c.db.QueryContext(ctx, SelectCurrentSettingsChanged)
+	return c.querySettings(ctx, SelectCurrentSettingsChanged)
+}
+
+// GetSettings возвращает все настройки из pg_settings
+func (c *Client) GetSettings(ctx context.Context) ([]SettingInfo, error) {
+	return c.querySettings(ctx, SelectAllSettings)
+}
+
+func (c *Client) querySettings(ctx context.Context, query string) ([]SettingInfo, error) {
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query settings: %w", err)
 	}
@@ -308,6 +468,7 @@ func (c *Client) GetActiveQueries(ctx context.Context, dbName string, minDuratio
 			&query.Username,
 			&query.Database,
 			&query.State,
+			&query.QueryStart,
 			&query.DurationSeconds,
 			&query.WaitEventType,
 			&query.WaitEvent,
@@ -326,6 +487,55 @@ func (c *Client) GetActiveQueries(ctx context.Context, dbName string, minDuratio
 	return queries, nil
 }
 
+// GetActivitySample возвращает активные сессии в текущий момент (version-aware)
+func (c *Client) GetActivitySample(ctx context.Context) ([]ActivitySample, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	query := SelectActivitySampleLegacy
+	switch {
+	case version.SupportsActivityQueryID():
+		query = SelectActivitySampleV14
+	case version.SupportsBackendType():
+		query = SelectActivitySampleV10
+	}
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity sample: %w", err)
+	}
+	defer rows.Close()
+
+	samples := []ActivitySample{}
+
+	for rows.Next() {
+		var sample ActivitySample
+		err := rows.Scan(
+			&sample.PID,
+			&sample.Username,
+			&sample.Database,
+			&sample.BackendType,
+			&sample.WaitEventType,
+			&sample.WaitEvent,
+			&sample.QueryID,
+			&sample.Query,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity sample: %w", err)
+		}
+		samples = append(samples, sample)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activity sample: %w", err)
+	}
+
+	return samples, nil
+}
+
 // GetConnectionStats возвращает статистику соединений
 func (c *Client) GetConnectionStats(ctx context.Context) (*ConnectionSummary, error) {
 	var stats ConnectionSummary
@@ -349,15 +559,8 @@ func (c *Client) GetConnectionStats(ctx context.Context) (*ConnectionSummary, er
 // GetSlowQueries возвращает топ медленных запросов из pg_stat_statements
 // Требует установленного extension pg_stat_statements
 func (c *Client) GetSlowQueries(ctx context.Context, limit int) ([]SlowQuery, error) {
-	// Проверить наличие pg_stat_statements
-	var exists bool
-	err := c.db.QueryRowContext(ctx,
-		"SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')").Scan(&exists)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check pg_stat_statements: %w", err)
-	}
-	if !exists {
-		return nil, fmt.Errorf("pg_stat_statements extension is not installed")
+	if err := c.checkStatementsExtension(ctx); err != nil {
+		return nil, err
 	}
 
 	if limit <= 0 {
@@ -396,6 +599,124 @@ func (c *Client) GetSlowQueries(ctx context.Context, limit int) ([]SlowQuery, er
 	return queries, nil
 }
 
+// checkStatementsExtension проверяет, что pg_stat_statements установлен в базе подключения
+func (c *Client) checkStatementsExtension(ctx context.Context) error {
+	var exists bool
+	err := c.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')").Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check pg_stat_statements: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("pg_stat_statements extension is not installed")
+	}
+	return nil
+}
+
+// GetStatements возвращает топ pg_stat_statements по измерению orderBy (version-aware)
+// database и user фильтруют по имени базы и роли, пустая строка - без фильтра.
+// limit StatementsAll читает все записи (для снимков)
+func (c *Client) GetStatements(ctx context.Context, orderBy, database, user string, limit int) (*StatementsReport, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	if orderBy == "" {
+		orderBy = StatementsOrderTotalTime
+	}
+	if !slices.Contains(StatementsOrders, orderBy) {
+		return nil, fmt.Errorf("unknown order %q, expected one of %s", orderBy, strings.Join(StatementsOrders, ", "))
+	}
+	if orderBy == StatementsOrderWalBytes && !version.SupportsStatementsExecTime() {
+		return nil, fmt.Errorf("WAL columns of pg_stat_statements not supported in PostgreSQL %d.%d (requires ≥13)",
+			version.Major, version.Minor)
+	}
+
+	if err := c.checkStatementsExtension(ctx); err != nil {
+		return nil, err
+	}
+
+	// LIMIT NULL в PostgreSQL - без ограничения
+	var rowLimit interface{} = limit
+	switch {
+	case limit == StatementsAll:
+		rowLimit = nil
+	case limit <= 0:
+		rowLimit = 20
+	}
+
+	query := SelectStatementsLegacy
+	switch {
+	case version.SupportsStatementsSharedBlkTime():
+		query = SelectStatementsV17
+	case version.SupportsStatementsExecTime():
+		query = SelectStatementsV13
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, database, user, orderBy, rowLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statements: %w", err)
+	}
+	defer rows.Close()
+
+	report := &StatementsReport{OrderBy: orderBy, Statements: []Statement{}}
+
+	for rows.Next() {
+		var statement Statement
+		err := rows.Scan(
+			&statement.QueryID,
+			&statement.UserID,
+			&statement.Username,
+			&statement.DbID,
+			&statement.Database,
+			&statement.Query,
+			&statement.Calls,
+			&statement.TotalExecTime,
+			&statement.MeanExecTime,
+			&statement.StddevExecTime,
+			&statement.TotalPlanTime,
+			&statement.MeanPlanTime,
+			&statement.Rows,
+			&statement.SharedBlksHit,
+			&statement.SharedBlksRead,
+			&statement.CacheHitPercent,
+			&statement.TempBlksRead,
+			&statement.TempBlksWritten,
+			&statement.BlkReadTime,
+			&statement.BlkWriteTime,
+			&statement.WalRecords,
+			&statement.WalBytes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statement: %w", err)
+		}
+		report.Statements = append(report.Statements, statement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating statements: %w", err)
+	}
+
+	infoQuery := SelectStatementsInfoLegacy
+	if version.SupportsStatementsInfo() {
+		infoQuery = SelectStatementsInfoV14
+	}
+
+	err = c.db.QueryRowContext(ctx, infoQuery).Scan(
+		&report.Info.Dealloc,
+		&report.Info.StatsReset,
+		&report.Info.Max,
+		&report.Info.Tracked,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pg_stat_statements info: %w", err)
+	}
+
+	return report, nil
+}
+
 // GetDatabaseSizes возвращает размеры всех баз данных
 func (c *Client) GetDatabaseSizes(ctx context.Context) ([]DatabaseSize, error) {
 	rows, err := c.db.QueryContext(ctx, SelectDatabaseSizes)
@@ -424,3 +745,1307 @@ func (c *Client) GetDatabaseSizes(ctx context.Context) ([]DatabaseSize, error) {
 
 	return databases, nil
 }
+
+// GetReplicationStatus возвращает состояние репликации (version-aware)
+// Роль определяется через pg_is_in_recovery(): на primary читается pg_stat_replication,
+// на standby - pg_stat_wal_receiver и прогресс применения WAL
+func (c *Client) GetReplicationStatus(ctx context.Context) (*ReplicationStatus, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	if !version.SupportsReplicationStats() {
+		return nil, fmt.Errorf("replication status not supported in PostgreSQL %d.%d (requires ≥10)",
+			version.Major, version.Minor)
+	}
+
+	var inRecovery bool
+	if err := c.db.QueryRowContext(ctx, SelectIsInRecovery).Scan(&inRecovery); err != nil {
+		return nil, fmt.Errorf("failed to detect replication role: %w", err)
+	}
+
+	status := &ReplicationStatus{
+		Role:     ReplicationRolePrimary,
+		Replicas: []ReplicaInfo{},
+	}
+
+	if !inRecovery {
+		replicas, err := c.getReplicas(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		status.Replicas = replicas
+		return status, nil
+	}
+
+	status.Role = ReplicationRoleStandby
+
+	walReceiver, err := c.getWalReceiver(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	status.WalReceiver = walReceiver
+
+	var replay StandbyReplay
+	err = c.db.QueryRowContext(ctx, SelectStandbyReplay).Scan(
+		&replay.ReceiveLsn,
+		&replay.ReplayLsn,
+		&replay.ReplayLagBytes,
+		&replay.LastReplayTime,
+		&replay.ReplayDelaySeconds,
+		&replay.ReplayPaused,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standby replay progress: %w", err)
+	}
+	status.Replay = &replay
+
+	return status, nil
+}
+
+func (c *Client) getReplicas(ctx context.Context, version *Version) ([]ReplicaInfo, error) {
+	query := SelectReplicationLegacy
+	if version.SupportsReplyTime() {
+		query = SelectReplicationV12
+	}
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replication stats: %w", err)
+	}
+	defer rows.Close()
+
+	replicas := []ReplicaInfo{}
+
+	for rows.Next() {
+		var replica ReplicaInfo
+		err := rows.Scan(
+			&replica.PID,
+			&replica.Username,
+			&replica.ApplicationName,
+			&replica.ClientAddr,
+			&replica.State,
+			&replica.SyncState,
+			&replica.SyncPriority,
+			&replica.SentLsn,
+			&replica.WriteLsn,
+			&replica.FlushLsn,
+			&replica.ReplayLsn,
+			&replica.SentLagBytes,
+			&replica.WriteLagBytes,
+			&replica.FlushLagBytes,
+			&replica.ReplayLagBytes,
+			&replica.WriteLagSeconds,
+			&replica.FlushLagSeconds,
+			&replica.ReplayLagSeconds,
+			&replica.BackendStart,
+			&replica.ReplyTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan replica: %w", err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating replicas: %w", err)
+	}
+
+	return replicas, nil
+}
+
+func (c *Client) getWalReceiver(ctx context.Context, version *Version) (*WalReceiverInfo, error) {
+	query := SelectWalReceiverLegacy
+	switch {
+	case version.SupportsWalReceiverFlushedLsn():
+		query = SelectWalReceiverV13
+	case version.SupportsWalReceiverSender():
+		query = SelectWalReceiverV11
+	}
+
+	var receiver WalReceiverInfo
+	err := c.db.QueryRowContext(ctx, query).Scan(
+		&receiver.PID,
+		&receiver.Status,
+		&receiver.ReceiveStartLsn,
+		&receiver.WrittenLsn,
+		&receiver.FlushedLsn,
+		&receiver.ReceivedTli,
+		&receiver.LatestEndLsn,
+		&receiver.LatestEndTime,
+		&receiver.LastMsgReceiptTime,
+		&receiver.SlotName,
+		&receiver.SenderHost,
+		&receiver.SenderPort,
+		&receiver.Conninfo,
+	)
+	if err != nil {
+		// WAL receiver не запущен: standby восстанавливается из архива или потерял primary
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get WAL receiver status: %w", err)
+	}
+
+	return &receiver, nil
+}
+
+// GetReplicationSlots возвращает слоты репликации (version-aware)
+// Слот помечается at_risk, если он неактивен и удерживает больше retainedWalThreshold байт WAL,
+// а в PG ≥13 - ещё и если wal_status = unreserved или lost
+func (c *Client) GetReplicationSlots(ctx context.Context, retainedWalThreshold int64) ([]ReplicationSlot, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	if !version.SupportsReplicationStats() {
+		return nil, fmt.Errorf("replication slots not supported in PostgreSQL %d.%d (requires ≥10)",
+			version.Major, version.Minor)
+	}
+
+	query := SelectReplicationSlotsLegacy
+	if version.SupportsSlotWalStatus() {
+		query = SelectReplicationSlotsV13
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, retainedWalThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replication slots: %w", err)
+	}
+	defer rows.Close()
+
+	slots := []ReplicationSlot{}
+
+	for rows.Next() {
+		var slot ReplicationSlot
+		var atRisk sql.NullBool
+		err := rows.Scan(
+			&slot.SlotName,
+			&slot.Plugin,
+			&slot.SlotType,
+			&slot.Database,
+			&slot.Temporary,
+			&slot.Active,
+			&slot.ActivePID,
+			&slot.Xmin,
+			&slot.CatalogXmin,
+			&slot.RestartLsn,
+			&slot.ConfirmedFlushLsn,
+			&slot.RetainedWalBytes,
+			&slot.WalStatus,
+			&slot.SafeWalSize,
+			&atRisk,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan replication slot: %w", err)
+		}
+		// NULL, если restart_lsn ещё не задан: такой слот WAL не удерживает
+		slot.AtRisk = atRisk.Valid && atRisk.Bool
+		slots = append(slots, slot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating replication slots: %w", err)
+	}
+
+	return slots, nil
+}
+
+// GetXidWraparound возвращает возраст XID/MultiXact по базам и держателей xmin horizon
+func (c *Client) GetXidWraparound(ctx context.Context, holdersLimit int) (*XidWraparound, error) {
+	result := &XidWraparound{
+		WraparoundLimit: WraparoundLimit,
+		Databases:       []DatabaseXidAge{},
+		HorizonHolders:  []XminHorizonHolder{},
+	}
+
+	err := c.db.QueryRowContext(ctx, SelectFreezeSettings).Scan(
+		&result.FreezeMaxAge,
+		&result.MultixactFreezeMaxAge,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get freeze settings: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, SelectDatabaseXidAge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database xid age: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var db DatabaseXidAge
+		err := rows.Scan(
+			&db.DatabaseName,
+			&db.XidAge,
+			&db.MxidAge,
+			&db.XidFreezeMaxAgePct,
+			&db.XidWraparoundPct,
+			&db.MxidFreezeMaxAgePct,
+			&db.MxidWraparoundPct,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan database xid age: %w", err)
+		}
+		result.Databases = append(result.Databases, db)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating database xid age: %w", err)
+	}
+
+	if holdersLimit <= 0 {
+		holdersLimit = 20 // значение по умолчанию
+	}
+
+	holders, err := c.db.QueryContext(ctx, SelectXminHorizonHolders, holdersLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query xmin horizon holders: %w", err)
+	}
+	defer holders.Close()
+
+	for holders.Next() {
+		var holder XminHorizonHolder
+		err := holders.Scan(
+			&holder.Kind,
+			&holder.Holder,
+			&holder.Username,
+			&holder.Database,
+			&holder.XminAge,
+			&holder.State,
+			&holder.Since,
+			&holder.Query,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan xmin horizon holder: %w", err)
+		}
+		result.HorizonHolders = append(result.HorizonHolders, holder)
+	}
+
+	if err = holders.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating xmin horizon holders: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetTableFreezeAge возвращает таблицы базы dbName с самым старым relfrozenxid
+func (c *Client) GetTableFreezeAge(ctx context.Context, dbName string, limit int) ([]TableFreezeAge, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, SelectTableFreezeAge, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table freeze age: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []TableFreezeAge{}
+
+	for rows.Next() {
+		var table TableFreezeAge
+		err := rows.Scan(
+			&table.SchemaName,
+			&table.TableName,
+			&table.RelKind,
+			&table.XidAge,
+			&table.MxidAge,
+			&table.XidFreezeMaxAgePct,
+			&table.XidWraparoundPct,
+			&table.TotalBytes,
+			&table.LastVacuum,
+			&table.LastAutovacuum,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table freeze age: %w", err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table freeze age: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetMaintenanceProgress возвращает ход обслуживающих операций (version-aware)
+// VACUUM - всегда, CREATE INDEX и CLUSTER - в PG ≥12, ANALYZE - в PG ≥13
+func (c *Client) GetMaintenanceProgress(ctx context.Context) ([]MaintenanceProgress, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	queries := []string{SelectProgressVacuum}
+	if version.SupportsProgressAnalyze() {
+		queries = append(queries, SelectProgressAnalyze)
+	}
+	if version.SupportsProgressCreateIndex() {
+		queries = append(queries, SelectProgressCreateIndex, SelectProgressCluster)
+	}
+
+	progress := []MaintenanceProgress{}
+
+	for _, query := range queries {
+		rows, err := c.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query maintenance progress: %w", err)
+		}
+
+		for rows.Next() {
+			var p MaintenanceProgress
+			err := rows.Scan(
+				&p.Command,
+				&p.PID,
+				&p.Database,
+				&p.RelID,
+				&p.Relation,
+				&p.Phase,
+				&p.BlocksTotal,
+				&p.BlocksDone,
+				&p.ProgressPct,
+				&p.ElapsedSeconds,
+				&p.Username,
+				&p.WaitEventType,
+				&p.WaitEvent,
+				&p.Query,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan maintenance progress: %w", err)
+			}
+			progress = append(progress, p)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating maintenance progress: %w", err)
+		}
+	}
+
+	c.resolveProgressRelations(ctx, progress)
+
+	return progress, nil
+}
+
+// resolveProgressRelations дочитывает имена таблиц операций в других базах через их пулы.
+// Ошибка по одной базе не мешает остальным: у операции остаются datname и relid
+func (c *Client) resolveProgressRelations(ctx context.Context, progress []MaintenanceProgress) {
+	oidsByDatabase := make(map[string][]int64)
+	for _, p := range progress {
+		if !p.Relation.Valid && p.Database.Valid && p.RelID != 0 {
+			oidsByDatabase[p.Database.String] = append(oidsByDatabase[p.Database.String], p.RelID)
+		}
+	}
+
+	for dbName, oids := range oidsByDatabase {
+		names, err := c.relationNames(ctx, dbName, oids)
+		if err != nil {
+			continue
+		}
+		for i := range progress {
+			p := &progress[i]
+			if name, ok := names[p.RelID]; ok && !p.Relation.Valid && p.Database.String == dbName {
+				p.Relation = NewNullString(name)
+			}
+		}
+	}
+}
+
+// relationNames возвращает имена отношений базы dbName по OID
+func (c *Client) relationNames(ctx context.Context, dbName string, oids []int64) (map[int64]string, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, SelectRelationNames, pq.Array(oids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relation names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[int64]string)
+	for rows.Next() {
+		var oid int64
+		var name string
+		if err := rows.Scan(&oid, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan relation name: %w", err)
+		}
+		names[oid] = name
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating relation names: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetTableBloat возвращает таблицы базы dbName с наибольшим потерянным местом
+// exact - точный режим через pgstattuple (читает таблицы целиком), иначе оценка по pg_stats
+func (c *Client) GetTableBloat(ctx context.Context, dbName string, limit int, exact bool) ([]TableBloat, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	query := SelectTableBloatEstimate
+	if exact {
+		if err := requirePgstattuple(ctx, db); err != nil {
+			return nil, err
+		}
+		query = SelectTableBloatExact
+	}
+
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table bloat: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []TableBloat{}
+
+	for rows.Next() {
+		var table TableBloat
+		dest := []any{
+			&table.SchemaName,
+			&table.TableName,
+			&table.RealBytes,
+			&table.WastedBytes,
+			&table.BloatPct,
+			&table.Fillfactor,
+		}
+		if exact {
+			dest = append(dest, &table.DeadTupleBytes, &table.FreeBytes)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan table bloat: %w", err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table bloat: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetIndexBloat возвращает btree-индексы базы dbName с наибольшим потерянным местом
+// exact - точный режим через pgstatindex, иначе оценка по pg_stats
+func (c *Client) GetIndexBloat(ctx context.Context, dbName string, limit int, exact bool) ([]IndexBloat, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	query := SelectIndexBloatEstimate
+	if exact {
+		if err := requirePgstattuple(ctx, db); err != nil {
+			return nil, err
+		}
+		query = SelectIndexBloatExact
+	}
+
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index bloat: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := []IndexBloat{}
+
+	for rows.Next() {
+		var index IndexBloat
+		dest := []any{
+			&index.SchemaName,
+			&index.TableName,
+			&index.IndexName,
+			&index.RealBytes,
+			&index.WastedBytes,
+			&index.BloatPct,
+			&index.Fillfactor,
+		}
+		if exact {
+			dest = append(dest, &index.AvgLeafDensity)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan index bloat: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating index bloat: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// requirePgstattuple проверяет, что pgstattuple установлен в базе, к которой относится пул
+func requirePgstattuple(ctx context.Context, db *sql.DB) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, SelectExtensionInstalled, "pgstattuple").Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check pgstattuple: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("pgstattuple extension is not installed in this database, exact mode is not available")
+	}
+	return nil
+}
+
+// GetIndexAdvice ищет в базе dbName неиспользуемые, дублирующиеся, избыточные и невалидные индексы,
+// а также внешние ключи без индекса. Возвращает только предложения, DDL не выполняется
+func (c *Client) GetIndexAdvice(ctx context.Context, dbName string) (*IndexAdvice, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	invalidQuery := SelectInvalidIndexesLegacy
+	if version.SupportsReindexConcurrently() {
+		invalidQuery = SelectInvalidIndexesV12
+	}
+
+	checks := []struct {
+		kind  string
+		query string
+	}{
+		{IndexFindingInvalid, invalidQuery},
+		{IndexFindingDuplicate, SelectDuplicateIndexes},
+		{IndexFindingRedundant, SelectRedundantIndexes},
+		{IndexFindingUnused, SelectUnusedIndexes},
+		{IndexFindingUnindexedFK, SelectUnindexedForeignKeys},
+	}
+
+	advice := &IndexAdvice{Findings: []IndexFinding{}}
+
+	if err := db.QueryRowContext(ctx, SelectStatsReset).Scan(&advice.StatsReset); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to query stats reset: %w", err)
+	}
+
+	for _, check := range checks {
+		rows, err := db.QueryContext(ctx, check.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s indexes: %w", check.kind, err)
+		}
+
+		for rows.Next() {
+			finding := IndexFinding{Kind: check.kind}
+			err := rows.Scan(
+				&finding.SchemaName,
+				&finding.TableName,
+				&finding.IndexName,
+				&finding.Related,
+				&finding.SizeBytes,
+				&finding.Definition,
+				&finding.Reason,
+				&finding.Suggestion,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s indexes: %w", check.kind, err)
+			}
+			advice.Findings = append(advice.Findings, finding)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating %s indexes: %w", check.kind, err)
+		}
+	}
+
+	return advice, nil
+}
+
+// GetLockGraph строит граф ожиданий блокировок по всему инстансу (PG ≥9.6)
+func (c *Client) GetLockGraph(ctx context.Context) (*LockGraph, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	if !version.SupportsBlockingPids() {
+		return nil, fmt.Errorf("lock graph requires pg_blocking_pids (PostgreSQL 9.6+), server is %d.%d", version.Major, version.Minor)
+	}
+
+	query := SelectLockWaitEdgesLegacy
+	if version.SupportsLockWaitStart() {
+		query = SelectLockWaitEdgesV14
+	}
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lock graph: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []LockWaitEdge{}
+
+	for rows.Next() {
+		var e LockWaitEdge
+		err := rows.Scan(
+			&e.WaiterPID,
+			&e.BlockerPID,
+			&e.LockType,
+			&e.Relation,
+			&e.WaiterMode,
+			&e.BlockerMode,
+			&e.BlockerGranted,
+			&e.Database,
+			&e.WaiterUsername,
+			&e.WaiterQuery,
+			&e.WaiterWaitSeconds,
+			&e.BlockerUsername,
+			&e.BlockerState,
+			&e.BlockerQuery,
+			&e.BlockerXactSeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lock graph: %w", err)
+		}
+		edges = append(edges, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lock graph: %w", err)
+	}
+
+	return buildLockGraph(edges), nil
+}
+
+// buildLockGraph находит корневые блокировщики и проставляет глубину ожидания.
+// Корень - процесс, который кого-то блокирует, но сам ничего не ждёт.
+// Сессии в цикле ожиданий (дедлок до срабатывания deadlock_timeout) недостижимы из корней и остаются с глубиной 0
+func buildLockGraph(edges []LockWaitEdge) *LockGraph {
+	waiters := make(map[int][]int) // блокирующий -> ожидающие
+	waiting := make(map[int]bool)
+	for _, e := range edges {
+		waiters[e.BlockerPID] = append(waiters[e.BlockerPID], e.WaiterPID)
+		waiting[e.WaiterPID] = true
+	}
+
+	depth := make(map[int]int)
+	roots := []LockRoot{}
+
+	for _, e := range edges {
+		if waiting[e.BlockerPID] {
+			continue
+		}
+		if _, seen := depth[e.BlockerPID]; seen {
+			continue
+		}
+		depth[e.BlockerPID] = 0
+
+		root := LockRoot{
+			PID:           e.BlockerPID,
+			Username:      e.BlockerUsername,
+			Database:      e.Database,
+			State:         e.BlockerState,
+			Query:         e.BlockerQuery,
+			XactSeconds:   e.BlockerXactSeconds,
+			DirectWaiters: len(waiters[e.BlockerPID]),
+		}
+
+		// Обход в ширину: глубина сессии - кратчайшее расстояние до любого корня
+		visited := map[int]bool{e.BlockerPID: true}
+		queue := []int{e.BlockerPID}
+		for level := 1; len(queue) > 0; level++ {
+			var next []int
+			for _, pid := range queue {
+				for _, w := range waiters[pid] {
+					if visited[w] {
+						continue
+					}
+					visited[w] = true
+					next = append(next, w)
+					root.TotalWaiters++
+					root.MaxDepth = level
+					if d, ok := depth[w]; !ok || level < d {
+						depth[w] = level
+					}
+				}
+			}
+			queue = next
+		}
+
+		roots = append(roots, root)
+	}
+
+	for i := range edges {
+		edges[i].Depth = depth[edges[i].WaiterPID]
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].TotalWaiters > roots[j].TotalWaiters
+	})
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Depth < edges[j].Depth
+	})
+
+	return &LockGraph{Roots: roots, Edges: edges}
+}
+
+// GetBackend возвращает процесс по PID (version-aware)
+func (c *Client) GetBackend(ctx context.Context, pid int) (*Backend, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	query := SelectBackendLegacy
+	if version.SupportsBackendType() {
+		query = SelectBackendV10
+	}
+
+	var backend Backend
+	err := c.db.QueryRowContext(ctx, query, pid).Scan(
+		&backend.PID,
+		&backend.Username,
+		&backend.Database,
+		&backend.ApplicationName,
+		&backend.BackendType,
+		&backend.State,
+		&backend.QueryStart,
+		&backend.Query,
+		&backend.Superuser,
+		&backend.ClientBackend,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("backend %d not found", pid)
+		}
+		return nil, fmt.Errorf("failed to get backend: %w", err)
+	}
+
+	return &backend, nil
+}
+
+// CancelBackend отменяет текущий запрос процесса pid, если он всё ещё выполняет запрос,
+// начатый в queryStart, и не является служебным процессом или процессом суперпользователя
+func (c *Client) CancelBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error) {
+	return c.signalBackend(ctx, pid, queryStart, false)
+}
+
+// TerminateBackend завершает процесс pid при тех же условиях, что и CancelBackend
+func (c *Client) TerminateBackend(ctx context.Context, pid int, queryStart time.Time) (bool, error) {
+	return c.signalBackend(ctx, pid, queryStart, true)
+}
+
+func (c *Client) signalBackend(ctx context.Context, pid int, queryStart time.Time, terminate bool) (bool, error) {
+	version := c.Version()
+
+	if version == nil {
+		return false, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	query := SignalBackendLegacy
+	if version.SupportsBackendType() {
+		query = SignalBackendV10
+	}
+
+	var signalled bool
+	err := c.db.QueryRowContext(ctx, query, pid, queryStart, terminate).Scan(&signalled)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("backend %d no longer matches: its query finished, the pid was reused or it is protected", pid)
+		}
+		return false, fmt.Errorf("failed to signal backend: %w", err)
+	}
+
+	return signalled, nil
+}
+
+// ListSchemas возвращает пользовательские схемы базы dbName
+func (c *Client) ListSchemas(ctx context.Context, dbName string) ([]SchemaInfo, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, SelectSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	schemas := []SchemaInfo{}
+
+	for rows.Next() {
+		var schema SchemaInfo
+		err := rows.Scan(
+			&schema.Name,
+			&schema.Owner,
+			&schema.Tables,
+			&schema.Views,
+			&schema.Functions,
+			&schema.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schemas: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// ListTables возвращает страницу таблиц базы dbName с колонками
+// schema - фильтр по схеме (пусто - все схемы)
+func (c *Client) ListTables(ctx context.Context, dbName, schema string, limit, offset int) ([]TableSchema, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, SelectSchemaTables, schema, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []TableSchema{}
+	oids := []int64{}
+
+	for rows.Next() {
+		var oid int64
+		table := TableSchema{Columns: []ColumnInfo{}}
+		err := rows.Scan(
+			&oid,
+			&table.SchemaName,
+			&table.TableName,
+			&table.Kind,
+			&table.EstimatedRows,
+			&table.TotalBytes,
+			&table.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, table)
+		oids = append(oids, oid)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	columns, err := queryColumns(ctx, db, oids)
+	if err != nil {
+		return nil, err
+	}
+	for i, oid := range oids {
+		if cols, exists := columns[oid]; exists {
+			tables[i].Columns = cols
+		}
+	}
+
+	return tables, nil
+}
+
+// DescribeTable возвращает колонки, ограничения, индексы и определение (для представлений) отношения
+func (c *Client) DescribeTable(ctx context.Context, dbName, schema, table string) (*TableDetail, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var oid int64
+	detail := TableDetail{
+		TableSchema: TableSchema{Columns: []ColumnInfo{}},
+		Constraints: []ConstraintInfo{},
+		Indexes:     []IndexDefinition{},
+	}
+
+	err = db.QueryRowContext(ctx, SelectRelation, schema, table).Scan(
+		&oid,
+		&detail.SchemaName,
+		&detail.TableName,
+		&detail.Kind,
+		&detail.EstimatedRows,
+		&detail.TotalBytes,
+		&detail.Comment,
+		&detail.ViewDefinition,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("table %s.%s not found", schema, table)
+		}
+		return nil, fmt.Errorf("failed to get table: %w", err)
+	}
+
+	columns, err := queryColumns(ctx, db, []int64{oid})
+	if err != nil {
+		return nil, err
+	}
+	if cols, exists := columns[oid]; exists {
+		detail.Columns = cols
+	}
+
+	constraints, err := queryConstraints(ctx, db, []int64{oid})
+	if err != nil {
+		return nil, err
+	}
+	if cons, exists := constraints[oid]; exists {
+		detail.Constraints = cons
+	}
+
+	indexes, err := queryIndexDefinitions(ctx, db, []int64{oid})
+	if err != nil {
+		return nil, err
+	}
+	if idx, exists := indexes[oid]; exists {
+		detail.Indexes = idx
+	}
+
+	return &detail, nil
+}
+
+// ListViews возвращает страницу представлений базы dbName с определениями
+func (c *Client) ListViews(ctx context.Context, dbName, schema string, limit, offset int) ([]ViewInfo, error) {
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, SelectSchemaViews, schema, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []ViewInfo{}
+
+	for rows.Next() {
+		var view ViewInfo
+		err := rows.Scan(
+			&view.SchemaName,
+			&view.ViewName,
+			&view.Materialized,
+			&view.Definition,
+			&view.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan view: %w", err)
+		}
+		views = append(views, view)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating views: %w", err)
+	}
+
+	return views, nil
+}
+
+// ListFunctions возвращает страницу функций и процедур базы dbName (version-aware)
+func (c *Client) ListFunctions(ctx context.Context, dbName, schema string, limit, offset int) ([]FunctionInfo, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	query := SelectSchemaFunctionsLegacy
+	if version.SupportsProcKind() {
+		query = SelectSchemaFunctionsV11
+	}
+
+	rows, err := db.QueryContext(ctx, query, schema, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query functions: %w", err)
+	}
+	defer rows.Close()
+
+	functions := []FunctionInfo{}
+
+	for rows.Next() {
+		var function FunctionInfo
+		err := rows.Scan(
+			&function.SchemaName,
+			&function.Name,
+			&function.Kind,
+			&function.Arguments,
+			&function.ReturnType,
+			&function.Language,
+			&function.Volatility,
+			&function.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan function: %w", err)
+		}
+		functions = append(functions, function)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating functions: %w", err)
+	}
+
+	return functions, nil
+}
+
+// queryColumns возвращает колонки отношений, сгруппированные по OID
+func queryColumns(ctx context.Context, db *sql.DB, oids []int64) (map[int64][]ColumnInfo, error) {
+	columns := make(map[int64][]ColumnInfo)
+	if len(oids) == 0 {
+		return columns, nil
+	}
+
+	rows, err := db.QueryContext(ctx, SelectColumns, pq.Array(oids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oid int64
+		var column ColumnInfo
+		err := rows.Scan(
+			&oid,
+			&column.Name,
+			&column.Type,
+			&column.Nullable,
+			&column.Default,
+			&column.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		columns[oid] = append(columns[oid], column)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+// GetSchemaSnapshot возвращает все таблицы базы dbName с колонками, ограничениями и индексами
+// и установленные расширения. schema - фильтр по схеме (пусто - все схемы)
+func (c *Client) GetSchemaSnapshot(ctx context.Context, dbName, schema string) (*SchemaSnapshot, error) {
+	version := c.Version()
+
+	if version == nil {
+		return nil, fmt.Errorf("version not detected, call Connect() first")
+	}
+
+	db, release, err := c.dbFor(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	query := SelectSnapshotTablesLegacy
+	if version.SupportsPartitioning() {
+		query = SelectSnapshotTablesV10
+	}
+
+	rows, err := db.QueryContext(ctx, query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	snapshot := SchemaSnapshot{
+		Tables:     []TableDetail{},
+		Extensions: []ExtensionInfo{},
+	}
+	oids := []int64{}
+
+	for rows.Next() {
+		var oid int64
+		table := TableDetail{
+			TableSchema: TableSchema{Columns: []ColumnInfo{}},
+			Constraints: []ConstraintInfo{},
+			Indexes:     []IndexDefinition{},
+		}
+		err := rows.Scan(
+			&oid,
+			&table.SchemaName,
+			&table.TableName,
+			&table.Kind,
+			&table.EstimatedRows,
+			&table.TotalBytes,
+			&table.Comment,
+			&table.PartitionKey,
+			&table.ParentSchema,
+			&table.ParentTable,
+			&table.PartitionBound,
+			&table.ForeignServer,
+			&table.ForeignOptions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		snapshot.Tables = append(snapshot.Tables, table)
+		oids = append(oids, oid)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	columns, err := queryColumns(ctx, db, oids)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := queryConstraints(ctx, db, oids)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := queryIndexDefinitions(ctx, db, oids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, oid := range oids {
+		if cols, exists := columns[oid]; exists {
+			snapshot.Tables[i].Columns = cols
+		}
+		if cons, exists := constraints[oid]; exists {
+			snapshot.Tables[i].Constraints = cons
+		}
+		if idx, exists := indexes[oid]; exists {
+			snapshot.Tables[i].Indexes = idx
+		}
+	}
+
+	extensionRows, err := db.QueryContext(ctx, SelectExtensions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query extensions: %w", err)
+	}
+	defer extensionRows.Close()
+
+	for extensionRows.Next() {
+		var extension ExtensionInfo
+		if err := extensionRows.Scan(&extension.Name, &extension.Version, &extension.Schema); err != nil {
+			return nil, fmt.Errorf("failed to scan extension: %w", err)
+		}
+		snapshot.Extensions = append(snapshot.Extensions, extension)
+	}
+
+	if err = extensionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating extensions: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// queryConstraints возвращает ограничения отношений, сгруппированные по OID
+func queryConstraints(ctx context.Context, db *sql.DB, oids []int64) (map[int64][]ConstraintInfo, error) {
+	constraints := make(map[int64][]ConstraintInfo)
+	if len(oids) == 0 {
+		return constraints, nil
+	}
+
+	rows, err := db.QueryContext(ctx, SelectConstraints, pq.Array(oids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oid int64
+		var constraint ConstraintInfo
+		err := rows.Scan(
+			&oid,
+			&constraint.Name,
+			&constraint.Type,
+			pq.Array(&constraint.Columns),
+			&constraint.Definition,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan constraint: %w", err)
+		}
+		if constraint.Columns == nil {
+			constraint.Columns = []string{}
+		}
+		constraints[oid] = append(constraints[oid], constraint)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating constraints: %w", err)
+	}
+
+	return constraints, nil
+}
+
+// queryIndexDefinitions возвращает индексы отношений, сгруппированные по OID
+func queryIndexDefinitions(ctx context.Context, db *sql.DB, oids []int64) (map[int64][]IndexDefinition, error) {
+	indexes := make(map[int64][]IndexDefinition)
+	if len(oids) == 0 {
+		return indexes, nil
+	}
+
+	rows, err := db.QueryContext(ctx, SelectIndexDefinitions, pq.Array(oids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index definitions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oid int64
+		var index IndexDefinition
+		err := rows.Scan(
+			&oid,
+			&index.Name,
+			&index.Definition,
+			&index.Primary,
+			&index.Unique,
+			&index.Valid,
+			&index.SizeBytes,
+			&index.Constraint,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan index definition: %w", err)
+		}
+		indexes[oid] = append(indexes[oid], index)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating index definitions: %w", err)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,804 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockClient возвращает клиент версии major поверх sqlmock; ожидания проверяются в конце теста
+func newMockClient(t *testing.T, major int) (*Client, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	})
+
+	client := &Client{
+		db:        db,
+		config:    &Config{},
+		version:   &Version{Major: major},
+		databases: make(map[string]*databasePool),
+	}
+	return client, mock
+}
+
+func TestBuildLockGraph(t *testing.T) {
+	type root struct {
+		pid, direct, total, maxDepth int
+	}
+
+	tests := []struct {
+		name  string
+		edges [][2]int // ожидающий, блокирующий
+		roots []root
+		depth map[int]int // ожидающий -> глубина
+	}{
+		{
+			name:  "chain",
+			edges: [][2]int{{2, 1}, {3, 2}, {4, 3}},
+			roots: []root{{pid: 1, direct: 1, total: 3, maxDepth: 3}},
+			depth: map[int]int{2: 1, 3: 2, 4: 3},
+		},
+		{
+			name:  "fan-out",
+			edges: [][2]int{{11, 10}, {2, 1}, {3, 1}, {4, 1}, {4, 10}},
+			roots: []root{{pid: 1, direct: 3, total: 3, maxDepth: 1}, {pid: 10, direct: 2, total: 2, maxDepth: 1}},
+			depth: map[int]int{2: 1, 3: 1, 4: 1, 11: 1},
+		},
+		{
+			name:  "cycle",
+			edges: [][2]int{{1, 2}, {2, 1}, {3, 1}},
+			roots: nil,
+			depth: map[int]int{1: 0, 2: 0, 3: 0},
+		},
+		{
+			name:  "self-edge",
+			edges: [][2]int{{1, 1}, {1, 2}, {3, 1}},
+			roots: []root{{pid: 2, direct: 1, total: 2, maxDepth: 2}},
+			depth: map[int]int{1: 1, 3: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		edges := make([]LockWaitEdge, 0, len(tt.edges))
+		for _, e := range tt.edges {
+			edges = append(edges, LockWaitEdge{WaiterPID: e[0], BlockerPID: e[1]})
+		}
+
+		graph := buildLockGraph(edges)
+
+		var roots []root
+		for _, r := range graph.Roots {
+			roots = append(roots, root{pid: r.PID, direct: r.DirectWaiters, total: r.TotalWaiters, maxDepth: r.MaxDepth})
+		}
+		assert.Equal(t, tt.roots, roots, tt.name)
+
+		assert.Len(t, graph.Edges, len(tt.edges), tt.name)
+		for i, e := range graph.Edges {
+			assert.Equal(t, tt.depth[e.WaiterPID], e.Depth, "%s: waiter %d", tt.name, e.WaiterPID)
+			if i > 0 {
+				assert.LessOrEqual(t, graph.Edges[i-1].Depth, e.Depth, "%s: edges are ordered by depth", tt.name)
+			}
+		}
+	}
+}
+
+func TestSignalBackend_MatchesTheExactQueryStart(t *testing.T) {
+	queryStart := time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC)
+
+	for major, query := range map[int]string{9: SignalBackendLegacy, 16: SignalBackendV10} {
+		client, mock := newMockClient(t, major)
+		assert.Contains(t, query, "a.query_start = $2::timestamptz")
+
+		mock.ExpectQuery(query).
+			WithArgs(42, queryStart, true).
+			WillReturnRows(sqlmock.NewRows([]string{"signalled"}).AddRow(true))
+		mock.ExpectQuery(query).
+			WithArgs(42, queryStart, false).
+			WillReturnRows(sqlmock.NewRows([]string{"signalled"}))
+
+		signalled, err := client.TerminateBackend(context.Background(), 42, queryStart)
+		require.NoError(t, err)
+		assert.True(t, signalled)
+
+		_, err = client.CancelBackend(context.Background(), 42, queryStart)
+		assert.ErrorContains(t, err, "no longer matches")
+	}
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	ctx := context.Background()
+	backendStart := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	replicaColumns := []string{"pid", "usename", "application_name", "client_addr", "state", "sync_state", "sync_priority",
+		"sent_lsn", "write_lsn", "flush_lsn", "replay_lsn", "sent_lag_bytes", "write_lag_bytes", "flush_lag_bytes",
+		"replay_lag_bytes", "write_lag", "flush_lag", "replay_lag", "backend_start", "reply_time"}
+	receiverColumns := []string{"pid", "status", "receive_start_lsn", "written_lsn", "flushed_lsn", "received_tli",
+		"latest_end_lsn", "latest_end_time", "last_msg_receipt_time", "slot_name", "sender_host", "sender_port", "conninfo"}
+
+	t.Run("primary", func(t *testing.T) {
+		for major, query := range map[int]string{11: SelectReplicationLegacy, 16: SelectReplicationV12} {
+			client, mock := newMockClient(t, major)
+
+			mock.ExpectQuery(SelectIsInRecovery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+			// Реплика через unix socket и без отставания по времени: NULL в client_addr и *_lag
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(replicaColumns).AddRow(
+				4242, "replicator", "standby1", nil, "streaming", "async", 0,
+				"0/3000148", "0/3000148", "0/3000148", "0/3000060", 0, 0, 0, 232,
+				nil, nil, nil, backendStart, nil,
+			))
+
+			status, err := client.GetReplicationStatus(ctx)
+			require.NoError(t, err, major)
+
+			assert.Equal(t, ReplicationRolePrimary, status.Role, major)
+			assert.Nil(t, status.WalReceiver, major)
+			assert.Nil(t, status.Replay, major)
+			require.Len(t, status.Replicas, 1, major)
+
+			replica := status.Replicas[0]
+			assert.Equal(t, 4242, replica.PID)
+			assert.Equal(t, NewNullString("replicator"), replica.Username)
+			assert.False(t, replica.ClientAddr.Valid)
+			assert.Equal(t, NewNullInt64(232), replica.ReplayLagBytes)
+			assert.False(t, replica.ReplayLagSeconds.Valid)
+			assert.Equal(t, NewNullTime(backendStart), replica.BackendStart)
+			assert.False(t, replica.ReplyTime.Valid)
+		}
+	})
+
+	t.Run("primary without replicas", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectIsInRecovery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+		mock.ExpectQuery(SelectReplicationV12).WillReturnRows(sqlmock.NewRows(replicaColumns))
+
+		status, err := client.GetReplicationStatus(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, status.Replicas, "no replicas serialize as []")
+		assert.Empty(t, status.Replicas)
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		tests := []struct {
+			major      int
+			query      string
+			senderHost interface{}
+			senderPort interface{}
+		}{
+			{major: 10, query: SelectWalReceiverLegacy},
+			{major: 12, query: SelectWalReceiverV11, senderHost: "10.0.0.1", senderPort: 5432},
+			{major: 16, query: SelectWalReceiverV13, senderHost: "10.0.0.1", senderPort: 5432},
+		}
+
+		for _, tt := range tests {
+			client, mock := newMockClient(t, tt.major)
+
+			mock.ExpectQuery(SelectIsInRecovery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+			mock.ExpectQuery(tt.query).WillReturnRows(sqlmock.NewRows(receiverColumns).AddRow(
+				77, "streaming", "0/3000000", nil, "0/3000148", 1,
+				"0/3000148", backendStart, backendStart, nil, tt.senderHost, tt.senderPort, "host=10.0.0.1 port=5432",
+			))
+			mock.ExpectQuery(SelectStandbyReplay).WillReturnRows(sqlmock.NewRows([]string{
+				"receive_lsn", "replay_lsn", "replay_lag_bytes", "last_replay_time", "replay_delay_seconds", "paused",
+			}).AddRow("0/3000148", "0/3000148", 0, nil, 0.0, false))
+
+			status, err := client.GetReplicationStatus(ctx)
+			require.NoError(t, err, tt.major)
+
+			assert.Equal(t, ReplicationRoleStandby, status.Role, tt.major)
+			assert.Empty(t, status.Replicas, tt.major)
+			require.NotNil(t, status.WalReceiver, tt.major)
+			assert.Equal(t, 77, status.WalReceiver.PID, tt.major)
+			assert.Equal(t, NewNullString("0/3000148"), status.WalReceiver.FlushedLsn, tt.major)
+			assert.False(t, status.WalReceiver.WrittenLsn.Valid, tt.major)
+			assert.False(t, status.WalReceiver.SlotName.Valid, tt.major)
+			assert.Equal(t, tt.senderHost != nil, status.WalReceiver.SenderHost.Valid, tt.major)
+			assert.Equal(t, tt.senderPort != nil, status.WalReceiver.SenderPort.Valid, tt.major)
+
+			require.NotNil(t, status.Replay, tt.major)
+			assert.False(t, status.Replay.LastReplayTime.Valid, tt.major)
+			assert.Equal(t, NewNullFloat64(0), status.Replay.ReplayDelaySeconds, tt.major)
+		}
+	})
+
+	t.Run("standby without WAL receiver", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		// Восстановление из архива: pg_stat_wal_receiver пуст
+		mock.ExpectQuery(SelectIsInRecovery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+		mock.ExpectQuery(SelectWalReceiverV13).WillReturnRows(sqlmock.NewRows(receiverColumns))
+		mock.ExpectQuery(SelectStandbyReplay).WillReturnRows(sqlmock.NewRows([]string{
+			"receive_lsn", "replay_lsn", "replay_lag_bytes", "last_replay_time", "replay_delay_seconds", "paused",
+		}).AddRow(nil, "0/3000148", nil, backendStart, 12.5, true))
+
+		status, err := client.GetReplicationStatus(ctx)
+		require.NoError(t, err)
+
+		assert.Nil(t, status.WalReceiver)
+		require.NotNil(t, status.Replay)
+		assert.False(t, status.Replay.ReceiveLsn.Valid)
+		assert.Equal(t, NewNullFloat64(12.5), status.Replay.ReplayDelaySeconds)
+		assert.True(t, status.Replay.ReplayPaused)
+	})
+
+	t.Run("before PostgreSQL 10", func(t *testing.T) {
+		client, _ := newMockClient(t, 9)
+
+		_, err := client.GetReplicationStatus(ctx)
+		assert.ErrorContains(t, err, "requires ≥10")
+	})
+}
+
+func TestGetXidWraparound(t *testing.T) {
+	client, mock := newMockClient(t, 16)
+	since := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(SelectFreezeSettings).WillReturnRows(sqlmock.NewRows([]string{
+		"autovacuum_freeze_max_age", "autovacuum_multixact_freeze_max_age",
+	}).AddRow(200000000, 400000000))
+	mock.ExpectQuery(SelectDatabaseXidAge).WillReturnRows(sqlmock.NewRows([]string{
+		"datname", "xid_age", "mxid_age", "xid_freeze_max_age_pct", "xid_wraparound_pct", "mxid_freeze_max_age_pct", "mxid_wraparound_pct",
+	}).AddRow("shop", 210000000, 1200, 105.0, 9.78, 0.0, 0.0))
+	// Забытая подготовленная транзакция: ни пользователя сессии, ни состояния, ни запроса
+	mock.ExpectQuery(SelectXminHorizonHolders).WithArgs(20).WillReturnRows(sqlmock.NewRows([]string{
+		"kind", "holder", "usename", "datname", "xmin_age", "state", "since", "query",
+	}).
+		AddRow("prepared_transaction", "tx-1", "app", "shop", 209000000, nil, since, nil).
+		AddRow("replication_slot", "old_slot", nil, nil, 180000000, "inactive", nil, nil))
+
+	result, err := client.GetXidWraparound(context.Background(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(200000000), result.FreezeMaxAge)
+	assert.Equal(t, WraparoundLimit, result.WraparoundLimit)
+	require.Len(t, result.Databases, 1)
+	assert.Equal(t, 105.0, result.Databases[0].XidFreezeMaxAgePct)
+
+	require.Len(t, result.HorizonHolders, 2)
+	prepared := result.HorizonHolders[0]
+	assert.Equal(t, NewNullInt64(209000000), prepared.XminAge)
+	assert.Equal(t, NewNullTime(since), prepared.Since)
+	assert.False(t, prepared.State.Valid)
+	assert.False(t, prepared.Query.Valid)
+
+	// У физического слота нет ни пользователя, ни базы, ни времени начала
+	slot := result.HorizonHolders[1]
+	assert.Equal(t, "old_slot", slot.Holder)
+	assert.False(t, slot.Username.Valid)
+	assert.False(t, slot.Database.Valid)
+	assert.Equal(t, NewNullString("inactive"), slot.State)
+	assert.False(t, slot.Since.Valid)
+}
+
+func TestGetTableFreezeAge(t *testing.T) {
+	ctx := context.Background()
+	client, mock := newMockClient(t, 16)
+	lastAutovacuum := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+
+	// Пустое имя - база, к которой подключён клиент
+	mock.ExpectQuery(SelectTableFreezeAge).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{
+		"schema", "table", "relkind", "xid_age", "mxid_age", "xid_freeze_max_age_pct", "xid_wraparound_pct",
+		"total_bytes", "last_vacuum", "last_autovacuum",
+	}).
+		AddRow("public", "orders", "r", 150000000, 10, 75.0, 6.98, 8192, nil, lastAutovacuum).
+		AddRow("pg_toast", "pg_toast_16384", "t", 140000000, 0, 70.0, 6.52, 0, nil, nil))
+
+	tables, err := client.GetTableFreezeAge(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+	assert.False(t, tables[0].LastVacuum.Valid)
+	assert.Equal(t, NewNullTime(lastAutovacuum), tables[0].LastAutovacuum)
+	assert.Equal(t, "t", tables[1].RelKind)
+	assert.False(t, tables[1].LastAutovacuum.Valid)
+
+	mock.ExpectQuery(SelectDatabaseConnectable).WithArgs("template0").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	_, err = client.GetTableFreezeAge(ctx, "template0", 10)
+	assert.ErrorContains(t, err, "database template0 not found or does not allow connections")
+}
+
+func TestGetMaintenanceProgress(t *testing.T) {
+	ctx := context.Background()
+	progressColumns := []string{"command", "pid", "datname", "relid", "relation", "phase", "blocks_total", "blocks_done",
+		"progress_pct", "elapsed_seconds", "usename", "wait_event_type", "wait_event", "query"}
+
+	tests := []struct {
+		major   int
+		queries []string
+	}{
+		{major: 11, queries: []string{SelectProgressVacuum}},
+		{major: 12, queries: []string{SelectProgressVacuum, SelectProgressCreateIndex, SelectProgressCluster}},
+		{major: 16, queries: []string{SelectProgressVacuum, SelectProgressAnalyze, SelectProgressCreateIndex, SelectProgressCluster}},
+	}
+
+	for _, tt := range tests {
+		client, mock := newMockClient(t, tt.major)
+
+		// Autovacuum без пользователя и запроса в базе клиента: имя таблицы уже известно
+		mock.ExpectQuery(tt.queries[0]).WillReturnRows(sqlmock.NewRows(progressColumns).AddRow(
+			"VACUUM", 101, "postgres", 16384, "public.orders", "scanning heap", 1000, 250, 25.0, 12.5, nil, nil, nil, nil,
+		))
+		for _, query := range tt.queries[1:] {
+			mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(progressColumns))
+		}
+
+		progress, err := client.GetMaintenanceProgress(ctx)
+		require.NoError(t, err, tt.major)
+		require.Len(t, progress, 1, tt.major)
+		assert.Equal(t, NewNullString("public.orders"), progress[0].Relation, tt.major)
+		assert.False(t, progress[0].Username.Valid, tt.major)
+		assert.False(t, progress[0].Query.Valid, tt.major)
+	}
+}
+
+func TestGetMaintenanceProgress_ResolvesRelationsInTheirDatabase(t *testing.T) {
+	ctx := context.Background()
+	client, mock := newMockClient(t, 11)
+
+	crm, crmMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer crm.Close()
+	client.databases["crm"] = &databasePool{db: crm}
+
+	// Таблицы других баз не видны из pg_class базы клиента, поэтому relation NULL
+	mock.ExpectQuery(SelectProgressVacuum).WillReturnRows(sqlmock.NewRows([]string{
+		"command", "pid", "datname", "relid", "relation", "phase", "blocks_total", "blocks_done",
+		"progress_pct", "elapsed_seconds", "usename", "wait_event_type", "wait_event", "query",
+	}).
+		AddRow("VACUUM", 101, "crm", 24576, nil, "vacuuming indexes", 500, 500, 100.0, 3.0, "app", "IO", "DataFileRead", "VACUUM contacts").
+		AddRow("VACUUM", 102, "archive", 32768, nil, "scanning heap", nil, nil, nil, nil, nil, nil, nil, nil))
+	crmMock.ExpectQuery(SelectRelationNames).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"oid", "name"}).AddRow(24576, "public.contacts"))
+	// К archive подключиться нельзя: у операции остаются datname и relid
+	mock.ExpectQuery(SelectDatabaseConnectable).WithArgs("archive").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	progress, err := client.GetMaintenanceProgress(ctx)
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+
+	assert.Equal(t, NewNullString("public.contacts"), progress[0].Relation)
+	assert.Equal(t, NewNullString("DataFileRead"), progress[0].WaitEvent)
+
+	assert.False(t, progress[1].Relation.Valid)
+	assert.Equal(t, int64(32768), progress[1].RelID)
+	assert.False(t, progress[1].BlocksTotal.Valid)
+	assert.False(t, progress[1].ProgressPct.Valid)
+	assert.NoError(t, crmMock.ExpectationsWereMet())
+}
+
+func TestGetTableBloat(t *testing.T) {
+	ctx := context.Background()
+	estimateColumns := []string{"schema", "table", "real_bytes", "wasted_bytes", "bloat_pct", "fillfactor"}
+
+	t.Run("estimate", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectTableBloatEstimate).WithArgs(20).WillReturnRows(sqlmock.NewRows(estimateColumns).
+			AddRow("public", "orders", 81920, 40960, 50.0, 100))
+
+		tables, err := client.GetTableBloat(ctx, "", 20, false)
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		assert.Equal(t, int64(40960), tables[0].WastedBytes)
+		assert.False(t, tables[0].DeadTupleBytes.Valid, "only measured in exact mode")
+		assert.False(t, tables[0].FreeBytes.Valid, "only measured in exact mode")
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectExtensionInstalled).WithArgs("pgstattuple").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(SelectTableBloatExact).WithArgs(20).WillReturnRows(sqlmock.NewRows(append(estimateColumns, "dead_tuple_bytes", "free_bytes")).
+			AddRow("public", "orders", 81920, 36864, 45.0, 90, 4096, 32768))
+
+		tables, err := client.GetTableBloat(ctx, "", 20, true)
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		assert.Equal(t, 90, tables[0].Fillfactor)
+		assert.Equal(t, NewNullInt64(4096), tables[0].DeadTupleBytes)
+		assert.Equal(t, NewNullInt64(32768), tables[0].FreeBytes)
+	})
+
+	t.Run("exact without pgstattuple", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectExtensionInstalled).WithArgs("pgstattuple").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, err := client.GetTableBloat(ctx, "", 20, true)
+		assert.ErrorContains(t, err, "pgstattuple extension is not installed")
+	})
+}
+
+func TestGetIndexBloat(t *testing.T) {
+	ctx := context.Background()
+	estimateColumns := []string{"schema", "table", "index", "real_bytes", "wasted_bytes", "bloat_pct", "fillfactor"}
+
+	t.Run("estimate", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectIndexBloatEstimate).WithArgs(20).WillReturnRows(sqlmock.NewRows(estimateColumns).
+			AddRow("public", "orders", "orders_pkey", 16384, 8192, 50.0, 90))
+
+		indexes, err := client.GetIndexBloat(ctx, "", 20, false)
+		require.NoError(t, err)
+		require.Len(t, indexes, 1)
+		assert.Equal(t, "orders_pkey", indexes[0].IndexName)
+		assert.False(t, indexes[0].AvgLeafDensity.Valid, "only measured in exact mode")
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		client, mock := newMockClient(t, 16)
+
+		mock.ExpectQuery(SelectExtensionInstalled).WithArgs("pgstattuple").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		// У индекса без листовых страниц плотность не определена: NULL
+		mock.ExpectQuery(SelectIndexBloatExact).WithArgs(20).WillReturnRows(sqlmock.NewRows(append(estimateColumns, "avg_leaf_density")).
+			AddRow("public", "orders", "orders_pkey", 16384, 8192, 50.0, 90, 45.5).
+			AddRow("public", "orders", "orders_status_idx", 8192, 0, 0.0, 90, nil))
+
+		indexes, err := client.GetIndexBloat(ctx, "", 20, true)
+		require.NoError(t, err)
+		require.Len(t, indexes, 2)
+		assert.Equal(t, NewNullFloat64(45.5), indexes[0].AvgLeafDensity)
+		assert.False(t, indexes[1].AvgLeafDensity.Valid)
+	})
+}
+
+func TestGetIndexAdvice(t *testing.T) {
+	ctx := context.Background()
+	findingColumns := []string{"schema", "table", "index", "related", "size_bytes", "definition", "reason", "suggestion"}
+	statsReset := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	for major, invalidQuery := range map[int]string{11: SelectInvalidIndexesLegacy, 16: SelectInvalidIndexesV12} {
+		client, mock := newMockClient(t, major)
+
+		mock.ExpectQuery(SelectStatsReset).WillReturnRows(sqlmock.NewRows([]string{"stats_reset"}).AddRow(statsReset))
+		mock.ExpectQuery(invalidQuery).WillReturnRows(sqlmock.NewRows(findingColumns).
+			AddRow("public", "orders", "orders_status_idx", nil, 8192, "CREATE INDEX orders_status_idx ON public.orders (status)",
+				"index build failed", "DROP INDEX CONCURRENTLY public.orders_status_idx;"))
+		mock.ExpectQuery(SelectDuplicateIndexes).WillReturnRows(sqlmock.NewRows(findingColumns))
+		mock.ExpectQuery(SelectRedundantIndexes).WillReturnRows(sqlmock.NewRows(findingColumns).
+			AddRow("public", "orders", "orders_customer_idx", "orders_customer_created_idx", 16384,
+				"CREATE INDEX orders_customer_idx ON public.orders (customer_id)", "prefix of another index", "DROP INDEX CONCURRENTLY public.orders_customer_idx;"))
+		mock.ExpectQuery(SelectUnusedIndexes).WillReturnRows(sqlmock.NewRows(findingColumns))
+		// У внешнего ключа без индекса нет имени индекса, related - имя ограничения
+		mock.ExpectQuery(SelectUnindexedForeignKeys).WillReturnRows(sqlmock.NewRows(findingColumns).
+			AddRow("public", "order_items", nil, "order_items_order_id_fkey", 65536, "FOREIGN KEY (order_id) REFERENCES orders(id)",
+				"no index on the referencing columns", "CREATE INDEX CONCURRENTLY ON public.order_items (order_id);"))
+
+		advice, err := client.GetIndexAdvice(ctx, "")
+		require.NoError(t, err, major)
+
+		assert.Equal(t, NewNullTime(statsReset), advice.StatsReset, major)
+		require.Len(t, advice.Findings, 3, major)
+
+		assert.Equal(t, IndexFindingInvalid, advice.Findings[0].Kind, major)
+		assert.False(t, advice.Findings[0].Related.Valid, major)
+		assert.Equal(t, IndexFindingRedundant, advice.Findings[1].Kind, major)
+		assert.Equal(t, NewNullString("orders_customer_created_idx"), advice.Findings[1].Related, major)
+		assert.Equal(t, IndexFindingUnindexedFK, advice.Findings[2].Kind, major)
+		assert.False(t, advice.Findings[2].IndexName.Valid, major)
+	}
+}
+
+func TestGetIndexAdvice_WithoutStatsReset(t *testing.T) {
+	client, mock := newMockClient(t, 16)
+	findingColumns := []string{"schema", "table", "index", "related", "size_bytes", "definition", "reason", "suggestion"}
+
+	// Статистика базы ни разу не сбрасывалась
+	mock.ExpectQuery(SelectStatsReset).WillReturnRows(sqlmock.NewRows([]string{"stats_reset"}).AddRow(nil))
+	for _, query := range []string{SelectInvalidIndexesV12, SelectDuplicateIndexes, SelectRedundantIndexes, SelectUnusedIndexes, SelectUnindexedForeignKeys} {
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(findingColumns))
+	}
+
+	advice, err := client.GetIndexAdvice(context.Background(), "")
+	require.NoError(t, err)
+	assert.False(t, advice.StatsReset.Valid)
+	assert.NotNil(t, advice.Findings)
+	assert.Empty(t, advice.Findings)
+}
+
+func TestListFunctions(t *testing.T) {
+	functionColumns := []string{"schema", "name", "kind", "arguments", "return_type", "language", "volatility", "comment"}
+
+	for major, query := range map[int]string{10: SelectSchemaFunctionsLegacy, 16: SelectSchemaFunctionsV11} {
+		client, mock := newMockClient(t, major)
+
+		// У процедуры нет возвращаемого типа
+		mock.ExpectQuery(query).WithArgs("billing", 50, 0).WillReturnRows(sqlmock.NewRows(functionColumns).
+			AddRow("billing", "total", "function", "order_id bigint", "numeric", "sql", "stable", "order total").
+			AddRow("billing", "close_month", "procedure", "", nil, "plpgsql", "volatile", nil))
+
+		functions, err := client.ListFunctions(context.Background(), "", "billing", 50, 0)
+		require.NoError(t, err, major)
+		require.Len(t, functions, 2, major)
+		assert.Equal(t, NewNullString("numeric"), functions[0].ReturnType, major)
+		assert.False(t, functions[1].ReturnType.Valid, major)
+		assert.False(t, functions[1].Comment.Valid, major)
+	}
+}
+
+func TestListTables_AttachesColumns(t *testing.T) {
+	client, mock := newMockClient(t, 16)
+
+	mock.ExpectQuery(SelectSchemaTables).WithArgs("", 50, 0).WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "schema", "table", "kind", "estimated_rows", "total_bytes", "comment",
+	}).
+		AddRow(16384, "public", "orders", "table", 1000, 81920, "customer orders").
+		AddRow(16400, "public", "empty", "table", nil, 0, nil))
+	// У таблицы empty нет колонок, а у никогда не анализированной таблицы нет оценки строк
+	mock.ExpectQuery(SelectColumns).WithArgs(sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "name", "type", "nullable", "default", "comment",
+	}).
+		AddRow(16384, "id", "bigint", false, "nextval('orders_id_seq'::regclass)", nil).
+		AddRow(16384, "note", "text", true, nil, "free text"))
+
+	tables, err := client.ListTables(context.Background(), "", "", 50, 0)
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	require.Len(t, tables[0].Columns, 2)
+	assert.Equal(t, NewNullString("nextval('orders_id_seq'::regclass)"), tables[0].Columns[0].Default)
+	assert.False(t, tables[0].Columns[1].Default.Valid)
+	assert.Equal(t, NewNullString("free text"), tables[0].Columns[1].Comment)
+
+	assert.False(t, tables[1].EstimatedRows.Valid)
+	assert.False(t, tables[1].Comment.Valid)
+	assert.NotNil(t, tables[1].Columns, "a table without columns serializes them as []")
+	assert.Empty(t, tables[1].Columns)
+}
+
+func TestDescribeTable(t *testing.T) {
+	ctx := context.Background()
+	client, mock := newMockClient(t, 16)
+
+	mock.ExpectQuery(SelectRelation).WithArgs("public", "order_items").WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "schema", "table", "kind", "estimated_rows", "total_bytes", "comment", "view_definition",
+	}).AddRow(16500, "public", "order_items", "table", 5000, 163840, nil, nil))
+	mock.ExpectQuery(SelectColumns).WithArgs(sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "name", "type", "nullable", "default", "comment",
+	}).AddRow(16500, "order_id", "bigint", false, nil, nil))
+	mock.ExpectQuery(SelectConstraints).WithArgs(sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "name", "type", "columns", "definition",
+	}).
+		AddRow(16500, "order_items_pkey", "primary_key", "{order_id,product_id}", "PRIMARY KEY (order_id, product_id)").
+		AddRow(16500, "order_items_qty_check", "check", "{}", "CHECK (qty > 0)"))
+	mock.ExpectQuery(SelectIndexDefinitions).WithArgs(sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{
+		"oid", "name", "definition", "primary", "unique", "valid", "size_bytes", "constraint",
+	}).
+		AddRow(16500, "order_items_pkey", "CREATE UNIQUE INDEX order_items_pkey ON public.order_items USING btree (order_id, product_id)",
+			true, true, true, 16384, "order_items_pkey").
+		AddRow(16500, "order_items_sku_idx", "CREATE INDEX order_items_sku_idx ON public.order_items USING btree (sku)",
+			false, false, false, 8192, nil))
+
+	detail, err := client.DescribeTable(ctx, "", "public", "order_items")
+	require.NoError(t, err)
+
+	assert.False(t, detail.ViewDefinition.Valid)
+	require.Len(t, detail.Columns, 1)
+	require.Len(t, detail.Constraints, 2)
+	assert.Equal(t, []string{"order_id", "product_id"}, detail.Constraints[0].Columns)
+	assert.Empty(t, detail.Constraints[1].Columns)
+	require.Len(t, detail.Indexes, 2)
+	assert.Equal(t, NewNullString("order_items_pkey"), detail.Indexes[0].Constraint)
+	assert.False(t, detail.Indexes[1].Constraint.Valid)
+	assert.False(t, detail.Indexes[1].Valid)
+
+	mock.ExpectQuery(SelectRelation).WithArgs("public", "missing").WillReturnRows(sqlmock.NewRows([]string{"oid"}))
+
+	_, err = client.DescribeTable(ctx, "", "public", "missing")
+	assert.EqualError(t, err, "table public.missing not found")
+}
+
+func TestGetStatements(t *testing.T) {
+	const extensionQuery = "SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')"
+	statementColumns := []string{"queryid", "userid", "username", "dbid", "database", "query", "calls",
+		"total_exec_time", "mean_exec_time", "stddev_exec_time", "total_plan_time", "mean_plan_time", "rows",
+		"shared_blks_hit", "shared_blks_read", "cache_hit_percent", "temp_blks_read", "temp_blks_written",
+		"blk_read_time", "blk_write_time", "wal_records", "wal_bytes"}
+	infoColumns := []string{"dealloc", "stats_reset", "max", "tracked"}
+	statsReset := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		major     int
+		query     string
+		infoQuery string
+	}{
+		{major: 12, query: SelectStatementsLegacy, infoQuery: SelectStatementsInfoLegacy},
+		{major: 13, query: SelectStatementsV13, infoQuery: SelectStatementsInfoLegacy},
+		{major: 16, query: SelectStatementsV13, infoQuery: SelectStatementsInfoV14},
+		{major: 17, query: SelectStatementsV17, infoQuery: SelectStatementsInfoV14},
+	}
+
+	for _, tt := range tests {
+		client, mock := newMockClient(t, tt.major)
+		hasPlanAndWal := tt.major >= 13
+
+		// Время планирования и WAL появились в PG13, до этого столбцы NULL
+		var planTime, walRecords, walBytes interface{}
+		if hasPlanAndWal {
+			planTime, walRecords, walBytes = 12.5, 40, 8192
+		}
+
+		mock.ExpectQuery(extensionQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		// Роль и база удалены после сбора статистики, у утилитарной команды нет queryid и процента попаданий
+		mock.ExpectQuery(tt.query).WithArgs("shop", "", StatementsOrderTotalTime, 20).WillReturnRows(sqlmock.NewRows(statementColumns).
+			AddRow(int64(-4211), 10, "app", 16384, "shop", "SELECT * FROM orders WHERE id = $1", 500,
+				2500.0, 5.0, 1.5, planTime, planTime, 500, 9000, 1000, 90.0, 0, 0, 0.0, 0.0, walRecords, walBytes).
+			AddRow(nil, 11, nil, 16385, nil, "VACUUM orders", 1, 300.0, 300.0, 0.0, planTime, planTime, 0, 0, 0, nil, 0, 0, 0.0, 0.0,
+				walRecords, walBytes))
+
+		infoRows := sqlmock.NewRows(infoColumns).AddRow(nil, nil, 5000, 2)
+		if tt.infoQuery == SelectStatementsInfoV14 {
+			infoRows = sqlmock.NewRows(infoColumns).AddRow(3, statsReset, 5000, 2)
+		}
+		mock.ExpectQuery(tt.infoQuery).WillReturnRows(infoRows)
+
+		report, err := client.GetStatements(context.Background(), "", "shop", "", 0)
+		require.NoError(t, err, tt.major)
+
+		assert.Equal(t, StatementsOrderTotalTime, report.OrderBy, tt.major)
+		require.Len(t, report.Statements, 2, tt.major)
+
+		assert.Equal(t, NewNullInt64(-4211), report.Statements[0].QueryID, tt.major)
+		assert.Equal(t, NewNullFloat64(90), report.Statements[0].CacheHitPercent, tt.major)
+		assert.Equal(t, hasPlanAndWal, report.Statements[0].TotalPlanTime.Valid, tt.major)
+		assert.Equal(t, hasPlanAndWal, report.Statements[0].WalBytes.Valid, tt.major)
+
+		assert.False(t, report.Statements[1].QueryID.Valid, tt.major)
+		assert.False(t, report.Statements[1].Username.Valid, tt.major)
+		assert.False(t, report.Statements[1].Database.Valid, tt.major)
+		assert.False(t, report.Statements[1].CacheHitPercent.Valid, tt.major)
+
+		assert.Equal(t, NewNullInt64(5000), report.Info.Max, tt.major)
+		assert.Equal(t, int64(2), report.Info.Tracked, tt.major)
+		if tt.infoQuery == SelectStatementsInfoV14 {
+			assert.Equal(t, NewNullInt64(3), report.Info.Dealloc, tt.major)
+			assert.Equal(t, NewNullTime(statsReset), report.Info.StatsReset, tt.major)
+		} else {
+			assert.False(t, report.Info.Dealloc.Valid, tt.major)
+			assert.False(t, report.Info.StatsReset.Valid, tt.major)
+		}
+	}
+}
+
+func TestGetStatements_AllRows(t *testing.T) {
+	client, mock := newMockClient(t, 16)
+
+	// StatementsAll передаётся как LIMIT NULL
+	mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(SelectStatementsV13).WithArgs("", "", StatementsOrderWalBytes, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"queryid"}))
+	mock.ExpectQuery(SelectStatementsInfoV14).
+		WillReturnRows(sqlmock.NewRows([]string{"dealloc", "stats_reset", "max", "tracked"}).AddRow(0, nil, nil, 0))
+
+	report, err := client.GetStatements(context.Background(), StatementsOrderWalBytes, "", "", StatementsAll)
+	require.NoError(t, err)
+	assert.NotNil(t, report.Statements)
+	assert.Empty(t, report.Statements)
+	assert.False(t, report.Info.Max.Valid)
+}
+
+func TestGetStatements_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	client, _ := newMockClient(t, 16)
+	_, err := client.GetStatements(ctx, "io_time", "", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown order "io_time"`)
+
+	client, _ = newMockClient(t, 12)
+	_, err = client.GetStatements(ctx, StatementsOrderWalBytes, "", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires ≥13")
+
+	client, mock := newMockClient(t, 16)
+	mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	_, err = client.GetStatements(ctx, "", "", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pg_stat_statements extension is not installed")
+}
+
+func TestGetIOStats_StatIO(t *testing.T) {
+	client, mock := newMockClient(t, 16)
+	ioColumns := []string{"backend_type", "object", "context", "reads", "read_time", "writes", "write_time",
+		"writebacks", "writeback_time", "extends", "extend_time", "hits", "hit_rate", "evictions", "reuses",
+		"fsyncs", "fsync_time", "stats_reset"}
+	older := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// Фоновый писатель не читает и не расширяет отношения - эти операции NULL;
+	// у строк без stats_reset счётчики ни разу не сбрасывались
+	mock.ExpectQuery(SelectIOStatsV16).WillReturnRows(sqlmock.NewRows(ioColumns).
+		AddRow("client backend", "relation", "normal", 1200, 35.5, 40, 2.5, nil, nil, 30, 1.5, 98000, 98.79, 10, nil, 0, 0.0, older).
+		AddRow("background writer", "relation", "normal", nil, nil, 500, 12.0, 500, 4.0, nil, nil, nil, nil, nil, nil, 20, 8.0, newer).
+		AddRow("autovacuum worker", "relation", "vacuum", 300, 10.0, 0, 0.0, 0, 0.0, 0, 0.0, 700, 70.0, 0, 50, nil, nil, nil))
+
+	report, err := client.GetIOStats(context.Background(), 20)
+	require.NoError(t, err)
+
+	assert.Equal(t, IOSourceStatIO, report.Source)
+	assert.Equal(t, NewNullTime(newer), report.StatsReset)
+	assert.Nil(t, report.Tables)
+	assert.Nil(t, report.Indexes)
+	require.Len(t, report.IO, 3)
+
+	assert.Equal(t, NewNullInt64(1200), report.IO[0].Reads)
+	assert.Equal(t, NewNullFloat64(98.79), report.IO[0].HitRate)
+	assert.False(t, report.IO[0].Reuses.Valid)
+	assert.False(t, report.IO[1].Reads.Valid)
+	assert.False(t, report.IO[1].HitRate.Valid)
+	assert.Equal(t, NewNullInt64(500), report.IO[1].Writebacks)
+	assert.False(t, report.IO[2].Fsyncs.Valid)
+	assert.Equal(t, NewNullInt64(50), report.IO[2].Reuses)
+}
+
+func TestGetIOStats_Relations(t *testing.T) {
+	statsReset := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	tableColumns := []string{"schema", "table", "heap_blks_read", "heap_blks_hit", "heap_hit_rate", "idx_blks_read",
+		"idx_blks_hit", "idx_hit_rate", "toast_blks_read", "toast_blks_hit", "toast_hit_rate"}
+	indexColumns := []string{"schema", "table", "index", "blks_read", "blks_hit", "hit_rate"}
+
+	for _, major := range []int{12, 15} {
+		client, mock := newMockClient(t, major)
+
+		mock.ExpectQuery(SelectDatabaseStatsReset).WillReturnRows(sqlmock.NewRows([]string{"stats_reset"}).AddRow(statsReset))
+		// У таблицы без индексов и TOAST соответствующие столбцы NULL, как и доля попаданий без обращений
+		mock.ExpectQuery(SelectTablesIO).WithArgs(20).WillReturnRows(sqlmock.NewRows(tableColumns).
+			AddRow("public", "orders", 400, 9600, 96.0, 100, 4900, 98.0, 5, 95, 95.0).
+			AddRow("public", "audit_log", 0, 0, nil, nil, nil, nil, nil, nil, nil))
+		mock.ExpectQuery(SelectIndexesIO).WithArgs(20).WillReturnRows(sqlmock.NewRows(indexColumns).
+			AddRow("public", "orders", "orders_pkey", 100, 4900, 98.0).
+			AddRow("public", "orders", "orders_status_idx", 0, 0, nil))
+
+		report, err := client.GetIOStats(context.Background(), 20)
+		require.NoError(t, err, major)
+
+		assert.Equal(t, IOSourceRelations, report.Source, major)
+		assert.Equal(t, NewNullTime(statsReset), report.StatsReset, major)
+		assert.Nil(t, report.IO, major)
+
+		require.Len(t, report.Tables, 2, major)
+		assert.Equal(t, NewNullFloat64(96), report.Tables[0].HeapHitRate, major)
+		assert.Equal(t, NewNullInt64(95), report.Tables[0].ToastBlksHit, major)
+		assert.False(t, report.Tables[1].HeapHitRate.Valid, major)
+		assert.False(t, report.Tables[1].IdxBlksRead.Valid, major)
+		assert.False(t, report.Tables[1].ToastHitRate.Valid, major)
+
+		require.Len(t, report.Indexes, 2, major)
+		assert.Equal(t, NewNullFloat64(98), report.Indexes[0].HitRate, major)
+		assert.False(t, report.Indexes[1].HitRate.Valid, major)
+	}
+}
+
+func TestGetIOStats_RelationsWithoutStatsReset(t *testing.T) {
+	client, mock := newMockClient(t, 15)
+
+	mock.ExpectQuery(SelectDatabaseStatsReset).WillReturnRows(sqlmock.NewRows([]string{"stats_reset"}).AddRow(nil))
+	mock.ExpectQuery(SelectTablesIO).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"schema"}))
+	mock.ExpectQuery(SelectIndexesIO).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"schema"}))
+
+	report, err := client.GetIOStats(context.Background(), 5)
+	require.NoError(t, err)
+	assert.False(t, report.StatsReset.Valid)
+	assert.NotNil(t, report.Tables)
+	assert.Empty(t, report.Tables)
+	assert.NotNil(t, report.Indexes)
+	assert.Empty(t, report.Indexes)
+}
@@ -64,6 +64,80 @@ SELECT
   checkpoint_write_time,
   checkpoint_sync_time
 FROM pg_stat_checkpointer;
+`
+
+	// SelectIOStatsV16 - статистика ввода-вывода для PG ≥16 (pg_stat_io)
+	// Строка на backend_type × object × context; пустые сочетания отброшены.
+	// *_time будут 0 если track_io_timing=off
+	SelectIOStatsV16 = `
+SELECT
+  backend_type,
+  object,
+  context,
+  reads,
+  read_time,
+  writes,
+  write_time,
+  writebacks,
+  writeback_time,
+  extends,
+  extend_time,
+  hits,
+  hits::float / NULLIF(COALESCE(hits, 0) + COALESCE(reads, 0), 0) AS hit_rate,
+  evictions,
+  reuses,
+  fsyncs,
+  fsync_time,
+  stats_reset
+FROM pg_stat_io
+WHERE COALESCE(reads, 0) + COALESCE(writes, 0) + COALESCE(writebacks, 0) + COALESCE(extends, 0)
+    + COALESCE(hits, 0) + COALESCE(evictions, 0) + COALESCE(reuses, 0) + COALESCE(fsyncs, 0) > 0
+ORDER BY COALESCE(reads, 0) + COALESCE(writes, 0) + COALESCE(extends, 0) DESC,
+  backend_type, object, context;
+`
+
+	// SelectTablesIO - блоки heap/index/toast по таблицам для PG <16 (pg_statio_user_tables)
+	// idx_* NULL у таблиц без индексов, toast_* NULL у таблиц без TOAST
+	// $1 - лимит
+	SelectTablesIO = `
+SELECT
+  schemaname,
+  relname,
+  heap_blks_read,
+  heap_blks_hit,
+  heap_blks_hit::float / NULLIF(heap_blks_hit + heap_blks_read, 0) AS heap_hit_rate,
+  idx_blks_read,
+  idx_blks_hit,
+  idx_blks_hit::float / NULLIF(idx_blks_hit + idx_blks_read, 0) AS idx_hit_rate,
+  toast_blks_read,
+  toast_blks_hit,
+  toast_blks_hit::float / NULLIF(toast_blks_hit + toast_blks_read, 0) AS toast_hit_rate
+FROM pg_statio_user_tables
+ORDER BY heap_blks_read + COALESCE(idx_blks_read, 0) + COALESCE(toast_blks_read, 0) DESC,
+  schemaname, relname
+LIMIT $1;
+`
+
+	// SelectDatabaseStatsReset - время сброса статистики текущей БД (к нему относятся pg_statio_*)
+	SelectDatabaseStatsReset = `
+SELECT stats_reset
+FROM pg_stat_database
+WHERE datname = current_database();
+`
+
+	// SelectIndexesIO - блоки по индексам для PG <16 (pg_statio_user_indexes)
+	// $1 - лимит
+	SelectIndexesIO = `
+SELECT
+  schemaname,
+  relname,
+  indexrelname,
+  idx_blks_read,
+  idx_blks_hit,
+  idx_blks_hit::float / NULLIF(idx_blks_hit + idx_blks_read, 0) AS hit_rate
+FROM pg_statio_user_indexes
+ORDER BY idx_blks_read DESC, schemaname, indexrelname
+LIMIT $1;
 `
 
 	// SelectWalActivity - статистика WAL для PG ≥14
@@ -136,6 +210,18 @@ SELECT
 FROM pg_settings
 WHERE source <> 'default'
 ORDER BY name;
+`
+
+	// SelectAllSettings - все настройки, для сравнения конфигурации между инстансами
+	SelectAllSettings = `
+SELECT
+  name,
+  setting,
+  unit,
+  source,
+  pending_restart
+FROM pg_settings
+ORDER BY name;
 `
 
 	// SelectIndexStats - статистика использования индексов
@@ -164,6 +250,7 @@ SELECT
   usename,
   datname,
   state,
+  query_start,
   EXTRACT(EPOCH FROM (now() - query_start)) AS duration_seconds,
   wait_event_type,
   wait_event,
@@ -176,6 +263,56 @@ WHERE datname = $1
 ORDER BY query_start;
 `
 
+	// SelectActivitySampleV14 - один снимок активных сессий для профиля ожиданий (PG ≥14, есть query_id)
+	// Собственная сессия исключается, чтобы сэмплер не попадал в свой профиль
+	SelectActivitySampleV14 = `
+SELECT
+  pid,
+  usename,
+  datname,
+  backend_type,
+  wait_event_type,
+  wait_event,
+  NULLIF(query_id, 0),
+  query
+FROM pg_stat_activity
+WHERE state = 'active'
+  AND pid <> pg_backend_pid();
+`
+
+	// SelectActivitySampleV10 - то же для PG 10–13, без query_id
+	SelectActivitySampleV10 = `
+SELECT
+  pid,
+  usename,
+  datname,
+  backend_type,
+  wait_event_type,
+  wait_event,
+  NULL::bigint,
+  query
+FROM pg_stat_activity
+WHERE state = 'active'
+  AND pid <> pg_backend_pid();
+`
+
+	// SelectActivitySampleLegacy - то же для PG 9.6: ни query_id, ни backend_type
+	// (в pg_stat_activity только клиентские процессы)
+	SelectActivitySampleLegacy = `
+SELECT
+  pid,
+  usename,
+  datname,
+  NULL::text AS backend_type,
+  wait_event_type,
+  wait_event,
+  NULL::bigint,
+  query
+FROM pg_stat_activity
+WHERE state = 'active'
+  AND pid <> pg_backend_pid();
+`
+
 	// SelectConnectionStats - статистика соединений с группировкой по состояниям
 	// Помогает оценить нагрузку на connection pool
 	SelectConnectionStats = `
@@ -206,6 +343,110 @@ ORDER BY total_exec_time DESC
 LIMIT COALESCE($1, 20);
 `
 
+	// statementsColumnsV17 - колонки pg_stat_statements для PG ≥17 (blk_*_time переименованы в shared_blk_*_time)
+	statementsColumnsV17 = `
+SELECT
+  queryid, userid, dbid, query, calls,
+  total_exec_time, mean_exec_time, stddev_exec_time, total_plan_time, mean_plan_time,
+  rows, shared_blks_hit, shared_blks_read, temp_blks_read, temp_blks_written,
+  shared_blk_read_time AS blk_read_time, shared_blk_write_time AS blk_write_time,
+  wal_records, wal_bytes
+FROM pg_stat_statements`
+
+	// statementsColumnsV13 - колонки pg_stat_statements для PG 13–16 (появились *_exec_time, *_plan_time и wal_*)
+	statementsColumnsV13 = `
+SELECT
+  queryid, userid, dbid, query, calls,
+  total_exec_time, mean_exec_time, stddev_exec_time, total_plan_time, mean_plan_time,
+  rows, shared_blks_hit, shared_blks_read, temp_blks_read, temp_blks_written,
+  blk_read_time, blk_write_time,
+  wal_records, wal_bytes
+FROM pg_stat_statements`
+
+	// statementsColumnsLegacy - колонки pg_stat_statements до PG13 (total_time вместо total_exec_time, без планирования и WAL)
+	statementsColumnsLegacy = `
+SELECT
+  queryid, userid, dbid, query, calls,
+  total_time AS total_exec_time, mean_time AS mean_exec_time, stddev_time AS stddev_exec_time,
+  NULL::float8 AS total_plan_time, NULL::float8 AS mean_plan_time,
+  rows, shared_blks_hit, shared_blks_read, temp_blks_read, temp_blks_written,
+  blk_read_time, blk_write_time,
+  NULL::bigint AS wal_records, NULL::numeric AS wal_bytes
+FROM pg_stat_statements`
+
+	// selectStatementsTop - общая часть запроса топа statements поверх колонок нужной версии
+	// $1 - база ('' - все), $2 - пользователь ('' - все), $3 - измерение сортировки, $4 - лимит
+	selectStatementsTop = `
+SELECT
+  s.queryid,
+  s.userid::bigint,
+  r.rolname,
+  s.dbid::bigint,
+  d.datname,
+  s.query,
+  s.calls,
+  s.total_exec_time,
+  s.mean_exec_time,
+  s.stddev_exec_time,
+  s.total_plan_time,
+  s.mean_plan_time,
+  s.rows,
+  s.shared_blks_hit,
+  s.shared_blks_read,
+  100.0 * s.shared_blks_hit / NULLIF(s.shared_blks_hit + s.shared_blks_read, 0) AS cache_hit_percent,
+  s.temp_blks_read,
+  s.temp_blks_written,
+  s.blk_read_time,
+  s.blk_write_time,
+  s.wal_records,
+  s.wal_bytes::bigint
+FROM s
+LEFT JOIN pg_roles r ON r.oid = s.userid
+LEFT JOIN pg_database d ON d.oid = s.dbid
+WHERE ($1 = '' OR d.datname = $1)
+  AND ($2 = '' OR r.rolname = $2)
+ORDER BY
+  CASE $3
+    WHEN 'total_time' THEN s.total_exec_time
+    WHEN 'mean_time' THEN s.mean_exec_time
+    WHEN 'calls' THEN s.calls::float8
+    WHEN 'rows' THEN s.rows::float8
+    WHEN 'shared_reads' THEN s.shared_blks_read::float8
+    WHEN 'temp_blocks' THEN (s.temp_blks_read + s.temp_blks_written)::float8
+    WHEN 'wal_bytes' THEN s.wal_bytes::float8
+  END DESC NULLS LAST
+LIMIT $4;
+`
+
+	// SelectStatementsV17 - топ statements для PG ≥17
+	SelectStatementsV17 = "WITH s AS (" + statementsColumnsV17 + ")" + selectStatementsTop
+
+	// SelectStatementsV13 - топ statements для PG 13–16
+	SelectStatementsV13 = "WITH s AS (" + statementsColumnsV13 + ")" + selectStatementsTop
+
+	// SelectStatementsLegacy - топ statements до PG13
+	SelectStatementsLegacy = "WITH s AS (" + statementsColumnsLegacy + ")" + selectStatementsTop
+
+	// SelectStatementsInfoV14 - вытеснения и сброс статистики из pg_stat_statements_info (PG ≥14)
+	// Вместе с pg_stat_statements.max и числом отслеживаемых statements показывает, хватает ли места
+	SelectStatementsInfoV14 = `
+SELECT
+  i.dealloc,
+  i.stats_reset,
+  current_setting('pg_stat_statements.max', true)::bigint,
+  (SELECT count(*) FROM pg_stat_statements)
+FROM pg_stat_statements_info i;
+`
+
+	// SelectStatementsInfoLegacy - то же до PG14, где pg_stat_statements_info ещё нет
+	SelectStatementsInfoLegacy = `
+SELECT
+  NULL::bigint,
+  NULL::timestamptz,
+  current_setting('pg_stat_statements.max', true)::bigint,
+  (SELECT count(*) FROM pg_stat_statements);
+`
+
 	// SelectDatabaseSizes - размеры всех баз данных
 	// Помогает мониторить рост данных
 	SelectDatabaseSizes = `
@@ -215,5 +456,1254 @@ SELECT
 FROM pg_database
 WHERE datistemplate = false
 ORDER BY pg_database_size(datname) DESC;
+`
+
+	// SelectIsInRecovery - роль инстанса: true на standby, false на primary
+	SelectIsInRecovery = `SELECT pg_is_in_recovery();`
+
+	// SelectReplicationV12 - реплики primary из pg_stat_replication для PG ≥12 (есть reply_time)
+	// Отставание в байтах считается от текущей позиции WAL на primary
+	SelectReplicationV12 = `
+SELECT
+  pid,
+  usename,
+  application_name,
+  client_addr::text,
+  state,
+  sync_state,
+  sync_priority,
+  sent_lsn::text,
+  write_lsn::text,
+  flush_lsn::text,
+  replay_lsn::text,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), sent_lsn)::bigint AS sent_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), write_lsn)::bigint AS write_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), flush_lsn)::bigint AS flush_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)::bigint AS replay_lag_bytes,
+  EXTRACT(EPOCH FROM write_lag) AS write_lag_seconds,
+  EXTRACT(EPOCH FROM flush_lag) AS flush_lag_seconds,
+  EXTRACT(EPOCH FROM replay_lag) AS replay_lag_seconds,
+  backend_start,
+  reply_time
+FROM pg_stat_replication
+ORDER BY application_name, pid;
+`
+
+	// SelectReplicationLegacy - реплики primary для PG 10–11 (reply_time ещё нет)
+	SelectReplicationLegacy = `
+SELECT
+  pid,
+  usename,
+  application_name,
+  client_addr::text,
+  state,
+  sync_state,
+  sync_priority,
+  sent_lsn::text,
+  write_lsn::text,
+  flush_lsn::text,
+  replay_lsn::text,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), sent_lsn)::bigint AS sent_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), write_lsn)::bigint AS write_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), flush_lsn)::bigint AS flush_lag_bytes,
+  pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)::bigint AS replay_lag_bytes,
+  EXTRACT(EPOCH FROM write_lag) AS write_lag_seconds,
+  EXTRACT(EPOCH FROM flush_lag) AS flush_lag_seconds,
+  EXTRACT(EPOCH FROM replay_lag) AS replay_lag_seconds,
+  backend_start,
+  NULL::timestamptz AS reply_time
+FROM pg_stat_replication
+ORDER BY application_name, pid;
+`
+
+	// SelectWalReceiverV13 - состояние WAL receiver на standby для PG ≥13
+	// (received_lsn переименован в flushed_lsn, добавлен written_lsn; sender_host и sender_port - с PG11)
+	SelectWalReceiverV13 = `
+SELECT
+  pid,
+  status,
+  receive_start_lsn::text,
+  written_lsn::text,
+  flushed_lsn::text,
+  received_tli,
+  latest_end_lsn::text,
+  latest_end_time,
+  last_msg_receipt_time,
+  slot_name,
+  sender_host,
+  sender_port,
+  conninfo
+FROM pg_stat_wal_receiver;
+`
+
+	// SelectWalReceiverV11 - состояние WAL receiver для PG 11–12 (written_lsn ещё нет)
+	SelectWalReceiverV11 = `
+SELECT
+  pid,
+  status,
+  receive_start_lsn::text,
+  NULL::text AS written_lsn,
+  received_lsn::text AS flushed_lsn,
+  received_tli,
+  latest_end_lsn::text,
+  latest_end_time,
+  last_msg_receipt_time,
+  slot_name,
+  sender_host,
+  sender_port,
+  conninfo
+FROM pg_stat_wal_receiver;
+`
+
+	// SelectWalReceiverLegacy - состояние WAL receiver для PG 10
+	// Адрес primary в этой версии берётся из conninfo
+	SelectWalReceiverLegacy = `
+SELECT
+  pid,
+  status,
+  receive_start_lsn::text,
+  NULL::text AS written_lsn,
+  received_lsn::text AS flushed_lsn,
+  received_tli,
+  latest_end_lsn::text,
+  latest_end_time,
+  last_msg_receipt_time,
+  slot_name,
+  NULL::text AS sender_host,
+  NULL::int AS sender_port,
+  conninfo
+FROM pg_stat_wal_receiver;
+`
+
+	// SelectStandbyReplay - прогресс применения WAL на standby (PG ≥10)
+	// Если всё полученное уже применено, задержка 0: иначе при простое primary она бы росла бесконечно
+	SelectStandbyReplay = `
+SELECT
+  pg_last_wal_receive_lsn()::text,
+  pg_last_wal_replay_lsn()::text,
+  pg_wal_lsn_diff(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn())::bigint AS replay_lag_bytes,
+  pg_last_xact_replay_timestamp(),
+  CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+       ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END AS replay_delay_seconds,
+  pg_is_wal_replay_paused();
+`
+
+	// SelectReplicationSlotsV13 - слоты репликации для PG ≥13 (есть wal_status и safe_wal_size)
+	// Удерживаемый WAL считается от текущей позиции: на standby - от последнего полученного LSN
+	// $1 - порог удерживаемого WAL в байтах для неактивных слотов
+	SelectReplicationSlotsV13 = `
+WITH pos AS (
+  SELECT CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+              ELSE pg_current_wal_lsn() END AS lsn
+)
+SELECT
+  s.slot_name,
+  s.plugin,
+  s.slot_type,
+  s.database,
+  s.temporary,
+  s.active,
+  s.active_pid,
+  s.xmin::text,
+  s.catalog_xmin::text,
+  s.restart_lsn::text,
+  s.confirmed_flush_lsn::text,
+  pg_wal_lsn_diff(pos.lsn, s.restart_lsn)::bigint AS retained_wal_bytes,
+  s.wal_status,
+  s.safe_wal_size,
+  (NOT s.active AND pg_wal_lsn_diff(pos.lsn, s.restart_lsn) > $1)
+    OR s.wal_status IN ('unreserved', 'lost') AS at_risk
+FROM pg_replication_slots s, pos
+ORDER BY retained_wal_bytes DESC NULLS LAST;
+`
+
+	// SelectReplicationSlotsLegacy - слоты репликации для PG 10–12 (wal_status и safe_wal_size ещё нет)
+	SelectReplicationSlotsLegacy = `
+WITH pos AS (
+  SELECT CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn()
+              ELSE pg_current_wal_lsn() END AS lsn
+)
+SELECT
+  s.slot_name,
+  s.plugin,
+  s.slot_type,
+  s.database,
+  s.temporary,
+  s.active,
+  s.active_pid,
+  s.xmin::text,
+  s.catalog_xmin::text,
+  s.restart_lsn::text,
+  s.confirmed_flush_lsn::text,
+  pg_wal_lsn_diff(pos.lsn, s.restart_lsn)::bigint AS retained_wal_bytes,
+  NULL::text AS wal_status,
+  NULL::bigint AS safe_wal_size,
+  NOT s.active AND pg_wal_lsn_diff(pos.lsn, s.restart_lsn) > $1 AS at_risk
+FROM pg_replication_slots s, pos
+ORDER BY retained_wal_bytes DESC NULLS LAST;
+`
+
+	// SelectDatabaseConnectable - существует ли база и разрешены ли к ней подключения
+	SelectDatabaseConnectable = `
+SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1 AND datallowconn);
+`
+
+	// SelectFreezeSettings - пороги принудительного anti-wraparound autovacuum
+	SelectFreezeSettings = `
+SELECT
+  current_setting('autovacuum_freeze_max_age')::bigint,
+  current_setting('autovacuum_multixact_freeze_max_age')::bigint;
+`
+
+	// SelectDatabaseXidAge - возраст datfrozenxid и datminmxid по всем базам
+	// Проценты считаются к autovacuum_*_freeze_max_age и к пределу wraparound (2^31)
+	SelectDatabaseXidAge = `
+SELECT
+  datname,
+  age(datfrozenxid) AS xid_age,
+  mxid_age(datminmxid) AS mxid_age,
+  round(100.0 * age(datfrozenxid) / current_setting('autovacuum_freeze_max_age')::bigint, 2) AS xid_freeze_max_age_pct,
+  round(100.0 * age(datfrozenxid) / 2147483648, 2) AS xid_wraparound_pct,
+  round(100.0 * mxid_age(datminmxid) / current_setting('autovacuum_multixact_freeze_max_age')::bigint, 2) AS mxid_freeze_max_age_pct,
+  round(100.0 * mxid_age(datminmxid) / 2147483648, 2) AS mxid_wraparound_pct
+FROM pg_database
+ORDER BY age(datfrozenxid) DESC;
+`
+
+	// SelectXminHorizonHolders - что удерживает xmin horizon: бэкенды (включая walsender с hot_standby_feedback),
+	// подготовленные транзакции и слоты репликации. Пока они держат horizon, VACUUM не может заморозить строки
+	// $1 - лимит
+	SelectXminHorizonHolders = `
+SELECT * FROM (
+  SELECT
+    'backend' AS kind,
+    pid::text AS holder,
+    usename::text AS username,
+    datname::text AS database,
+    greatest(age(backend_xmin), age(backend_xid)) AS xmin_age,
+    state,
+    xact_start AS since,
+    query
+  FROM pg_stat_activity
+  WHERE (backend_xmin IS NOT NULL OR backend_xid IS NOT NULL)
+    AND pid <> pg_backend_pid()
+  UNION ALL
+  SELECT
+    'prepared_transaction',
+    gid,
+    owner::text,
+    database::text,
+    age(transaction),
+    NULL,
+    prepared,
+    NULL
+  FROM pg_prepared_xacts
+  UNION ALL
+  SELECT
+    'replication_slot',
+    slot_name::text,
+    NULL,
+    database::text,
+    greatest(age(xmin), age(catalog_xmin)),
+    CASE WHEN active THEN 'active' ELSE 'inactive' END,
+    NULL,
+    NULL
+  FROM pg_replication_slots
+  WHERE xmin IS NOT NULL OR catalog_xmin IS NOT NULL
+) holders
+ORDER BY xmin_age DESC NULLS LAST
+LIMIT $1;
+`
+
+	// SelectTableFreezeAge - таблицы текущей БД с самым старым relfrozenxid (включая TOAST и матпредставления)
+	// $1 - лимит
+	SelectTableFreezeAge = `
+SELECT
+  n.nspname,
+  c.relname,
+  c.relkind::text,
+  age(c.relfrozenxid) AS xid_age,
+  mxid_age(c.relminmxid) AS mxid_age,
+  round(100.0 * age(c.relfrozenxid) / current_setting('autovacuum_freeze_max_age')::bigint, 2) AS xid_freeze_max_age_pct,
+  round(100.0 * age(c.relfrozenxid) / 2147483648, 2) AS xid_wraparound_pct,
+  pg_total_relation_size(c.oid) AS total_bytes,
+  s.last_vacuum,
+  s.last_autovacuum
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_all_tables s ON s.relid = c.oid
+WHERE c.relkind IN ('r', 'm', 't')
+ORDER BY age(c.relfrozenxid) DESC
+LIMIT COALESCE($1, 20);
+`
+
+	// Прогресс обслуживающих операций. Все запросы возвращают одинаковые колонки
+	// Имя таблицы разрешается через regclass только для текущей БД, для остальных
+	// его дочитывает SelectRelationNames в пуле нужной базы
+
+	// SelectRelationNames - имена отношений по OID в текущей БД
+	// $1 - массив OID
+	SelectRelationNames = `
+SELECT oid::bigint, oid::regclass::text
+FROM pg_class
+WHERE oid = ANY($1::oid[]);
+`
+
+	// SelectProgressVacuum - ход VACUUM и autovacuum (pg_stat_progress_vacuum)
+	SelectProgressVacuum = `
+SELECT
+  'VACUUM' AS command,
+  p.pid,
+  p.datname,
+  p.relid::bigint,
+  CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+  p.phase,
+  p.heap_blks_total,
+  p.heap_blks_scanned,
+  round(100.0 * p.heap_blks_scanned / NULLIF(p.heap_blks_total, 0), 2) AS progress_pct,
+  EXTRACT(EPOCH FROM now() - a.query_start) AS elapsed_seconds,
+  a.usename,
+  a.wait_event_type,
+  a.wait_event,
+  a.query
+FROM pg_stat_progress_vacuum p
+LEFT JOIN pg_stat_activity a ON a.pid = p.pid;
+`
+
+	// SelectProgressAnalyze - ход ANALYZE для PG ≥13 (pg_stat_progress_analyze)
+	SelectProgressAnalyze = `
+SELECT
+  'ANALYZE' AS command,
+  p.pid,
+  p.datname,
+  p.relid::bigint,
+  CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+  p.phase,
+  p.sample_blks_total,
+  p.sample_blks_scanned,
+  round(100.0 * p.sample_blks_scanned / NULLIF(p.sample_blks_total, 0), 2) AS progress_pct,
+  EXTRACT(EPOCH FROM now() - a.query_start) AS elapsed_seconds,
+  a.usename,
+  a.wait_event_type,
+  a.wait_event,
+  a.query
+FROM pg_stat_progress_analyze p
+LEFT JOIN pg_stat_activity a ON a.pid = p.pid;
+`
+
+	// SelectProgressCreateIndex - ход CREATE INDEX и REINDEX для PG ≥12 (pg_stat_progress_create_index)
+	SelectProgressCreateIndex = `
+SELECT
+  p.command,
+  p.pid,
+  p.datname,
+  p.relid::bigint,
+  CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+  p.phase,
+  p.blocks_total,
+  p.blocks_done,
+  round(100.0 * p.blocks_done / NULLIF(p.blocks_total, 0), 2) AS progress_pct,
+  EXTRACT(EPOCH FROM now() - a.query_start) AS elapsed_seconds,
+  a.usename,
+  a.wait_event_type,
+  a.wait_event,
+  a.query
+FROM pg_stat_progress_create_index p
+LEFT JOIN pg_stat_activity a ON a.pid = p.pid;
+`
+
+	// SelectProgressCluster - ход CLUSTER и VACUUM FULL для PG ≥12 (pg_stat_progress_cluster)
+	SelectProgressCluster = `
+SELECT
+  p.command,
+  p.pid,
+  p.datname,
+  p.relid::bigint,
+  CASE WHEN p.datname = current_database() THEN p.relid::regclass::text END AS relation,
+  p.phase,
+  p.heap_blks_total,
+  p.heap_blks_scanned,
+  round(100.0 * p.heap_blks_scanned / NULLIF(p.heap_blks_total, 0), 2) AS progress_pct,
+  EXTRACT(EPOCH FROM now() - a.query_start) AS elapsed_seconds,
+  a.usename,
+  a.wait_event_type,
+  a.wait_event,
+  a.query
+FROM pg_stat_progress_cluster p
+LEFT JOIN pg_stat_activity a ON a.pid = p.pid;
+`
+
+	// SelectTableBloatEstimate - оценка bloat таблиц по pg_stats и статистике каталога
+	// Классический запрос оценки (ioguix/pgsql-bloat-estimation): ожидаемое число страниц
+	// считается по средней ширине строки с учётом fillfactor и сравнивается с relpages.
+	// Таблицы без статистики (не было ANALYZE) и с колонками типа name пропускаются - для них оценка неверна
+	// $1 - лимит
+	SelectTableBloatEstimate = `
+SELECT
+  schemaname,
+  tblname,
+  (bs * tblpages)::bigint AS real_bytes,
+  CASE WHEN tblpages - est_tblpages_ff > 0 THEN ((tblpages - est_tblpages_ff) * bs)::bigint ELSE 0 END AS wasted_bytes,
+  CASE WHEN tblpages > 0 AND tblpages - est_tblpages_ff > 0
+       THEN round((100 * (tblpages - est_tblpages_ff) / tblpages)::numeric, 2) ELSE 0 END AS bloat_pct,
+  fillfactor
+FROM (
+  SELECT
+    ceil(reltuples / ((bs - page_hdr) * fillfactor / (tpl_size * 100))) + ceil(toasttuples / 4) AS est_tblpages_ff,
+    tblpages, fillfactor, bs, schemaname, tblname, is_na
+  FROM (
+    SELECT
+      (4 + tpl_hdr_size + tpl_data_size + (2 * ma)
+        - CASE WHEN tpl_hdr_size % ma = 0 THEN ma ELSE tpl_hdr_size % ma END
+        - CASE WHEN ceil(tpl_data_size)::int % ma = 0 THEN ma ELSE ceil(tpl_data_size)::int % ma END
+      ) AS tpl_size,
+      (heappages + toastpages) AS tblpages,
+      reltuples, toasttuples, bs, page_hdr, schemaname, tblname, fillfactor, is_na
+    FROM (
+      SELECT
+        tbl.oid AS tblid,
+        ns.nspname AS schemaname,
+        tbl.relname AS tblname,
+        tbl.reltuples,
+        tbl.relpages AS heappages,
+        coalesce(toast.relpages, 0) AS toastpages,
+        coalesce(toast.reltuples, 0) AS toasttuples,
+        coalesce(substring(array_to_string(tbl.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::smallint, 100) AS fillfactor,
+        current_setting('block_size')::numeric AS bs,
+        CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS ma,
+        24 AS page_hdr,
+        23 + CASE WHEN max(coalesce(s.null_frac, 0)) > 0 THEN (7 + count(s.attname)) / 8 ELSE 0::int END
+           + CASE WHEN bool_or(att.attname = 'oid' AND att.attnum < 0) THEN 4 ELSE 0 END AS tpl_hdr_size,
+        sum((1 - coalesce(s.null_frac, 0)) * coalesce(s.avg_width, 0)) AS tpl_data_size,
+        bool_or(att.atttypid = 'pg_catalog.name'::regtype)
+          OR sum(CASE WHEN att.attnum > 0 THEN 1 ELSE 0 END) <> count(s.attname) AS is_na
+      FROM pg_attribute AS att
+      JOIN pg_class AS tbl ON att.attrelid = tbl.oid
+      JOIN pg_namespace AS ns ON ns.oid = tbl.relnamespace
+      LEFT JOIN pg_stats AS s ON s.schemaname = ns.nspname
+        AND s.tablename = tbl.relname AND s.inherited = false AND s.attname = att.attname
+      LEFT JOIN pg_class AS toast ON tbl.reltoastrelid = toast.oid
+      WHERE NOT att.attisdropped
+        AND tbl.relkind IN ('r', 'm')
+        AND tbl.reltuples >= 0
+        AND ns.nspname NOT IN ('pg_catalog', 'information_schema')
+      GROUP BY 1, 2, 3, 4, 5, 6, 7, 8, 9, 10
+    ) AS s
+  ) AS s2
+) AS s3
+WHERE NOT is_na
+ORDER BY wasted_bytes DESC
+LIMIT COALESCE($1, 50);
+`
+
+	// SelectIndexBloatEstimate - оценка bloat btree-индексов по pg_stats (ioguix/pgsql-bloat-estimation)
+	// Ожидаемое число страниц считается по средней ширине ключа с учётом fillfactor (по умолчанию 90)
+	// $1 - лимит
+	SelectIndexBloatEstimate = `
+SELECT
+  nspname,
+  tblname,
+  idxname,
+  (bs * relpages)::bigint AS real_bytes,
+  CASE WHEN relpages > est_pages_ff THEN (bs * (relpages - est_pages_ff))::bigint ELSE 0 END AS wasted_bytes,
+  CASE WHEN relpages > est_pages_ff
+       THEN round((100 * (relpages - est_pages_ff)::float / relpages)::numeric, 2) ELSE 0 END AS bloat_pct,
+  fillfactor
+FROM (
+  SELECT
+    coalesce(1 + ceil(reltuples / floor((bs - pageopqdata - pagehdr) * fillfactor / (100 * (4 + nulldatahdrwidth)::float))), 0) AS est_pages_ff,
+    bs, nspname, tblname, idxname, relpages, fillfactor, is_na
+  FROM (
+    SELECT
+      maxalign, bs, nspname, tblname, idxname, reltuples, relpages, fillfactor,
+      (index_tuple_hdr_bm
+        + maxalign - CASE WHEN index_tuple_hdr_bm % maxalign = 0 THEN maxalign ELSE index_tuple_hdr_bm % maxalign END
+        + nulldatawidth + maxalign - CASE
+            WHEN nulldatawidth = 0 THEN 0
+            WHEN nulldatawidth::integer % maxalign = 0 THEN maxalign
+            ELSE nulldatawidth::integer % maxalign
+          END
+      )::numeric AS nulldatahdrwidth,
+      pagehdr, pageopqdata, is_na
+    FROM (
+      SELECT
+        n.nspname, i.tblname, i.idxname, i.reltuples, i.relpages, i.idxoid, i.fillfactor,
+        current_setting('block_size')::numeric AS bs,
+        CASE WHEN version() ~ 'mingw32' OR version() ~ '64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS maxalign,
+        24 AS pagehdr,
+        16 AS pageopqdata,
+        CASE WHEN max(coalesce(s.null_frac, 0)) = 0 THEN 8 ELSE 8 + ((32 + 8 - 1) / 8) END AS index_tuple_hdr_bm,
+        sum((1 - coalesce(s.null_frac, 0)) * coalesce(s.avg_width, 1024)) AS nulldatawidth,
+        max(CASE WHEN i.atttypid = 'pg_catalog.name'::regtype THEN 1 ELSE 0 END) > 0 AS is_na
+      FROM (
+        SELECT
+          ct.relname AS tblname, ct.relnamespace, ic.idxname, ic.attpos, ic.indkey, ic.reltuples, ic.relpages,
+          ic.tbloid, ic.idxoid, ic.fillfactor,
+          coalesce(a1.attnum, a2.attnum) AS attnum,
+          coalesce(a1.attname, a2.attname) AS attname,
+          coalesce(a1.atttypid, a2.atttypid) AS atttypid,
+          CASE WHEN a1.attnum IS NULL THEN ic.idxname ELSE ct.relname END AS attrelname
+        FROM (
+          SELECT
+            idxname, reltuples, relpages, tbloid, idxoid, fillfactor, indkey,
+            generate_series(1, indnatts) AS attpos
+          FROM (
+            SELECT
+              ci.relname AS idxname, ci.reltuples, ci.relpages, i.indrelid AS tbloid, i.indexrelid AS idxoid,
+              coalesce(substring(array_to_string(ci.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::smallint, 90) AS fillfactor,
+              i.indnatts,
+              string_to_array(textin(int2vectorout(i.indkey)), ' ')::int[] AS indkey
+            FROM pg_index i
+            JOIN pg_class ci ON ci.oid = i.indexrelid
+            WHERE ci.relam = (SELECT oid FROM pg_am WHERE amname = 'btree')
+              AND ci.relpages > 0
+              AND ci.reltuples >= 0
+          ) AS idx_data
+        ) AS ic
+        JOIN pg_class ct ON ct.oid = ic.tbloid
+        LEFT JOIN pg_attribute a1 ON ic.indkey[ic.attpos] <> 0
+          AND a1.attrelid = ic.tbloid AND a1.attnum = ic.indkey[ic.attpos]
+        LEFT JOIN pg_attribute a2 ON ic.indkey[ic.attpos] = 0
+          AND a2.attrelid = ic.idxoid AND a2.attnum = ic.attpos
+      ) i
+      JOIN pg_namespace n ON n.oid = i.relnamespace
+      JOIN pg_stats s ON s.schemaname = n.nspname AND s.tablename = i.attrelname AND s.attname = i.attname
+      WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+      GROUP BY 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11
+    ) AS rows_data_stats
+  ) AS rows_hdr_pdg_stats
+) AS relation_stats
+WHERE NOT is_na
+ORDER BY wasted_bytes DESC
+LIMIT COALESCE($1, 50);
+`
+
+	// SelectTableBloatExact - точный bloat через pgstattuple (полное чтение таблицы)
+	// Читаются только $1 самых больших таблиц; потерянным считается место мёртвых строк
+	// и свободное место сверх того, что резервирует fillfactor
+	SelectTableBloatExact = `
+SELECT
+  schemaname,
+  tblname,
+  real_bytes,
+  wasted_bytes,
+  CASE WHEN real_bytes > 0 THEN round(100.0 * wasted_bytes / real_bytes, 2) ELSE 0 END AS bloat_pct,
+  fillfactor,
+  dead_tuple_bytes,
+  free_bytes
+FROM (
+  SELECT
+    n.nspname AS schemaname,
+    c.relname AS tblname,
+    s.table_len AS real_bytes,
+    greatest(s.dead_tuple_len + s.free_space - s.table_len * (100 - c.fillfactor) / 100, 0)::bigint AS wasted_bytes,
+    c.fillfactor,
+    s.dead_tuple_len AS dead_tuple_bytes,
+    s.free_space AS free_bytes
+  FROM (
+    SELECT
+      c.oid,
+      c.relname,
+      c.relnamespace,
+      coalesce(substring(array_to_string(c.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::int, 100) AS fillfactor
+    FROM pg_class c
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    WHERE c.relkind IN ('r', 'm')
+      AND c.relpersistence <> 't'
+      AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+    ORDER BY pg_relation_size(c.oid) DESC
+    LIMIT COALESCE($1, 50)
+  ) c
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+  CROSS JOIN LATERAL pgstattuple(c.oid::regclass) s
+) t
+ORDER BY wasted_bytes DESC;
+`
+
+	// SelectIndexBloatExact - точный bloat btree-индексов через pgstatindex
+	// Читаются только $1 самых больших индексов; ожидаемый размер - листовые страницы
+	// при плотности fillfactor плюс внутренние страницы и метастраница
+	SelectIndexBloatExact = `
+SELECT
+  schemaname,
+  tblname,
+  idxname,
+  real_bytes,
+  wasted_bytes,
+  CASE WHEN real_bytes > 0 THEN round(100.0 * wasted_bytes / real_bytes, 2) ELSE 0 END AS bloat_pct,
+  fillfactor,
+  avg_leaf_density
+FROM (
+  SELECT
+    n.nspname AS schemaname,
+    t.relname AS tblname,
+    c.relname AS idxname,
+    s.index_size AS real_bytes,
+    CASE WHEN s.leaf_pages > 0
+         THEN greatest(s.index_size - ceil(s.leaf_pages * s.avg_leaf_density / c.fillfactor + s.internal_pages + 1)
+                       * current_setting('block_size')::bigint, 0)::bigint
+         ELSE 0 END AS wasted_bytes,
+    c.fillfactor,
+    CASE WHEN s.leaf_pages > 0 THEN s.avg_leaf_density END AS avg_leaf_density
+  FROM (
+    SELECT
+      c.oid,
+      c.relname,
+      c.relnamespace,
+      i.indrelid,
+      coalesce(substring(array_to_string(c.reloptions, ' ') FROM 'fillfactor=([0-9]+)')::int, 90) AS fillfactor
+    FROM pg_class c
+    JOIN pg_index i ON i.indexrelid = c.oid
+    JOIN pg_namespace n ON n.oid = c.relnamespace
+    WHERE c.relkind = 'i'
+      AND c.relam = (SELECT oid FROM pg_am WHERE amname = 'btree')
+      AND c.relpersistence <> 't'
+      AND i.indisvalid
+      AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+    ORDER BY pg_relation_size(c.oid) DESC
+    LIMIT COALESCE($1, 50)
+  ) c
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+  JOIN pg_class t ON t.oid = c.indrelid
+  CROSS JOIN LATERAL pgstatindex(c.oid::regclass) s
+) t
+ORDER BY wasted_bytes DESC;
+`
+
+	// SelectExtensionInstalled - установлен ли extension в текущей БД
+	SelectExtensionInstalled = `
+SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = $1);
+`
+
+	// Запросы советника по индексам возвращают одинаковый набор колонок:
+	// схема, таблица, индекс, связанный объект, размер, определение, причина, предлагаемый DDL
+
+	// SelectUnusedIndexes - индексы без сканирований с момента сброса статистики
+	// Уникальные индексы, первичные ключи и индексы ограничений (exclusion) не предлагаются к удалению
+	SelectUnusedIndexes = `
+SELECT
+  s.schemaname,
+  s.relname,
+  s.indexrelname,
+  NULL::text AS related,
+  pg_relation_size(s.indexrelid) AS size_bytes,
+  pg_get_indexdef(s.indexrelid) AS definition,
+  'no index scans since statistics reset' AS reason,
+  format('DROP INDEX CONCURRENTLY %I.%I;', s.schemaname, s.indexrelname) AS suggestion
+FROM pg_stat_user_indexes s
+JOIN pg_index i ON i.indexrelid = s.indexrelid
+WHERE s.idx_scan = 0
+  AND i.indisvalid
+  AND NOT i.indisunique
+  AND NOT i.indisprimary
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = s.indexrelid)
+ORDER BY size_bytes DESC;
+`
+
+	// SelectDuplicateIndexes - точные дубликаты: тот же метод, колонки, классы операторов, выражения и предикат
+	// Из группы дубликатов остаётся первичный ключ, затем уникальный индекс, затем созданный раньше (меньший oid)
+	SelectDuplicateIndexes = `
+SELECT DISTINCT ON (i.indexrelid)
+  n.nspname,
+  t.relname,
+  ci.relname,
+  ck.relname AS related,
+  pg_relation_size(i.indexrelid) AS size_bytes,
+  pg_get_indexdef(i.indexrelid) AS definition,
+  format('duplicate of %s', ck.relname) AS reason,
+  format('DROP INDEX CONCURRENTLY %I.%I;', n.nspname, ci.relname) AS suggestion
+FROM pg_index i
+JOIN pg_class ci ON ci.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_index k ON k.indrelid = i.indrelid AND k.indexrelid <> i.indexrelid
+JOIN pg_class ck ON ck.oid = k.indexrelid
+WHERE i.indisvalid
+  AND k.indisvalid
+  AND ck.relam = ci.relam
+  AND k.indkey::text = i.indkey::text
+  AND k.indclass::text = i.indclass::text
+  AND k.indcollation::text = i.indcollation::text
+  AND coalesce(pg_get_expr(k.indexprs, k.indrelid), '') = coalesce(pg_get_expr(i.indexprs, i.indrelid), '')
+  AND coalesce(pg_get_expr(k.indpred, k.indrelid), '') = coalesce(pg_get_expr(i.indpred, i.indrelid), '')
+  AND (k.indisprimary, k.indisunique, -k.indexrelid::bigint) > (i.indisprimary, i.indisunique, -i.indexrelid::bigint)
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = i.indexrelid)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY i.indexrelid, k.indisprimary DESC, k.indisunique DESC, k.indexrelid;
+`
+
+	// SelectRedundantIndexes - btree-индексы, колонки которых являются началом другого btree-индекса той же таблицы
+	// Уникальные индексы и индексы с выражениями или предикатом не рассматриваются
+	SelectRedundantIndexes = `
+SELECT DISTINCT ON (i.indexrelid)
+  n.nspname,
+  t.relname,
+  ci.relname,
+  ck.relname AS related,
+  pg_relation_size(i.indexrelid) AS size_bytes,
+  pg_get_indexdef(i.indexrelid) AS definition,
+  format('columns are a prefix of %s', ck.relname) AS reason,
+  format('DROP INDEX CONCURRENTLY %I.%I;', n.nspname, ci.relname) AS suggestion
+FROM pg_index i
+JOIN pg_class ci ON ci.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_index k ON k.indrelid = i.indrelid AND k.indexrelid <> i.indexrelid
+JOIN pg_class ck ON ck.oid = k.indexrelid
+WHERE ci.relam = (SELECT oid FROM pg_am WHERE amname = 'btree')
+  AND ck.relam = ci.relam
+  AND i.indisvalid
+  AND k.indisvalid
+  AND NOT i.indisunique
+  AND NOT i.indisprimary
+  AND i.indexprs IS NULL AND i.indpred IS NULL
+  AND k.indexprs IS NULL AND k.indpred IS NULL
+  AND k.indkey::text LIKE i.indkey::text || ' %'
+  AND k.indclass::text LIKE i.indclass::text || ' %'
+  AND k.indcollation::text LIKE i.indcollation::text || ' %'
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = i.indexrelid)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY i.indexrelid, pg_relation_size(k.indexrelid);
+`
+
+	// SelectInvalidIndexesV12 - невалидные индексы (упавший CREATE INDEX CONCURRENTLY) для PG ≥12
+	SelectInvalidIndexesV12 = `
+SELECT
+  n.nspname,
+  t.relname,
+  ci.relname,
+  NULL::text AS related,
+  pg_relation_size(i.indexrelid) AS size_bytes,
+  pg_get_indexdef(i.indexrelid) AS definition,
+  'index is invalid: a concurrent build failed or is still running' AS reason,
+  format('REINDEX INDEX CONCURRENTLY %I.%I;', n.nspname, ci.relname) AS suggestion
+FROM pg_index i
+JOIN pg_class ci ON ci.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE NOT i.indisvalid
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY size_bytes DESC;
+`
+
+	// SelectInvalidIndexesLegacy - невалидные индексы для PG <12 (нет REINDEX CONCURRENTLY)
+	SelectInvalidIndexesLegacy = `
+SELECT
+  n.nspname,
+  t.relname,
+  ci.relname,
+  NULL::text AS related,
+  pg_relation_size(i.indexrelid) AS size_bytes,
+  pg_get_indexdef(i.indexrelid) AS definition,
+  'index is invalid: a concurrent build failed or is still running' AS reason,
+  format('DROP INDEX CONCURRENTLY %I.%I; %s;',
+         n.nspname, ci.relname,
+         regexp_replace(pg_get_indexdef(i.indexrelid), '^CREATE (UNIQUE )?INDEX ', 'CREATE \1INDEX CONCURRENTLY ')) AS suggestion
+FROM pg_index i
+JOIN pg_class ci ON ci.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE NOT i.indisvalid
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY size_bytes DESC;
+`
+
+	// SelectUnindexedForeignKeys - внешние ключи без индекса, начинающегося с их колонок
+	// Размер - размер ссылающейся таблицы: её придётся сканировать при UPDATE/DELETE в родительской
+	SelectUnindexedForeignKeys = `
+SELECT
+  n.nspname,
+  t.relname,
+  NULL::text AS index_name,
+  c.conname AS related,
+  pg_relation_size(t.oid) AS size_bytes,
+  pg_get_constraintdef(c.oid) AS definition,
+  format('foreign key %s has no supporting index', c.conname) AS reason,
+  format('CREATE INDEX CONCURRENTLY ON %I.%I (%s);', n.nspname, t.relname,
+         (SELECT string_agg(quote_ident(a.attname), ', ' ORDER BY k.ord)
+          FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, ord)
+          JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum)) AS suggestion
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE c.contype = 'f'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND NOT EXISTS (
+    SELECT 1
+    FROM pg_index i
+    WHERE i.indrelid = c.conrelid
+      AND i.indisvalid
+      AND i.indpred IS NULL
+      AND (string_to_array(i.indkey::text, ' ')::int2[])[1:array_length(c.conkey, 1)] @> c.conkey
+  )
+ORDER BY size_bytes DESC;
+`
+
+	// SelectStatsReset - время сброса статистики текущей БД (от него считаются idx_scan)
+	SelectStatsReset = `
+SELECT stats_reset FROM pg_stat_database WHERE datname = current_database();
+`
+
+	// SelectLockWaitEdgesV14 - рёбра графа ожиданий блокировок по всему инстансу (PG ≥14)
+	// Каждая строка - ожидающий процесс и один из процессов из pg_blocking_pids.
+	// Для блокирующего берётся его блокировка на тот же объект (сначала выданная);
+	// её может не быть, если он сам стоит в очереди раньше ожидающего.
+	// Имя отношения резолвится только для текущей БД, для остальных возвращается OID.
+	// Время ожидания считается от pg_locks.waitstart (NULL в первые мгновения ожидания)
+	SelectLockWaitEdgesV14 = `
+WITH waiting AS (
+  SELECT l.*
+  FROM pg_locks l
+  WHERE NOT l.granted
+    AND l.pid <> pg_backend_pid()
+)
+SELECT
+  w.pid AS waiter_pid,
+  b.pid AS blocker_pid,
+  w.locktype,
+  CASE
+    WHEN w.relation IS NULL THEN NULL
+    WHEN w.database = (SELECT oid FROM pg_database WHERE datname = current_database()) THEN w.relation::regclass::text
+    ELSE w.relation::text
+  END AS relation,
+  w.mode AS waiter_mode,
+  bl.mode AS blocker_mode,
+  coalesce(bl.granted, false) AS blocker_granted,
+  wa.datname,
+  wa.usename AS waiter_username,
+  wa.query AS waiter_query,
+  EXTRACT(EPOCH FROM (now() - w.waitstart))::float8 AS waiter_wait_seconds,
+  ba.usename AS blocker_username,
+  ba.state AS blocker_state,
+  ba.query AS blocker_query,
+  EXTRACT(EPOCH FROM (now() - ba.xact_start))::float8 AS blocker_xact_seconds
+FROM waiting w
+CROSS JOIN LATERAL unnest(pg_blocking_pids(w.pid)) AS b(pid)
+LEFT JOIN LATERAL (
+  SELECT l.mode, l.granted
+  FROM pg_locks l
+  WHERE l.pid = b.pid
+    AND l.locktype = w.locktype
+    AND l.database IS NOT DISTINCT FROM w.database
+    AND l.relation IS NOT DISTINCT FROM w.relation
+    AND l.page IS NOT DISTINCT FROM w.page
+    AND l.tuple IS NOT DISTINCT FROM w.tuple
+    AND l.virtualxid IS NOT DISTINCT FROM w.virtualxid
+    AND l.transactionid IS NOT DISTINCT FROM w.transactionid
+    AND l.classid IS NOT DISTINCT FROM w.classid
+    AND l.objid IS NOT DISTINCT FROM w.objid
+    AND l.objsubid IS NOT DISTINCT FROM w.objsubid
+  ORDER BY l.granted DESC
+  LIMIT 1
+) bl ON true
+LEFT JOIN pg_stat_activity wa ON wa.pid = w.pid
+LEFT JOIN pg_stat_activity ba ON ba.pid = b.pid
+ORDER BY w.pid, b.pid;
+`
+
+	// SelectLockWaitEdgesLegacy - рёбра графа ожиданий блокировок по всему инстансу (PG 9.6-13)
+	// Каждая строка - ожидающий процесс и один из процессов из pg_blocking_pids.
+	// Для блокирующего берётся его блокировка на тот же объект (сначала выданная);
+	// её может не быть, если он сам стоит в очереди раньше ожидающего.
+	// Имя отношения резолвится только для текущей БД, для остальных возвращается OID.
+	// Время ожидания считается от state_change: waitstart в pg_locks появился только в PG14
+	SelectLockWaitEdgesLegacy = `
+WITH waiting AS (
+  SELECT l.*
+  FROM pg_locks l
+  WHERE NOT l.granted
+    AND l.pid <> pg_backend_pid()
+)
+SELECT
+  w.pid AS waiter_pid,
+  b.pid AS blocker_pid,
+  w.locktype,
+  CASE
+    WHEN w.relation IS NULL THEN NULL
+    WHEN w.database = (SELECT oid FROM pg_database WHERE datname = current_database()) THEN w.relation::regclass::text
+    ELSE w.relation::text
+  END AS relation,
+  w.mode AS waiter_mode,
+  bl.mode AS blocker_mode,
+  coalesce(bl.granted, false) AS blocker_granted,
+  wa.datname,
+  wa.usename AS waiter_username,
+  wa.query AS waiter_query,
+  EXTRACT(EPOCH FROM (now() - wa.state_change))::float8 AS waiter_wait_seconds,
+  ba.usename AS blocker_username,
+  ba.state AS blocker_state,
+  ba.query AS blocker_query,
+  EXTRACT(EPOCH FROM (now() - ba.xact_start))::float8 AS blocker_xact_seconds
+FROM waiting w
+CROSS JOIN LATERAL unnest(pg_blocking_pids(w.pid)) AS b(pid)
+LEFT JOIN LATERAL (
+  SELECT l.mode, l.granted
+  FROM pg_locks l
+  WHERE l.pid = b.pid
+    AND l.locktype = w.locktype
+    AND l.database IS NOT DISTINCT FROM w.database
+    AND l.relation IS NOT DISTINCT FROM w.relation
+    AND l.page IS NOT DISTINCT FROM w.page
+    AND l.tuple IS NOT DISTINCT FROM w.tuple
+    AND l.virtualxid IS NOT DISTINCT FROM w.virtualxid
+    AND l.transactionid IS NOT DISTINCT FROM w.transactionid
+    AND l.classid IS NOT DISTINCT FROM w.classid
+    AND l.objid IS NOT DISTINCT FROM w.objid
+    AND l.objsubid IS NOT DISTINCT FROM w.objsubid
+  ORDER BY l.granted DESC
+  LIMIT 1
+) bl ON true
+LEFT JOIN pg_stat_activity wa ON wa.pid = w.pid
+LEFT JOIN pg_stat_activity ba ON ba.pid = b.pid
+ORDER BY w.pid, b.pid;
+`
+
+	// SelectBackendV10 - процесс по PID для проверки перед отменой или завершением (PG ≥10)
+	// Служебные процессы (walsender, autovacuum, фоновые воркеры) имеют backend_type <> 'client backend'
+	SelectBackendV10 = `
+SELECT
+  a.pid,
+  a.usename,
+  a.datname,
+  a.application_name,
+  a.backend_type,
+  a.state,
+  a.query_start,
+  a.query,
+  coalesce(r.rolsuper, false) AS superuser,
+  a.backend_type = 'client backend' AS client_backend
+FROM pg_stat_activity a
+LEFT JOIN pg_roles r ON r.oid = a.usesysid
+WHERE a.pid = $1;
+`
+
+	// SelectBackendLegacy - процесс по PID для PG <10: backend_type нет, в pg_stat_activity
+	// кроме клиентских процессов видны только walsender'ы
+	SelectBackendLegacy = `
+SELECT
+  a.pid,
+  a.usename,
+  a.datname,
+  a.application_name,
+  NULL::text AS backend_type,
+  a.state,
+  a.query_start,
+  a.query,
+  coalesce(r.rolsuper, false) AS superuser,
+  a.pid NOT IN (SELECT pid FROM pg_stat_replication) AS client_backend
+FROM pg_stat_activity a
+LEFT JOIN pg_roles r ON r.oid = a.usesysid
+WHERE a.pid = $1;
+`
+
+	// SignalBackendV10 - отмена запроса ($3 = false) или завершение процесса ($3 = true) (PG ≥10)
+	// Все проверки повторяются в том же запросе: процесс должен быть клиентским, не суперпользователя,
+	// и время начала запроса должно в точности совпадать с наблюдённым ($2), иначе строк нет
+	SignalBackendV10 = `
+SELECT CASE WHEN $3 THEN pg_terminate_backend(a.pid) ELSE pg_cancel_backend(a.pid) END
+FROM pg_stat_activity a
+LEFT JOIN pg_roles r ON r.oid = a.usesysid
+WHERE a.pid = $1
+  AND a.query_start = $2::timestamptz
+  AND NOT coalesce(r.rolsuper, false)
+  AND a.backend_type = 'client backend';
+`
+
+	// SignalBackendLegacy - отмена или завершение процесса для PG <10
+	SignalBackendLegacy = `
+SELECT CASE WHEN $3 THEN pg_terminate_backend(a.pid) ELSE pg_cancel_backend(a.pid) END
+FROM pg_stat_activity a
+LEFT JOIN pg_roles r ON r.oid = a.usesysid
+WHERE a.pid = $1
+  AND a.query_start = $2::timestamptz
+  AND NOT coalesce(r.rolsuper, false)
+  AND a.pid NOT IN (SELECT pid FROM pg_stat_replication);
+`
+
+	// Запросы описания схемы. Системные схемы (pg_catalog, information_schema, pg_toast, временные) скрыты.
+	// Пустая строка в параметре схемы означает все схемы
+
+	// SelectSchemas - схемы с владельцем и числом объектов
+	SelectSchemas = `
+SELECT
+  n.nspname,
+  pg_get_userbyid(n.nspowner) AS owner,
+  (SELECT count(*) FROM pg_class c WHERE c.relnamespace = n.oid AND c.relkind IN ('r', 'p', 'f')) AS tables,
+  (SELECT count(*) FROM pg_class c WHERE c.relnamespace = n.oid AND c.relkind IN ('v', 'm')) AS views,
+  (SELECT count(*) FROM pg_proc p WHERE p.pronamespace = n.oid) AS functions,
+  obj_description(n.oid, 'pg_namespace') AS comment
+FROM pg_namespace n
+WHERE n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname;
+`
+
+	// SelectSchemaTables - страница таблиц (обычных, секционированных и внешних)
+	// $1 - схема, $2 - лимит, $3 - смещение
+	SelectSchemaTables = `
+SELECT
+  c.oid,
+  n.nspname,
+  c.relname,
+  CASE c.relkind
+    WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'partitioned table'
+    WHEN 'f' THEN 'foreign table'
+  END AS kind,
+  CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END AS estimated_rows,
+  pg_total_relation_size(c.oid) AS total_bytes,
+  obj_description(c.oid, 'pg_class') AS comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p', 'f')
+  AND ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, c.relname
+LIMIT $2 OFFSET $3;
+`
+
+	// SelectColumns - колонки отношений с типами, значениями по умолчанию и комментариями
+	// $1 - массив OID отношений
+	SelectColumns = `
+SELECT
+  a.attrelid,
+  a.attname,
+  format_type(a.atttypid, a.atttypmod) AS type,
+  NOT a.attnotnull AS nullable,
+  pg_get_expr(d.adbin, d.adrelid) AS default_value,
+  col_description(a.attrelid, a.attnum) AS comment
+FROM pg_attribute a
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = ANY($1::oid[])
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+ORDER BY a.attrelid, a.attnum;
+`
+
+	// SelectRelation - отношение (таблица или представление) по схеме и имени
+	SelectRelation = `
+SELECT
+  c.oid,
+  n.nspname,
+  c.relname,
+  CASE c.relkind
+    WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'partitioned table'
+    WHEN 'f' THEN 'foreign table'
+    WHEN 'v' THEN 'view'
+    WHEN 'm' THEN 'materialized view'
+  END AS kind,
+  CASE WHEN c.reltuples < 0 OR c.relkind = 'v' THEN NULL ELSE c.reltuples::bigint END AS estimated_rows,
+  pg_total_relation_size(c.oid) AS total_bytes,
+  obj_description(c.oid, 'pg_class') AS comment,
+  CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) END AS view_definition
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1
+  AND c.relname = $2
+  AND c.relkind IN ('r', 'p', 'f', 'v', 'm');
+`
+
+	// SelectConstraints - ограничения отношений: первичный ключ, внешние ключи, уникальность, check, exclusion
+	// $1 - массив OID отношений
+	SelectConstraints = `
+SELECT
+  con.conrelid,
+  con.conname,
+  CASE con.contype
+    WHEN 'p' THEN 'primary_key'
+    WHEN 'f' THEN 'foreign_key'
+    WHEN 'u' THEN 'unique'
+    WHEN 'c' THEN 'check'
+    WHEN 'x' THEN 'exclusion'
+  END AS type,
+  ARRAY(
+    SELECT a.attname
+    FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+    JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+    ORDER BY k.ord
+  )::text[] AS columns,
+  pg_get_constraintdef(con.oid, true) AS definition
+FROM pg_constraint con
+WHERE con.conrelid = ANY($1::oid[])
+  AND con.contype IN ('p', 'f', 'u', 'c', 'x')
+ORDER BY con.conrelid, position(con.contype IN 'pufcx'), con.conname;
+`
+
+	// SelectIndexDefinitions - индексы отношений с DDL из pg_get_indexdef и ограничением, которое индекс обслуживает
+	// $1 - массив OID отношений
+	SelectIndexDefinitions = `
+SELECT
+  i.indrelid,
+  ci.relname,
+  pg_get_indexdef(i.indexrelid) AS definition,
+  i.indisprimary,
+  i.indisunique,
+  i.indisvalid,
+  pg_relation_size(i.indexrelid) AS size_bytes,
+  con.conname AS constraint_name
+FROM pg_index i
+JOIN pg_class ci ON ci.oid = i.indexrelid
+LEFT JOIN pg_constraint con ON con.conindid = i.indexrelid
+  AND con.conrelid = i.indrelid
+  AND con.contype IN ('p', 'u', 'x')
+WHERE i.indrelid = ANY($1::oid[])
+ORDER BY i.indrelid, i.indisprimary DESC, ci.relname;
+`
+
+	// SelectSchemaViews - страница представлений и материализованных представлений с определениями
+	// $1 - схема, $2 - лимит, $3 - смещение
+	SelectSchemaViews = `
+SELECT
+  n.nspname,
+  c.relname,
+  c.relkind = 'm' AS materialized,
+  pg_get_viewdef(c.oid, true) AS definition,
+  obj_description(c.oid, 'pg_class') AS comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('v', 'm')
+  AND ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, c.relname
+LIMIT $2 OFFSET $3;
+`
+
+	// SelectSchemaFunctionsV11 - страница функций и процедур (PG ≥11, есть prokind)
+	// $1 - схема, $2 - лимит, $3 - смещение
+	SelectSchemaFunctionsV11 = `
+SELECT
+  n.nspname,
+  p.proname,
+  CASE p.prokind
+    WHEN 'f' THEN 'function'
+    WHEN 'p' THEN 'procedure'
+    WHEN 'a' THEN 'aggregate'
+    WHEN 'w' THEN 'window'
+  END AS kind,
+  pg_get_function_arguments(p.oid) AS arguments,
+  CASE WHEN p.prokind = 'p' THEN NULL ELSE pg_get_function_result(p.oid) END AS return_type,
+  l.lanname AS language,
+  CASE p.provolatile WHEN 'i' THEN 'immutable' WHEN 's' THEN 'stable' ELSE 'volatile' END AS volatility,
+  obj_description(p.oid, 'pg_proc') AS comment
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+JOIN pg_language l ON l.oid = p.prolang
+WHERE ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, p.proname, pg_get_function_arguments(p.oid)
+LIMIT $2 OFFSET $3;
+`
+
+	// SelectSchemaFunctionsLegacy - страница функций для PG <11 (процедур нет, вид по proisagg/proiswindow)
+	SelectSchemaFunctionsLegacy = `
+SELECT
+  n.nspname,
+  p.proname,
+  CASE WHEN p.proisagg THEN 'aggregate' WHEN p.proiswindow THEN 'window' ELSE 'function' END AS kind,
+  pg_get_function_arguments(p.oid) AS arguments,
+  pg_get_function_result(p.oid) AS return_type,
+  l.lanname AS language,
+  CASE p.provolatile WHEN 'i' THEN 'immutable' WHEN 's' THEN 'stable' ELSE 'volatile' END AS volatility,
+  obj_description(p.oid, 'pg_proc') AS comment
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+JOIN pg_language l ON l.oid = p.prolang
+WHERE ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, p.proname, pg_get_function_arguments(p.oid)
+LIMIT $2 OFFSET $3;
+`
+
+	// SelectSnapshotTablesV10 - все таблицы базы для сравнения схем (PG ≥10)
+	// с ключом секционирования, родителем и границами секций и сервером внешних таблиц
+	// $1 - схема (пусто - все схемы)
+	SelectSnapshotTablesV10 = `
+SELECT
+  c.oid,
+  n.nspname,
+  c.relname,
+  CASE c.relkind
+    WHEN 'r' THEN 'table'
+    WHEN 'p' THEN 'partitioned table'
+    WHEN 'f' THEN 'foreign table'
+  END AS kind,
+  CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END AS estimated_rows,
+  pg_total_relation_size(c.oid) AS total_bytes,
+  obj_description(c.oid, 'pg_class') AS comment,
+  CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) END AS partition_key,
+  pn.nspname AS parent_schema,
+  pc.relname AS parent_table,
+  CASE WHEN c.relispartition THEN pg_get_expr(c.relpartbound, c.oid) END AS partition_bound,
+  fs.srvname AS foreign_server,
+  (
+    SELECT string_agg(quote_ident(o.option_name) || ' ' || quote_literal(o.option_value), ', ')
+    FROM pg_options_to_table(ft.ftoptions) o
+  ) AS foreign_options
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_inherits i ON c.relispartition AND i.inhrelid = c.oid
+LEFT JOIN pg_class pc ON pc.oid = i.inhparent
+LEFT JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+LEFT JOIN pg_foreign_table ft ON ft.ftrelid = c.oid
+LEFT JOIN pg_foreign_server fs ON fs.oid = ft.ftserver
+WHERE c.relkind IN ('r', 'p', 'f')
+  AND ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, c.relname;
+`
+
+	// SelectSnapshotTablesLegacy - все таблицы базы для сравнения схем (PG <10, без секционирования)
+	// $1 - схема (пусто - все схемы)
+	SelectSnapshotTablesLegacy = `
+SELECT
+  c.oid,
+  n.nspname,
+  c.relname,
+  CASE c.relkind
+    WHEN 'r' THEN 'table'
+    WHEN 'f' THEN 'foreign table'
+  END AS kind,
+  CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END AS estimated_rows,
+  pg_total_relation_size(c.oid) AS total_bytes,
+  obj_description(c.oid, 'pg_class') AS comment,
+  NULL::text AS partition_key,
+  NULL::text AS parent_schema,
+  NULL::text AS parent_table,
+  NULL::text AS partition_bound,
+  fs.srvname AS foreign_server,
+  (
+    SELECT string_agg(quote_ident(o.option_name) || ' ' || quote_literal(o.option_value), ', ')
+    FROM pg_options_to_table(ft.ftoptions) o
+  ) AS foreign_options
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_foreign_table ft ON ft.ftrelid = c.oid
+LEFT JOIN pg_foreign_server fs ON fs.oid = ft.ftserver
+WHERE c.relkind IN ('r', 'f')
+  AND ($1 = '' OR n.nspname = $1)
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+  AND n.nspname NOT LIKE 'pg_temp_%'
+  AND n.nspname NOT LIKE 'pg_toast_temp_%'
+ORDER BY n.nspname, c.relname;
+`
+
+	// SelectExtensions - установленные расширения с версиями
+	SelectExtensions = `
+SELECT e.extname, e.extversion, n.nspname
+FROM pg_extension e
+JOIN pg_namespace n ON n.oid = e.extnamespace
+ORDER BY e.extname;
 `
 )
@@ -1,9 +1,5 @@
 package pg
 
-import (
-	"database/sql"
-)
-
 // DatabaseOverview - основная статистика по БД
 type DatabaseOverview struct {
 	XactCommit   int64   `json:"xact_commit"`
@@ -25,69 +21,132 @@ type DatabaseOverview struct {
 
 // CacheHitRate - cache hit rate (процент попаданий в кэш)
 type CacheHitRate struct {
-	HitRate sql.NullFloat64 `json:"hit_rate"`
+	HitRate NullFloat64 `json:"hit_rate"`
 }
 
 // CheckpointsStats - статистика чекпоинтов
 type CheckpointsStats struct {
-	CheckpointsTimed    int64         `json:"checkpoints_timed"`
-	CheckpointsReq      int64         `json:"checkpoints_req"`
-	CheckpointWriteTime float64       `json:"checkpoint_write_time"`
-	CheckpointSyncTime  float64       `json:"checkpoint_sync_time"`
-	BuffersCheckpoint   sql.NullInt64 `json:"buffers_checkpoint,omitempty"`    // только в legacy
-	BuffersBackend      sql.NullInt64 `json:"buffers_backend,omitempty"`       // только в legacy
-	BuffersBackendFsync sql.NullInt64 `json:"buffers_backend_fsync,omitempty"` // только в legacy
-	BuffersAlloc        sql.NullInt64 `json:"buffers_alloc,omitempty"`         // только в legacy
+	CheckpointsTimed    int64     `json:"checkpoints_timed"`
+	CheckpointsReq      int64     `json:"checkpoints_req"`
+	CheckpointWriteTime float64   `json:"checkpoint_write_time"`
+	CheckpointSyncTime  float64   `json:"checkpoint_sync_time"`
+	BuffersCheckpoint   NullInt64 `json:"buffers_checkpoint,omitempty"`    // только в legacy
+	BuffersBackend      NullInt64 `json:"buffers_backend,omitempty"`       // только в legacy
+	BuffersBackendFsync NullInt64 `json:"buffers_backend_fsync,omitempty"` // только в legacy
+	BuffersAlloc        NullInt64 `json:"buffers_alloc,omitempty"`         // только в legacy
+}
+
+// IOStat - строка pg_stat_io: ввод-вывод одного типа процессов по объекту и контексту.
+// NULL означает, что операция для этого сочетания не бывает; *_time в миллисекундах
+type IOStat struct {
+	BackendType   string      `json:"backend_type"`
+	Object        string      `json:"object"`  // relation, temp relation (PG 18: ещё wal)
+	Context       string      `json:"context"` // normal, vacuum, bulkread, bulkwrite (PG 18: ещё init)
+	Reads         NullInt64   `json:"reads"`
+	ReadTime      NullFloat64 `json:"read_time"`
+	Writes        NullInt64   `json:"writes"`
+	WriteTime     NullFloat64 `json:"write_time"`
+	Writebacks    NullInt64   `json:"writebacks"`
+	WritebackTime NullFloat64 `json:"writeback_time"`
+	Extends       NullInt64   `json:"extends"`
+	ExtendTime    NullFloat64 `json:"extend_time"`
+	Hits          NullInt64   `json:"hits"`
+	HitRate       NullFloat64 `json:"hit_rate"`
+	Evictions     NullInt64   `json:"evictions"`
+	Reuses        NullInt64   `json:"reuses"`
+	Fsyncs        NullInt64   `json:"fsyncs"`
+	FsyncTime     NullFloat64 `json:"fsync_time"`
+}
+
+// TableIO - чтения блоков таблицы из pg_statio_user_tables и доля попаданий в shared buffers
+type TableIO struct {
+	Schema        string      `json:"schema"`
+	Table         string      `json:"table"`
+	HeapBlksRead  int64       `json:"heap_blks_read"`
+	HeapBlksHit   int64       `json:"heap_blks_hit"`
+	HeapHitRate   NullFloat64 `json:"heap_hit_rate"`
+	IdxBlksRead   NullInt64   `json:"idx_blks_read"`
+	IdxBlksHit    NullInt64   `json:"idx_blks_hit"`
+	IdxHitRate    NullFloat64 `json:"idx_hit_rate"`
+	ToastBlksRead NullInt64   `json:"toast_blks_read"`
+	ToastBlksHit  NullInt64   `json:"toast_blks_hit"`
+	ToastHitRate  NullFloat64 `json:"toast_hit_rate"`
+}
+
+// IndexIO - чтения блоков индекса из pg_statio_user_indexes
+type IndexIO struct {
+	Schema   string      `json:"schema"`
+	Table    string      `json:"table"`
+	Index    string      `json:"index"`
+	BlksRead int64       `json:"blks_read"`
+	BlksHit  int64       `json:"blks_hit"`
+	HitRate  NullFloat64 `json:"hit_rate"`
 }
 
+// IOReport - статистика ввода-вывода (version-aware): на PG ≥16 заполнен IO из
+// pg_stat_io, на более старых - Tables и Indexes текущей БД
+type IOReport struct {
+	Source     string    `json:"source"` // pg_stat_io или pg_statio
+	StatsReset NullTime  `json:"stats_reset"`
+	IO         []IOStat  `json:"io,omitempty"`
+	Tables     []TableIO `json:"tables,omitempty"`
+	Indexes    []IndexIO `json:"indexes,omitempty"`
+}
+
+// Источники IOReport
+const (
+	IOSourceStatIO    = "pg_stat_io"
+	IOSourceRelations = "pg_statio"
+)
+
 // WalActivity - статистика WAL (доступна только в PG ≥14)
 type WalActivity struct {
-	WalRecords     int64        `json:"wal_records"`
-	WalFpi         int64        `json:"wal_fpi"`
-	WalBytes       int64        `json:"wal_bytes"`
-	WalBuffersFull int64        `json:"wal_buffers_full"`
-	StatsReset     sql.NullTime `json:"stats_reset"`
+	WalRecords     int64    `json:"wal_records"`
+	WalFpi         int64    `json:"wal_fpi"`
+	WalBytes       int64    `json:"wal_bytes"`
+	WalBuffersFull int64    `json:"wal_buffers_full"`
+	StatsReset     NullTime `json:"stats_reset"`
 }
 
 // TableInfo - статистика по таблице
 type TableInfo struct {
-	SchemaName      string          `json:"schema_name"`
-	TableName       string          `json:"table_name"`
-	TotalBytes      int64           `json:"total_bytes"`
-	TableBytes      int64           `json:"table_bytes"`
-	IndexesBytes    int64           `json:"indexes_bytes"`
-	NLiveTup        sql.NullInt64   `json:"n_live_tup"`
-	NDeadTup        sql.NullInt64   `json:"n_dead_tup"`
-	DeadRatio       sql.NullFloat64 `json:"dead_ratio"`
-	SeqScan         sql.NullInt64   `json:"seq_scan"`
-	IdxScan         sql.NullInt64   `json:"idx_scan"`
-	LastVacuum      sql.NullTime    `json:"last_vacuum"`
-	LastAutovacuum  sql.NullTime    `json:"last_autovacuum"`
-	LastAnalyze     sql.NullTime    `json:"last_analyze"`
-	LastAutoanalyze sql.NullTime    `json:"last_autoanalyze"`
-	VacuumCount     sql.NullInt64   `json:"vacuum_count"`
-	AutovacuumCount sql.NullInt64   `json:"autovacuum_count"`
+	SchemaName      string      `json:"schema_name"`
+	TableName       string      `json:"table_name"`
+	TotalBytes      int64       `json:"total_bytes"`
+	TableBytes      int64       `json:"table_bytes"`
+	IndexesBytes    int64       `json:"indexes_bytes"`
+	NLiveTup        NullInt64   `json:"n_live_tup"`
+	NDeadTup        NullInt64   `json:"n_dead_tup"`
+	DeadRatio       NullFloat64 `json:"dead_ratio"`
+	SeqScan         NullInt64   `json:"seq_scan"`
+	IdxScan         NullInt64   `json:"idx_scan"`
+	LastVacuum      NullTime    `json:"last_vacuum"`
+	LastAutovacuum  NullTime    `json:"last_autovacuum"`
+	LastAnalyze     NullTime    `json:"last_analyze"`
+	LastAutoanalyze NullTime    `json:"last_autoanalyze"`
+	VacuumCount     NullInt64   `json:"vacuum_count"`
+	AutovacuumCount NullInt64   `json:"autovacuum_count"`
 }
 
 // LockInfo - информация о блокировках
 type LockInfo struct {
-	PID           int            `json:"pid"`
-	Username      sql.NullString `json:"username"`
-	Database      sql.NullString `json:"database"`
-	WaitEventType sql.NullString `json:"wait_event_type"`
-	WaitEvent     sql.NullString `json:"wait_event"`
-	State         sql.NullString `json:"state"`
-	QueryStart    sql.NullTime   `json:"query_start"`
-	BlockingPids  string         `json:"blocking_pids"` // массив как строка, например "{123,456}"
+	PID           int        `json:"pid"`
+	Username      NullString `json:"username"`
+	Database      NullString `json:"database"`
+	WaitEventType NullString `json:"wait_event_type"`
+	WaitEvent     NullString `json:"wait_event"`
+	State         NullString `json:"state"`
+	QueryStart    NullTime   `json:"query_start"`
+	BlockingPids  []int64    `json:"blocking_pids"` // PID процессов, блокирующих этот
 }
 
 // SettingInfo - информация о настройке PostgreSQL
 type SettingInfo struct {
-	Name           string         `json:"name"`
-	Setting        string         `json:"setting"`
-	Unit           sql.NullString `json:"unit"`
-	Source         string         `json:"source"`
-	PendingRestart bool           `json:"pending_restart"`
+	Name           string     `json:"name"`
+	Setting        string     `json:"setting"`
+	Unit           NullString `json:"unit"`
+	Source         string     `json:"source"`
+	PendingRestart bool       `json:"pending_restart"`
 }
 
 // Version - информация о версии PostgreSQL
@@ -108,32 +167,131 @@ func (v *Version) SupportsCheckpointer() bool {
 	return v.Major >= 17
 }
 
+// SupportsStatIO проверяет, поддерживает ли версия pg_stat_io (PG ≥16)
+func (v *Version) SupportsStatIO() bool {
+	return v.Major >= 16
+}
+
+// SupportsStatementsExecTime проверяет, есть ли в pg_stat_statements колонки *_exec_time (PG ≥13)
+func (v *Version) SupportsStatementsExecTime() bool {
+	return v.Major >= 13
+}
+
+// SupportsStatementsInfo проверяет, есть ли представление pg_stat_statements_info (PG ≥14)
+func (v *Version) SupportsStatementsInfo() bool {
+	return v.Major >= 14
+}
+
+// SupportsStatementsSharedBlkTime проверяет, переименованы ли blk_*_time в shared_blk_*_time (PG ≥17)
+func (v *Version) SupportsStatementsSharedBlkTime() bool {
+	return v.Major >= 17
+}
+
+// SupportsActivityQueryID проверяет, есть ли query_id в pg_stat_activity (PG ≥14)
+func (v *Version) SupportsActivityQueryID() bool {
+	return v.Major >= 14
+}
+
 // SupportsBlockingPids проверяет, поддерживает ли версия pg_blocking_pids (PG ≥9.6)
 func (v *Version) SupportsBlockingPids() bool {
 	return v.Major >= 10 || (v.Major == 9 && v.Minor >= 6)
 }
 
+// SupportsLockWaitStart проверяет, есть ли waitstart в pg_locks (PG ≥14)
+func (v *Version) SupportsLockWaitStart() bool {
+	return v.Major >= 14
+}
+
+// SupportsReplicationStats проверяет, есть ли *_lsn и *_lag колонки в pg_stat_replication (PG ≥10)
+func (v *Version) SupportsReplicationStats() bool {
+	return v.Major >= 10
+}
+
+// SupportsReplyTime проверяет, есть ли reply_time в pg_stat_replication (PG ≥12)
+func (v *Version) SupportsReplyTime() bool {
+	return v.Major >= 12
+}
+
+// SupportsWalReceiverSender проверяет, есть ли sender_host и sender_port в pg_stat_wal_receiver (PG ≥11)
+func (v *Version) SupportsWalReceiverSender() bool {
+	return v.Major >= 11
+}
+
+// SupportsWalReceiverFlushedLsn проверяет, есть ли flushed_lsn и written_lsn в pg_stat_wal_receiver (PG ≥13)
+func (v *Version) SupportsWalReceiverFlushedLsn() bool {
+	return v.Major >= 13
+}
+
+// SupportsSlotWalStatus проверяет, есть ли wal_status и safe_wal_size в pg_replication_slots (PG ≥13)
+func (v *Version) SupportsSlotWalStatus() bool {
+	return v.Major >= 13
+}
+
+// SupportsBackendType проверяет, есть ли backend_type в pg_stat_activity (PG ≥10)
+func (v *Version) SupportsBackendType() bool {
+	return v.Major >= 10
+}
+
+// SupportsPartitioning проверяет, поддерживает ли версия декларативное секционирование (PG ≥10)
+func (v *Version) SupportsPartitioning() bool {
+	return v.Major >= 10
+}
+
+// SupportsProcKind проверяет, есть ли pg_proc.prokind и процедуры (PG ≥11)
+func (v *Version) SupportsProcKind() bool {
+	return v.Major >= 11
+}
+
+// SupportsProgressCreateIndex проверяет, есть ли pg_stat_progress_create_index и pg_stat_progress_cluster (PG ≥12)
+func (v *Version) SupportsProgressCreateIndex() bool {
+	return v.Major >= 12
+}
+
+// SupportsProgressAnalyze проверяет, есть ли pg_stat_progress_analyze (PG ≥13)
+func (v *Version) SupportsProgressAnalyze() bool {
+	return v.Major >= 13
+}
+
+// SupportsReindexConcurrently проверяет, поддерживает ли версия REINDEX CONCURRENTLY (PG ≥12)
+func (v *Version) SupportsReindexConcurrently() bool {
+	return v.Major >= 12
+}
+
 // IndexStats - статистика по индексу
 type IndexStats struct {
-	SchemaName  string        `json:"schema_name"`
-	TableName   string        `json:"table_name"`
-	IndexName   string        `json:"index_name"`
-	IdxScan     sql.NullInt64 `json:"idx_scan"`
-	IdxTupRead  sql.NullInt64 `json:"idx_tup_read"`
-	IdxTupFetch sql.NullInt64 `json:"idx_tup_fetch"`
-	SizeBytes   int64         `json:"size_bytes"`
+	SchemaName  string    `json:"schema_name"`
+	TableName   string    `json:"table_name"`
+	IndexName   string    `json:"index_name"`
+	IdxScan     NullInt64 `json:"idx_scan"`
+	IdxTupRead  NullInt64 `json:"idx_tup_read"`
+	IdxTupFetch NullInt64 `json:"idx_tup_fetch"`
+	SizeBytes   int64     `json:"size_bytes"`
 }
 
 // ActiveQuery - информация об активном запросе
 type ActiveQuery struct {
-	PID             int             `json:"pid"`
-	Username        sql.NullString  `json:"username"`
-	Database        sql.NullString  `json:"database"`
-	State           sql.NullString  `json:"state"`
-	DurationSeconds sql.NullFloat64 `json:"duration_seconds"`
-	WaitEventType   sql.NullString  `json:"wait_event_type"`
-	WaitEvent       sql.NullString  `json:"wait_event"`
-	Query           sql.NullString  `json:"query"`
+	PID             int         `json:"pid"`
+	Username        NullString  `json:"username"`
+	Database        NullString  `json:"database"`
+	State           NullString  `json:"state"`
+	QueryStart      NullTime    `json:"query_start"` // нужен для cancel_backend/terminate_backend
+	DurationSeconds NullFloat64 `json:"duration_seconds"`
+	WaitEventType   NullString  `json:"wait_event_type"`
+	WaitEvent       NullString  `json:"wait_event"`
+	Query           NullString  `json:"query"`
+}
+
+// ActivitySample - активная сессия в одном снимке pg_stat_activity
+// Без wait_event сессия работает на CPU (или ждёт то, что не инструментировано)
+type ActivitySample struct {
+	PID           int        `json:"pid"`
+	Username      NullString `json:"username"`
+	Database      NullString `json:"database"`
+	BackendType   NullString `json:"backend_type"`
+	WaitEventType NullString `json:"wait_event_type"`
+	WaitEvent     NullString `json:"wait_event"`
+	QueryID       NullInt64  `json:"query_id"` // только PG ≥14 с compute_query_id
+	Query         NullString `json:"query"`
 }
 
 // ConnectionSummary - сводная статистика соединений
@@ -148,13 +306,81 @@ type ConnectionSummary struct {
 
 // SlowQuery - информация о медленном запросе из pg_stat_statements
 type SlowQuery struct {
-	Query           string          `json:"query"`
-	Calls           int64           `json:"calls"`
-	TotalExecTime   float64         `json:"total_exec_time"`
-	MeanExecTime    float64         `json:"mean_exec_time"`
-	StddevExecTime  float64         `json:"stddev_exec_time"`
-	Rows            int64           `json:"rows"`
-	CacheHitPercent sql.NullFloat64 `json:"cache_hit_percent"`
+	Query           string      `json:"query"`
+	Calls           int64       `json:"calls"`
+	TotalExecTime   float64     `json:"total_exec_time"`
+	MeanExecTime    float64     `json:"mean_exec_time"`
+	StddevExecTime  float64     `json:"stddev_exec_time"`
+	Rows            int64       `json:"rows"`
+	CacheHitPercent NullFloat64 `json:"cache_hit_percent"`
+}
+
+// Statement - строка pg_stat_statements со всеми измерениями топа
+// Колонки, которых нет в версии сервера (планирование и WAL до PG13), остаются null
+type Statement struct {
+	QueryID         NullInt64   `json:"queryid"`
+	UserID          int64       `json:"userid"`
+	Username        NullString  `json:"username"`
+	DbID            int64       `json:"dbid"`
+	Database        NullString  `json:"database"`
+	Query           string      `json:"query"`
+	Calls           int64       `json:"calls"`
+	TotalExecTime   float64     `json:"total_exec_time"`
+	MeanExecTime    float64     `json:"mean_exec_time"`
+	StddevExecTime  float64     `json:"stddev_exec_time"`
+	TotalPlanTime   NullFloat64 `json:"total_plan_time"`
+	MeanPlanTime    NullFloat64 `json:"mean_plan_time"`
+	Rows            int64       `json:"rows"`
+	SharedBlksHit   int64       `json:"shared_blks_hit"`
+	SharedBlksRead  int64       `json:"shared_blks_read"`
+	CacheHitPercent NullFloat64 `json:"cache_hit_percent"`
+	TempBlksRead    int64       `json:"temp_blks_read"`
+	TempBlksWritten int64       `json:"temp_blks_written"`
+	BlkReadTime     float64     `json:"blk_read_time"`  // мс, ненулевое только при track_io_timing
+	BlkWriteTime    float64     `json:"blk_write_time"` // мс, ненулевое только при track_io_timing
+	WalRecords      NullInt64   `json:"wal_records"`
+	WalBytes        NullInt64   `json:"wal_bytes"`
+}
+
+// StatementsInfo - состояние самого pg_stat_statements
+// Dealloc и StatsReset есть только с PG14 (pg_stat_statements_info)
+type StatementsInfo struct {
+	Dealloc    NullInt64 `json:"dealloc"`     // сколько раз вытеснялись редкие statements
+	StatsReset NullTime  `json:"stats_reset"` // последний сброс всей статистики
+	Max        NullInt64 `json:"max"`         // pg_stat_statements.max
+	Tracked    int64     `json:"tracked"`     // сколько statements отслеживается сейчас
+}
+
+// StatementsReport - топ statements по выбранному измерению
+type StatementsReport struct {
+	OrderBy    string         `json:"order_by"`
+	Statements []Statement    `json:"statements"`
+	Info       StatementsInfo `json:"info"`
+}
+
+// StatementsAll - limit GetStatements, при котором читаются все записи pg_stat_statements
+const StatementsAll = -1
+
+// Измерения, по которым сортируется топ statements
+const (
+	StatementsOrderTotalTime   = "total_time"
+	StatementsOrderMeanTime    = "mean_time"
+	StatementsOrderCalls       = "calls"
+	StatementsOrderRows        = "rows"
+	StatementsOrderSharedReads = "shared_reads"
+	StatementsOrderTempBlocks  = "temp_blocks"
+	StatementsOrderWalBytes    = "wal_bytes"
+)
+
+// StatementsOrders - допустимые измерения сортировки в порядке перечисления
+var StatementsOrders = []string{
+	StatementsOrderTotalTime,
+	StatementsOrderMeanTime,
+	StatementsOrderCalls,
+	StatementsOrderRows,
+	StatementsOrderSharedReads,
+	StatementsOrderTempBlocks,
+	StatementsOrderWalBytes,
 }
 
 // DatabaseSize - информация о размере базы данных
@@ -162,3 +388,359 @@ type DatabaseSize struct {
 	DatabaseName string `json:"database_name"`
 	SizeBytes    int64  `json:"size_bytes"`
 }
+
+// Роли инстанса в репликации
+const (
+	ReplicationRolePrimary = "primary"
+	ReplicationRoleStandby = "standby"
+)
+
+// ReplicationStatus - состояние репликации инстанса
+// На primary заполнен Replicas, на standby - WalReceiver и Replay
+type ReplicationStatus struct {
+	Role        string           `json:"role"`         // primary или standby
+	Replicas    []ReplicaInfo    `json:"replicas"`     // подключённые реплики (pg_stat_replication)
+	WalReceiver *WalReceiverInfo `json:"wal_receiver"` // nil, если WAL receiver не запущен (например, восстановление из архива)
+	Replay      *StandbyReplay   `json:"replay"`       // только на standby
+}
+
+// ReplicaInfo - реплика, подключённая к primary
+type ReplicaInfo struct {
+	PID              int         `json:"pid"`
+	Username         NullString  `json:"username"`
+	ApplicationName  string      `json:"application_name"`
+	ClientAddr       NullString  `json:"client_addr"` // NULL при подключении через unix socket
+	State            NullString  `json:"state"`
+	SyncState        NullString  `json:"sync_state"`
+	SyncPriority     NullInt64   `json:"sync_priority"`
+	SentLsn          NullString  `json:"sent_lsn"`
+	WriteLsn         NullString  `json:"write_lsn"`
+	FlushLsn         NullString  `json:"flush_lsn"`
+	ReplayLsn        NullString  `json:"replay_lsn"`
+	SentLagBytes     NullInt64   `json:"sent_lag_bytes"`
+	WriteLagBytes    NullInt64   `json:"write_lag_bytes"`
+	FlushLagBytes    NullInt64   `json:"flush_lag_bytes"`
+	ReplayLagBytes   NullInt64   `json:"replay_lag_bytes"`
+	WriteLagSeconds  NullFloat64 `json:"write_lag_seconds"`
+	FlushLagSeconds  NullFloat64 `json:"flush_lag_seconds"`
+	ReplayLagSeconds NullFloat64 `json:"replay_lag_seconds"`
+	BackendStart     NullTime    `json:"backend_start"`
+	ReplyTime        NullTime    `json:"reply_time"` // только в PG ≥12
+}
+
+// WalReceiverInfo - состояние WAL receiver на standby
+type WalReceiverInfo struct {
+	PID                int        `json:"pid"`
+	Status             string     `json:"status"`
+	ReceiveStartLsn    NullString `json:"receive_start_lsn"`
+	WrittenLsn         NullString `json:"written_lsn"` // только в PG ≥13
+	FlushedLsn         NullString `json:"flushed_lsn"` // received_lsn в PG ≤12
+	ReceivedTli        NullInt64  `json:"received_tli"`
+	LatestEndLsn       NullString `json:"latest_end_lsn"`
+	LatestEndTime      NullTime   `json:"latest_end_time"`
+	LastMsgReceiptTime NullTime   `json:"last_msg_receipt_time"`
+	SlotName           NullString `json:"slot_name"`
+	SenderHost         NullString `json:"sender_host"` // только в PG ≥11, иначе см. conninfo
+	SenderPort         NullInt64  `json:"sender_port"` // только в PG ≥11
+	Conninfo           NullString `json:"conninfo"`    // пароль скрыт самим PostgreSQL
+}
+
+// StandbyReplay - прогресс применения WAL на standby
+type StandbyReplay struct {
+	ReceiveLsn         NullString  `json:"receive_lsn"`
+	ReplayLsn          NullString  `json:"replay_lsn"`
+	ReplayLagBytes     NullInt64   `json:"replay_lag_bytes"`     // получено, но ещё не применено
+	LastReplayTime     NullTime    `json:"last_replay_time"`     // время коммита последней применённой транзакции
+	ReplayDelaySeconds NullFloat64 `json:"replay_delay_seconds"` // 0, если всё полученное применено
+	ReplayPaused       bool        `json:"replay_paused"`
+}
+
+// ReplicationSlot - слот репликации и объём удерживаемого им WAL
+type ReplicationSlot struct {
+	SlotName          string     `json:"slot_name"`
+	Plugin            NullString `json:"plugin"` // только у логических слотов
+	SlotType          string     `json:"slot_type"`
+	Database          NullString `json:"database"`
+	Temporary         bool       `json:"temporary"`
+	Active            bool       `json:"active"`
+	ActivePID         NullInt64  `json:"active_pid"`
+	Xmin              NullString `json:"xmin"`
+	CatalogXmin       NullString `json:"catalog_xmin"`
+	RestartLsn        NullString `json:"restart_lsn"`
+	ConfirmedFlushLsn NullString `json:"confirmed_flush_lsn"`
+	RetainedWalBytes  NullInt64  `json:"retained_wal_bytes"`
+	WalStatus         NullString `json:"wal_status"`    // только в PG ≥13: reserved, extended, unreserved, lost
+	SafeWalSize       NullInt64  `json:"safe_wal_size"` // только в PG ≥13, NULL без max_slot_wal_keep_size
+	AtRisk            bool       `json:"at_risk"`       // неактивен и удерживает больше порога, либо WAL уже теряется
+}
+
+// WraparoundLimit - предел возраста XID/MultiXact (2^31), после которого наступает wraparound
+const WraparoundLimit int64 = 1 << 31
+
+// XidWraparound - возраст XID и MultiXact по базам инстанса и то, что держит xmin horizon
+type XidWraparound struct {
+	FreezeMaxAge          int64               `json:"autovacuum_freeze_max_age"`
+	MultixactFreezeMaxAge int64               `json:"autovacuum_multixact_freeze_max_age"`
+	WraparoundLimit       int64               `json:"wraparound_limit"`
+	Databases             []DatabaseXidAge    `json:"databases"`       // от самой старой к самой молодой
+	HorizonHolders        []XminHorizonHolder `json:"horizon_holders"` // от самого старого xmin
+}
+
+// DatabaseXidAge - возраст datfrozenxid и datminmxid базы
+type DatabaseXidAge struct {
+	DatabaseName        string  `json:"database_name"`
+	XidAge              int64   `json:"xid_age"`
+	MxidAge             int64   `json:"mxid_age"`
+	XidFreezeMaxAgePct  float64 `json:"xid_freeze_max_age_pct"` // >100% - anti-wraparound autovacuum уже должен работать
+	XidWraparoundPct    float64 `json:"xid_wraparound_pct"`
+	MxidFreezeMaxAgePct float64 `json:"mxid_freeze_max_age_pct"`
+	MxidWraparoundPct   float64 `json:"mxid_wraparound_pct"`
+}
+
+// XminHorizonHolder - бэкенд, подготовленная транзакция или слот, удерживающие xmin horizon
+type XminHorizonHolder struct {
+	Kind     string     `json:"kind"`   // backend, prepared_transaction или replication_slot
+	Holder   string     `json:"holder"` // PID, gid или имя слота
+	Username NullString `json:"username"`
+	Database NullString `json:"database"`
+	XminAge  NullInt64  `json:"xmin_age"`
+	State    NullString `json:"state"`
+	Since    NullTime   `json:"since"` // начало транзакции или момент PREPARE
+	Query    NullString `json:"query"`
+}
+
+// TableFreezeAge - возраст relfrozenxid и relminmxid таблицы
+type TableFreezeAge struct {
+	SchemaName         string   `json:"schema_name"`
+	TableName          string   `json:"table_name"`
+	RelKind            string   `json:"relkind"` // r - таблица, m - матпредставление, t - TOAST
+	XidAge             int64    `json:"xid_age"`
+	MxidAge            int64    `json:"mxid_age"`
+	XidFreezeMaxAgePct float64  `json:"xid_freeze_max_age_pct"`
+	XidWraparoundPct   float64  `json:"xid_wraparound_pct"`
+	TotalBytes         int64    `json:"total_bytes"`
+	LastVacuum         NullTime `json:"last_vacuum"`
+	LastAutovacuum     NullTime `json:"last_autovacuum"`
+}
+
+// MaintenanceProgress - ход VACUUM, ANALYZE, CREATE INDEX или CLUSTER
+type MaintenanceProgress struct {
+	Command        string      `json:"command"` // VACUUM, ANALYZE, CREATE INDEX [CONCURRENTLY], REINDEX [CONCURRENTLY], CLUSTER, VACUUM FULL
+	PID            int         `json:"pid"`
+	Database       NullString  `json:"database"`
+	RelID          int64       `json:"relid"`
+	Relation       NullString  `json:"relation"` // NULL, если базу не удалось открыть или таблицы уже нет
+	Phase          string      `json:"phase"`
+	BlocksTotal    NullInt64   `json:"blocks_total"` // блоки кучи, выборки ANALYZE или индекса - в зависимости от команды и фазы
+	BlocksDone     NullInt64   `json:"blocks_done"`
+	ProgressPct    NullFloat64 `json:"progress_pct"`
+	ElapsedSeconds NullFloat64 `json:"elapsed_seconds"`
+	Username       NullString  `json:"username"`
+	WaitEventType  NullString  `json:"wait_event_type"`
+	WaitEvent      NullString  `json:"wait_event"`
+	Query          NullString  `json:"query"`
+}
+
+// TableBloat - оценка потерянного места в таблице
+type TableBloat struct {
+	SchemaName     string    `json:"schema_name"`
+	TableName      string    `json:"table_name"`
+	RealBytes      int64     `json:"real_bytes"`
+	WastedBytes    int64     `json:"wasted_bytes"` // сверх места, зарезервированного fillfactor
+	BloatPct       float64   `json:"bloat_pct"`
+	Fillfactor     int       `json:"fillfactor"`
+	DeadTupleBytes NullInt64 `json:"dead_tuple_bytes"` // только в точном режиме
+	FreeBytes      NullInt64 `json:"free_bytes"`       // только в точном режиме
+}
+
+// IndexBloat - оценка потерянного места в btree-индексе
+type IndexBloat struct {
+	SchemaName     string      `json:"schema_name"`
+	TableName      string      `json:"table_name"`
+	IndexName      string      `json:"index_name"`
+	RealBytes      int64       `json:"real_bytes"`
+	WastedBytes    int64       `json:"wasted_bytes"`
+	BloatPct       float64     `json:"bloat_pct"`
+	Fillfactor     int         `json:"fillfactor"`
+	AvgLeafDensity NullFloat64 `json:"avg_leaf_density"` // только в точном режиме
+}
+
+// Виды находок советника по индексам
+const (
+	IndexFindingUnused      = "unused"
+	IndexFindingDuplicate   = "duplicate"
+	IndexFindingRedundant   = "redundant"
+	IndexFindingInvalid     = "invalid"
+	IndexFindingUnindexedFK = "unindexed_foreign_key"
+)
+
+// IndexFinding - проблема с индексом и предлагаемый DDL (ничего не выполняется)
+type IndexFinding struct {
+	Kind       string     `json:"kind"`
+	SchemaName string     `json:"schema_name"`
+	TableName  string     `json:"table_name"`
+	IndexName  NullString `json:"index_name"` // пусто для внешних ключей без индекса
+	Related    NullString `json:"related"`    // индекс, который остаётся, или имя внешнего ключа
+	SizeBytes  int64      `json:"size_bytes"` // размер индекса, для внешних ключей - размер таблицы
+	Definition string     `json:"definition"`
+	Reason     string     `json:"reason"`
+	Suggestion string     `json:"suggestion"`
+}
+
+// IndexAdvice - результат советника по индексам для одной БД
+type IndexAdvice struct {
+	StatsReset NullTime       `json:"stats_reset"` // с этого момента считаются сканирования для unused
+	Findings   []IndexFinding `json:"findings"`
+}
+
+// LockWaitEdge - ребро графа ожиданий: WaiterPID ждёт блокировку, которую держит (или ждёт раньше) BlockerPID
+type LockWaitEdge struct {
+	WaiterPID          int         `json:"waiter_pid"`
+	BlockerPID         int         `json:"blocker_pid"`
+	Depth              int         `json:"depth"` // расстояние ожидающего от корня, 0 - корень не найден (цикл)
+	LockType           string      `json:"lock_type"`
+	Relation           NullString  `json:"relation"`
+	WaiterMode         string      `json:"waiter_mode"`
+	BlockerMode        NullString  `json:"blocker_mode"`
+	BlockerGranted     bool        `json:"blocker_granted"` // false - блокирующий сам стоит в очереди раньше
+	Database           NullString  `json:"database"`
+	WaiterUsername     NullString  `json:"waiter_username"`
+	WaiterQuery        NullString  `json:"waiter_query"`
+	WaiterWaitSeconds  NullFloat64 `json:"waiter_wait_seconds"`
+	BlockerUsername    NullString  `json:"blocker_username"`
+	BlockerState       NullString  `json:"blocker_state"`
+	BlockerQuery       NullString  `json:"blocker_query"`
+	BlockerXactSeconds NullFloat64 `json:"blocker_xact_seconds"`
+}
+
+// LockRoot - корневой блокировщик: блокирует других, но сам ничего не ждёт
+type LockRoot struct {
+	PID           int         `json:"pid"`
+	Username      NullString  `json:"username"`
+	Database      NullString  `json:"database"`
+	State         NullString  `json:"state"`
+	Query         NullString  `json:"query"`
+	XactSeconds   NullFloat64 `json:"xact_seconds"`
+	DirectWaiters int         `json:"direct_waiters"`
+	TotalWaiters  int         `json:"total_waiters"` // все сессии, ждущие за этим корнем на любой глубине
+	MaxDepth      int         `json:"max_depth"`
+}
+
+// LockGraph - граф ожиданий блокировок
+type LockGraph struct {
+	Roots []LockRoot     `json:"roots"`
+	Edges []LockWaitEdge `json:"edges"`
+}
+
+// Backend - процесс, который собираются отменить или завершить
+type Backend struct {
+	PID             int        `json:"pid"`
+	Username        NullString `json:"username"`
+	Database        NullString `json:"database"`
+	ApplicationName NullString `json:"application_name"`
+	BackendType     NullString `json:"backend_type"` // только PG ≥10
+	State           NullString `json:"state"`
+	QueryStart      NullTime   `json:"query_start"`
+	Query           NullString `json:"query"`
+	Superuser       bool       `json:"superuser"`
+	ClientBackend   bool       `json:"client_backend"` // false для walsender и служебных процессов
+}
+
+// SchemaInfo - схема БД с числом объектов
+type SchemaInfo struct {
+	Name      string     `json:"name"`
+	Owner     string     `json:"owner"`
+	Tables    int        `json:"tables"`
+	Views     int        `json:"views"`
+	Functions int        `json:"functions"`
+	Comment   NullString `json:"comment"`
+}
+
+// ColumnInfo - колонка таблицы или представления
+type ColumnInfo struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Nullable bool       `json:"nullable"`
+	Default  NullString `json:"default"`
+	Comment  NullString `json:"comment"`
+}
+
+// TableSchema - таблица с колонками
+type TableSchema struct {
+	SchemaName    string       `json:"schema_name"`
+	TableName     string       `json:"table_name"`
+	Kind          string       `json:"kind"`           // table, partitioned table, foreign table, view, materialized view
+	EstimatedRows NullInt64    `json:"estimated_rows"` // reltuples, пусто если не было ANALYZE
+	TotalBytes    int64        `json:"total_bytes"`
+	Comment       NullString   `json:"comment"`
+	Columns       []ColumnInfo `json:"columns"`
+}
+
+// ConstraintInfo - ограничение таблицы
+type ConstraintInfo struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"` // primary_key, foreign_key, unique, check, exclusion
+	Columns    []string `json:"columns"`
+	Definition string   `json:"definition"`
+}
+
+// IndexDefinition - индекс таблицы с DDL
+type IndexDefinition struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+	Primary    bool   `json:"primary"`
+	Unique     bool   `json:"unique"`
+	Valid      bool   `json:"valid"`
+	SizeBytes  int64  `json:"size_bytes"`
+	// Constraint - ограничение (первичный ключ, уникальность, exclusion), которое обслуживает индекс
+	Constraint NullString `json:"constraint"`
+}
+
+// TableDetail - полное описание таблицы или представления
+type TableDetail struct {
+	TableSchema
+	Constraints    []ConstraintInfo  `json:"constraints"`
+	Indexes        []IndexDefinition `json:"indexes"`
+	ViewDefinition NullString        `json:"view_definition"` // только для представлений
+	// Секционирование (PG ≥10) и внешний сервер, заполняются только в снимке схемы
+	PartitionKey   NullString `json:"partition_key"`   // RANGE (...) секционированной таблицы
+	ParentSchema   NullString `json:"parent_schema"`   // родитель секции
+	ParentTable    NullString `json:"parent_table"`    // родитель секции
+	PartitionBound NullString `json:"partition_bound"` // FOR VALUES ... или DEFAULT секции
+	ForeignServer  NullString `json:"foreign_server"`  // сервер внешней таблицы
+	ForeignOptions NullString `json:"foreign_options"` // OPTIONS внешней таблицы без скобок
+}
+
+// ViewInfo - представление с определением
+type ViewInfo struct {
+	SchemaName   string     `json:"schema_name"`
+	ViewName     string     `json:"view_name"`
+	Materialized bool       `json:"materialized"`
+	Definition   string     `json:"definition"`
+	Comment      NullString `json:"comment"`
+}
+
+// FunctionInfo - функция или процедура
+type FunctionInfo struct {
+	SchemaName string     `json:"schema_name"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"` // function, procedure, aggregate, window
+	Arguments  string     `json:"arguments"`
+	ReturnType NullString `json:"return_type"` // пусто для процедур
+	Language   string     `json:"language"`
+	Volatility string     `json:"volatility"`
+	Comment    NullString `json:"comment"`
+}
+
+// ExtensionInfo - установленное расширение
+type ExtensionInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Schema  string `json:"schema"`
+}
+
+// SchemaSnapshot - таблицы базы с колонками, ограничениями и индексами и установленные расширения
+type SchemaSnapshot struct {
+	Tables     []TableDetail   `json:"tables"`
+	Extensions []ExtensionInfo `json:"extensions"`
+}
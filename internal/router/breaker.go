@@ -0,0 +1,199 @@
+package router
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type BreakerState string
+
+const (
+	BreakerStateClosed   BreakerState = "closed"
+	BreakerStateOpen     BreakerState = "open"
+	BreakerStateHalfOpen BreakerState = "half_open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerStatus is a point-in-time view of an instance circuit breaker
+type BreakerStatus struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	FailureThreshold    int          `json:"failure_threshold"`
+	LastError           string       `json:"last_error,omitempty"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	NextProbeAt         *time.Time   `json:"next_probe_at,omitempty"`
+}
+
+// circuitBreaker trips after a run of consecutive connection failures and
+// fails fast until the open timeout elapses. After that a single probe request
+// is let through (half-open): its success closes the breaker, its failure
+// re-opens it for another timeout period.
+type circuitBreaker struct {
+	instance      string
+	threshold     int
+	openTimeout   time.Duration
+	state         BreakerState
+	failures      int
+	lastError     string
+	openedAt      time.Time
+	probeInFlight bool
+	now           func() time.Time
+	mu            sync.Mutex
+}
+
+func newCircuitBreaker(instance string, threshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		instance:    instance,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		state:       BreakerStateClosed,
+		now:         time.Now,
+	}
+}
+
+// allow reports whether a request may proceed. A nil error in half-open state
+// means the caller owns the probe and must report its outcome via record.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerStateOpen:
+		retryAt := b.openedAt.Add(b.openTimeout)
+		if b.now().Before(retryAt) {
+			return fmt.Errorf("%w for instance %s after %d consecutive failures (last error: %s), next probe in %s",
+				ErrCircuitOpen, b.instance, b.failures, b.lastError, retryAt.Sub(b.now()).Round(time.Second))
+		}
+		b.state = BreakerStateHalfOpen
+		b.probeInFlight = true
+		return nil
+
+	case BreakerStateHalfOpen:
+		if b.probeInFlight {
+			return fmt.Errorf("%w for instance %s: probe request in progress", ErrCircuitOpen, b.instance)
+		}
+		b.probeInFlight = true
+		return nil
+	}
+
+	return nil
+}
+
+// record registers the outcome of a request that passed allow. A half-open probe
+// proves the instance healthy only by succeeding or by an error the server itself
+// reported; anything else, a timeout against a hung instance included, re-opens the breaker.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probeInFlight = false
+
+	failed := isConnectionFailure(err)
+	if b.state == BreakerStateHalfOpen && err != nil && !isServerError(err) {
+		failed = true
+	}
+
+	if !failed {
+		b.state = BreakerStateClosed
+		b.failures = 0
+		b.lastError = ""
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+
+	if b.state == BreakerStateHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerStateOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *circuitBreaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.threshold,
+		LastError:           b.lastError,
+	}
+	if b.state != BreakerStateClosed {
+		openedAt := b.openedAt
+		nextProbeAt := b.openedAt.Add(b.openTimeout)
+		status.OpenedAt = &openedAt
+		status.NextProbeAt = &nextProbeAt
+	}
+
+	return status
+}
+
+// errClientNotFound marks a missing registry client; it counts as a connection failure
+var errClientNotFound = errors.New("client not found")
+
+// IsUnavailable reports whether err means the instance could not serve a request at
+// all: it is unreachable, its breaker is open, it has no free slot or the call timed
+// out. Such an error says nothing about the request itself.
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) ||
+		errors.Is(err, ErrInstanceBusy) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		isConnectionFailure(err)
+}
+
+// isConnectionFailure distinguishes an unreachable instance from errors
+// reported by a live server (SQL errors, unsupported features, bad parameters).
+// Timeouts count only while dialing: a slow query timing out on a healthy
+// instance must not open the breaker for every caller.
+func isConnectionFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, errClientNotFound) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial" || !opErr.Timeout()
+	}
+
+	// context.DeadlineExceeded is a net.Error as well
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return !netErr.Timeout()
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// 08 - connection exception, 57P0x - server shutting down or starting up,
+		// 53300 - too many connections
+		return pqErr.Code.Class() == "08" ||
+			strings.HasPrefix(string(pqErr.Code), "57P0") ||
+			pqErr.Code == "53300"
+	}
+
+	return false
+}
+
+// isServerError reports whether err is an SQL-level error returned by a live server
+func isServerError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr)
+}
@@ -0,0 +1,210 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+	routermocks "psql-mcp-registry/internal/router/mocks"
+)
+
+func TestRouter_RouteQuery_BreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "down-instance"}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient).Times(3)
+	mockClient.On("GetConnectionStats", ctx).Return((*pg.ConnectionSummary)(nil), dialErr).Times(3)
+
+	router := NewWithConfig(mockRegistry, breakerConfig(3, time.Minute))
+	req := QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}
+
+	for i := 0; i < 3; i++ {
+		_, err := router.RouteQuery(ctx, req, instance)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+
+	// Fourth call must fail fast without reaching the client
+	response, err := router.RouteQuery(ctx, req, instance)
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, response.Success)
+	assert.Contains(t, response.Error, "down-instance")
+	assert.Contains(t, response.Error, "connection refused")
+
+	status := router.BreakerStatus(instance.Name)
+	assert.Equal(t, BreakerStateOpen, status.State)
+	assert.Equal(t, 3, status.ConsecutiveFailures)
+	assert.NotNil(t, status.NextProbeAt)
+}
+
+func TestRouter_RouteQuery_BreakerHalfOpenProbeCloses(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "flaky-instance"}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetConnectionStats", ctx).Return((*pg.ConnectionSummary)(nil), dialErr).Once()
+	mockClient.On("GetConnectionStats", ctx).Return(&pg.ConnectionSummary{TotalConnections: 1}, nil).Once()
+
+	router := NewWithConfig(mockRegistry, breakerConfig(1, 20*time.Millisecond))
+	req := QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}
+
+	_, err := router.RouteQuery(ctx, req, instance)
+	require.Error(t, err)
+	assert.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+
+	time.Sleep(30 * time.Millisecond)
+
+	response, err := router.RouteQuery(ctx, req, instance)
+
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, BreakerStateClosed, router.BreakerStatus(instance.Name).State)
+	assert.Equal(t, 0, router.BreakerStatus(instance.Name).ConsecutiveFailures)
+}
+
+func TestRouter_RouteQuery_BreakerHalfOpenProbeTimeoutReopens(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "hung-instance"}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	sqlErr := &pq.Error{Code: "42P01", Message: "relation does not exist"}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetConnectionStats", ctx).Return((*pg.ConnectionSummary)(nil), dialErr).Once()
+	mockClient.On("GetConnectionStats", ctx).
+		Return((*pg.ConnectionSummary)(nil), fmt.Errorf("failed to query connection stats: %w", context.DeadlineExceeded)).Once()
+	mockClient.On("GetConnectionStats", ctx).Return((*pg.ConnectionSummary)(nil), sqlErr).Once()
+
+	router := NewWithConfig(mockRegistry, breakerConfig(1, 20*time.Millisecond))
+	req := QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}
+
+	_, err := router.RouteQuery(ctx, req, instance)
+	require.Error(t, err)
+	assert.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// A probe that times out says nothing good about the instance
+	_, err = router.RouteQuery(ctx, req, instance)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+	assert.Equal(t, 2, router.BreakerStatus(instance.Name).ConsecutiveFailures)
+
+	_, err = router.RouteQuery(ctx, req, instance)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// An SQL error comes from a live server, so it closes the breaker
+	_, err = router.RouteQuery(ctx, req, instance)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, BreakerStateClosed, router.BreakerStatus(instance.Name).State)
+}
+
+func TestRouter_RouteQuery_ServerErrorsDoNotTripBreaker(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "live-instance"}
+	sqlErr := &pq.Error{Code: "42P01", Message: "relation does not exist"}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetConnectionStats", ctx).Return((*pg.ConnectionSummary)(nil), sqlErr)
+
+	router := NewWithConfig(mockRegistry, breakerConfig(1, time.Minute))
+	req := QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}
+
+	for i := 0; i < 3; i++ {
+		_, err := router.RouteQuery(ctx, req, instance)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+
+	assert.Equal(t, BreakerStateClosed, router.BreakerStatus(instance.Name).State)
+}
+
+func TestRouter_RouteQuery_QueryTimeoutsDoNotTripBreaker(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "slow-instance"}
+	readTimeout := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	dialTimeout := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetTablesInfo", mock.Anything, 200).
+		Return(nil, fmt.Errorf("failed to query tables info: %w", context.DeadlineExceeded)).Once()
+	mockClient.On("GetTablesInfo", mock.Anything, 200).Return(nil, readTimeout).Once()
+	mockClient.On("GetTablesInfo", mock.Anything, 200).Return(nil, dialTimeout).Once()
+
+	router := NewWithConfig(mockRegistry, breakerConfig(1, time.Minute))
+	req := QueryRequest{InstanceName: instance.Name, Action: model.ActionNameTablesInfo}
+
+	for i := 0; i < 2; i++ {
+		_, err := router.RouteQuery(ctx, req, instance)
+		require.Error(t, err)
+		assert.Equal(t, BreakerStateClosed, router.BreakerStatus(instance.Name).State)
+	}
+
+	// Timing out while connecting still means the instance is unreachable
+	_, err := router.RouteQuery(ctx, req, instance)
+	require.Error(t, err)
+	assert.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+}
+
+func TestRouter_RouteQuery_InstanceHealthReportsOpenBreaker(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "down-instance"}
+
+	mockRegistry := routermocks.NewRegistry(t)
+	mockRegistry.On("GetInstanceClient", instance).Return(nil).Once()
+
+	router := NewWithConfig(mockRegistry, breakerConfig(1, time.Minute))
+
+	response, err := router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameInstanceHealth}, instance)
+	require.NoError(t, err)
+
+	health, ok := response.Data.(*InstanceHealth)
+	require.True(t, ok, "response.Data should be *InstanceHealth")
+	assert.False(t, health.Reachable)
+	assert.Equal(t, BreakerStateOpen, health.Breaker.State)
+
+	// Second health call does not touch the registry while the breaker is open
+	response, err = router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameInstanceHealth}, instance)
+	require.NoError(t, err)
+
+	health = response.Data.(*InstanceHealth)
+	assert.Contains(t, health.Error, ErrCircuitOpen.Error())
+}
+
+func breakerConfig(threshold int, openTimeout time.Duration) *Config {
+	config := DefaultConfig()
+	config.BreakerFailureThreshold = threshold
+	config.BreakerOpenTimeout = openTimeout
+	return config
+}
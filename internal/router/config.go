@@ -0,0 +1,75 @@
+package router
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"psql-mcp-registry/internal/healthreport"
+)
+
+// Config holds per-instance resilience settings of the router
+type Config struct {
+	// BreakerFailureThreshold is the number of consecutive connection failures
+	// after which the instance circuit breaker opens
+	BreakerFailureThreshold int
+	// BreakerOpenTimeout is how long the breaker stays open before a half-open probe is allowed
+	BreakerOpenTimeout time.Duration
+	// LightSlots and HeavySlots cap concurrent light and heavy actions per instance;
+	// together they should stay below the client pool size (pg.Config.MaxOpenConns)
+	LightSlots int
+	HeavySlots int
+	// MaxQueueDepth is how many requests may wait for a slot of each class
+	// before new ones are rejected as busy
+	MaxQueueDepth int
+	// HealthRules are the default thresholds of health_report; a request may pass its own
+	HealthRules healthreport.Rules
+}
+
+// DefaultConfig returns the default router configuration
+func DefaultConfig() *Config {
+	return &Config{
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      30 * time.Second,
+		LightSlots:              6,
+		HeavySlots:              3,
+		MaxQueueDepth:           20,
+		HealthRules:             healthreport.DefaultRules(),
+	}
+}
+
+// LoadConfigFromEnv loads the router configuration from environment variables
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	if threshold := os.Getenv("ROUTER_BREAKER_FAILURE_THRESHOLD"); threshold != "" {
+		if t, err := strconv.Atoi(threshold); err == nil && t > 0 {
+			cfg.BreakerFailureThreshold = t
+		}
+	}
+	if timeout := os.Getenv("ROUTER_BREAKER_OPEN_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
+			cfg.BreakerOpenTimeout = d
+		}
+	}
+
+	if slots := os.Getenv("ROUTER_LIGHT_SLOTS"); slots != "" {
+		if s, err := strconv.Atoi(slots); err == nil && s > 0 {
+			cfg.LightSlots = s
+		}
+	}
+	if slots := os.Getenv("ROUTER_HEAVY_SLOTS"); slots != "" {
+		if s, err := strconv.Atoi(slots); err == nil && s > 0 {
+			cfg.HeavySlots = s
+		}
+	}
+	if depth := os.Getenv("ROUTER_MAX_QUEUE_DEPTH"); depth != "" {
+		if d, err := strconv.Atoi(depth); err == nil && d >= 0 {
+			cfg.MaxQueueDepth = d
+		}
+	}
+
+	cfg.HealthRules = healthreport.LoadRulesFromEnv()
+
+	return cfg
+}
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"context"
+	"sync"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// Querier runs an action on several instances; the background jobs take it instead of a *Router
+type Querier interface {
+	RouteAll(ctx context.Context, req QueryRequest, instances []model.Instance) []*QueryResponse
+}
+
+// InstanceLister returns the registered instances
+type InstanceLister interface {
+	ListInstances(ctx context.Context) ([]model.Instance, error)
+}
+
+// RouteAll runs the request on every given instance concurrently. Each call goes
+// through the breaker and slots of its own instance, so a down or busy instance
+// only fails its own response. Responses keep the order of instances.
+func (r *Router) RouteAll(ctx context.Context, req QueryRequest, instances []model.Instance) []*QueryResponse {
+	responses := make([]*QueryResponse, len(instances))
+
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			instanceReq := req
+			instanceReq.InstanceName = instance.Name
+			// The error is already recorded in the response
+			responses[i], _ = r.RouteQuery(ctx, instanceReq, instance)
+		}()
+	}
+	wg.Wait()
+
+	return responses
+}
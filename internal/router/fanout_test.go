@@ -0,0 +1,50 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+	routermocks "psql-mcp-registry/internal/router/mocks"
+)
+
+func TestRouter_RouteAll_CollectsPerInstanceResults(t *testing.T) {
+	ctx := context.Background()
+
+	primary := model.Instance{Name: "primary"}
+	broken := model.Instance{Name: "broken"}
+
+	primaryClient := pgmocks.NewClientInterface(t)
+	brokenClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", primary).Return(primaryClient)
+	mockRegistry.On("GetInstanceClient", broken).Return(brokenClient)
+
+	slots := []pg.ReplicationSlot{{SlotName: "standby_1", SlotType: "physical", Active: true}}
+	primaryClient.On("GetReplicationSlots", ctx, int64(512*1024*1024)).Return(slots, nil)
+	brokenClient.On("GetReplicationSlots", ctx, int64(512*1024*1024)).Return(nil, errors.New("permission denied"))
+
+	router := New(mockRegistry)
+
+	req := QueryRequest{
+		Action:     model.ActionNameReplicationSlots,
+		Parameters: map[string]interface{}{"retainedWalThresholdMB": 512},
+	}
+
+	responses := router.RouteAll(ctx, req, []model.Instance{primary, broken})
+
+	assert.Len(t, responses, 2)
+
+	assert.Equal(t, primary.Name, responses[0].Instance)
+	assert.True(t, responses[0].Success)
+	assert.Equal(t, slots, responses[0].Data)
+
+	assert.Equal(t, broken.Name, responses[1].Instance)
+	assert.False(t, responses[1].Success)
+	assert.Contains(t, responses[1].Error, "permission denied")
+}
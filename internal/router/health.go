@@ -0,0 +1,52 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+)
+
+// InstanceHealth describes reachability of a registered instance as seen by the router
+type InstanceHealth struct {
+	Instance      string        `json:"instance"`
+	Reachable     bool          `json:"reachable"`
+	PingLatencyMs float64       `json:"ping_latency_ms,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Version       *pg.Version   `json:"version,omitempty"`
+	Breaker       BreakerStatus `json:"breaker"`
+	Load          LimiterStatus `json:"load"`
+}
+
+func (r *Router) instanceHealth(ctx context.Context, instance model.Instance) *InstanceHealth {
+	health := &InstanceHealth{Instance: instance.Name}
+	guard := r.guard(instance.Name)
+	breaker := guard.breaker
+
+	// The ping goes through the breaker: it is skipped while open and acts as the probe when half-open
+	if err := breaker.allow(); err != nil {
+		health.Error = err.Error()
+	} else {
+		client := r.registry.GetInstanceClient(instance)
+		if client == nil {
+			err = errClientNotFound
+		} else {
+			started := time.Now()
+			err = client.Ping(ctx)
+			health.PingLatencyMs = float64(time.Since(started).Microseconds()) / 1000
+			health.Version = client.Version()
+		}
+		breaker.record(err)
+
+		if err != nil {
+			health.Error = err.Error()
+		} else {
+			health.Reachable = true
+		}
+	}
+
+	health.Breaker = breaker.status()
+	health.Load = guard.limiter.status()
+	return health
+}
@@ -0,0 +1,168 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"psql-mcp-registry/internal/model"
+)
+
+var ErrInstanceBusy = errors.New("instance is busy")
+
+type SlotClass string
+
+const (
+	SlotClassLight SlotClass = "light"
+	SlotClassHeavy SlotClass = "heavy"
+)
+
+// heavyActions scan catalogs or whole statistics views and may hold a
+// connection for a long time; they get their own slots so cheap calls are not starved.
+// A slot is held for the whole action and bounds one connection: health_report runs
+// its checks one at a time and wait_profile keeps its slot for its entire duration.
+var heavyActions = map[model.ActionName]bool{
+	model.ActionNameTablesInfo:         true,
+	model.ActionNameIndexStats:         true,
+	model.ActionNameIOStats:            true,
+	model.ActionNameSlowQueries:        true,
+	model.ActionNameTopStatements:      true,
+	model.ActionNameStatementsSnapshot: true,
+	model.ActionNameDatabaseSizes:      true,
+	model.ActionNameTableFreezeAge:     true,
+	model.ActionNameTableBloat:         true,
+	model.ActionNameIndexBloat:         true,
+	model.ActionNameIndexAdvisor:       true,
+	model.ActionNameListSchemas:        true,
+	model.ActionNameListTables:         true,
+	model.ActionNameListViews:          true,
+	model.ActionNameListFunctions:      true,
+	model.ActionNameHealthReport:       true,
+	model.ActionNameWaitProfile:        true,
+}
+
+func slotClassOf(action model.ActionName) SlotClass {
+	if heavyActions[action] {
+		return SlotClassHeavy
+	}
+	return SlotClassLight
+}
+
+// SlotPoolStatus is a point-in-time view of one slot pool
+type SlotPoolStatus struct {
+	Slots    int `json:"slots"`
+	InUse    int `json:"in_use"`
+	Queued   int `json:"queued"`
+	MaxQueue int `json:"max_queue"`
+}
+
+// LimiterStatus reports the concurrency state of an instance
+type LimiterStatus struct {
+	Light SlotPoolStatus `json:"light"`
+	Heavy SlotPoolStatus `json:"heavy"`
+}
+
+// slotPool is a counting semaphore with a bounded wait queue
+type slotPool struct {
+	slots    chan struct{}
+	maxQueue int
+	queued   int
+	mu       sync.Mutex
+}
+
+func newSlotPool(slots, maxQueue int) *slotPool {
+	return &slotPool{
+		slots:    make(chan struct{}, slots),
+		maxQueue: maxQueue,
+	}
+}
+
+func (p *slotPool) acquire(ctx context.Context) (bool, error) {
+	select {
+	case p.slots <- struct{}{}:
+		return true, nil
+	default:
+	}
+
+	p.mu.Lock()
+	if p.queued >= p.maxQueue {
+		p.mu.Unlock()
+		return false, nil
+	}
+	p.queued++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.queued--
+		p.mu.Unlock()
+	}()
+
+	select {
+	case p.slots <- struct{}{}:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (p *slotPool) release() {
+	<-p.slots
+}
+
+func (p *slotPool) status() SlotPoolStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return SlotPoolStatus{
+		Slots:    cap(p.slots),
+		InUse:    len(p.slots),
+		Queued:   p.queued,
+		MaxQueue: p.maxQueue,
+	}
+}
+
+// instanceLimiter caps concurrent queries against one instance
+type instanceLimiter struct {
+	instance string
+	light    *slotPool
+	heavy    *slotPool
+}
+
+func newInstanceLimiter(instance string, config *Config) *instanceLimiter {
+	return &instanceLimiter{
+		instance: instance,
+		light:    newSlotPool(config.LightSlots, config.MaxQueueDepth),
+		heavy:    newSlotPool(config.HeavySlots, config.MaxQueueDepth),
+	}
+}
+
+// acquire takes a slot of the action's class, waiting in the queue if needed.
+// It fails fast with ErrInstanceBusy when the queue is already full.
+func (l *instanceLimiter) acquire(ctx context.Context, action model.ActionName) (func(), error) {
+	class := slotClassOf(action)
+	pool := l.light
+	if class == SlotClassHeavy {
+		pool = l.heavy
+	}
+
+	ok, err := pool.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for %s slot on instance %s: %w", class, l.instance, err)
+	}
+	if !ok {
+		status := pool.status()
+		return nil, fmt.Errorf("%w: instance %s has all %d %s slots in use and %d requests queued, retry later",
+			ErrInstanceBusy, l.instance, status.Slots, class, status.Queued)
+	}
+
+	return pool.release, nil
+}
+
+func (l *instanceLimiter) status() LimiterStatus {
+	return LimiterStatus{
+		Light: l.light.status(),
+		Heavy: l.heavy.status(),
+	}
+}
@@ -0,0 +1,169 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+	routermocks "psql-mcp-registry/internal/router/mocks"
+)
+
+func TestRouter_RouteQuery_HeavyActionsRejectedWhenQueueFull(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "busy-instance"}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetTablesInfo", mock.Anything, 200).
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-unblock
+		}).
+		Return([]pg.TableInfo{}, nil).Once()
+	mockClient.On("GetConnectionStats", ctx).Return(&pg.ConnectionSummary{TotalConnections: 3}, nil).Once()
+
+	config := DefaultConfig()
+	config.HeavySlots = 1
+	config.MaxQueueDepth = 0
+	router := NewWithConfig(mockRegistry, config)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameTablesInfo}, instance)
+		done <- err
+	}()
+	<-started
+
+	// The only heavy slot is taken and nothing may queue
+	response, err := router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameTablesInfo}, instance)
+	assert.ErrorIs(t, err, ErrInstanceBusy)
+	assert.Contains(t, response.Error, "heavy")
+
+	// Light actions have their own slots
+	response, err = router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}, instance)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+
+	status := router.LimiterStatus(instance.Name)
+	assert.Equal(t, 1, status.Heavy.InUse)
+	assert.Equal(t, 0, status.Light.InUse)
+
+	close(unblock)
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, router.LimiterStatus(instance.Name).Heavy.InUse)
+}
+
+func TestRouter_RouteQuery_QueuedRequestRespectsContext(t *testing.T) {
+	instance := model.Instance{Name: "busy-instance"}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetConnectionStats", mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-unblock
+		}).
+		Return(&pg.ConnectionSummary{}, nil).Once()
+
+	config := DefaultConfig()
+	config.LightSlots = 1
+	config.MaxQueueDepth = 1
+	router := NewWithConfig(mockRegistry, config)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = router.RouteQuery(context.Background(), QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}, instance)
+		close(done)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}, instance)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, router.LimiterStatus(instance.Name).Light.Queued)
+
+	// Queue timeouts are not instance failures
+	assert.Equal(t, BreakerStateClosed, router.BreakerStatus(instance.Name).State)
+
+	close(unblock)
+	<-done
+}
+
+func TestRouter_RouteQuery_ControlActionsBypassLimiterAndBreaker(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "overloaded-instance"}
+	queryStart := time.Date(2025, 10, 20, 12, 30, 15, 0, time.UTC)
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	started := make(chan struct{}, 2)
+	unblock := make(chan struct{})
+	hold := func(mock.Arguments) {
+		started <- struct{}{}
+		<-unblock
+	}
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetConnectionStats", mock.Anything).Run(hold).Return(&pg.ConnectionSummary{}, nil).Once()
+	mockClient.On("GetTablesInfo", mock.Anything, 200).Run(hold).Return([]pg.TableInfo{}, nil).Once()
+	mockClient.On("CancelBackend", ctx, 4242, queryStart).Return(true, nil).Once()
+
+	config := breakerConfig(1, time.Minute)
+	config.LightSlots = 1
+	config.HeavySlots = 1
+	config.MaxQueueDepth = 0
+	router := NewWithConfig(mockRegistry, config)
+
+	done := make(chan struct{}, 2)
+	for _, action := range []model.ActionName{model.ActionNameConnectionStats, model.ActionNameTablesInfo} {
+		go func() {
+			_, _ = router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: action}, instance)
+			done <- struct{}{}
+		}()
+	}
+	<-started
+	<-started
+
+	// Every slot is held and the breaker is open
+	router.guard(instance.Name).breaker.record(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	require.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+
+	_, err := router.RouteQuery(ctx, QueryRequest{InstanceName: instance.Name, Action: model.ActionNameConnectionStats}, instance)
+	assert.ErrorIs(t, err, ErrInstanceBusy)
+
+	response, err := router.RouteQuery(ctx, QueryRequest{
+		InstanceName: instance.Name,
+		Action:       model.ActionNameCancelBackend,
+		Parameters:   map[string]interface{}{"pid": 4242, "queryStart": queryStart.Format(time.RFC3339Nano)},
+	}, instance)
+
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, true, response.Data)
+	assert.Equal(t, BreakerStateOpen, router.BreakerStatus(instance.Name).State)
+
+	close(unblock)
+	<-done
+	<-done
+}
@@ -14,4 +14,6 @@ type QueryResponse struct {
 	Success  bool             `json:"success"`
 	Data     interface{}      `json:"data,omitempty"`
 	Error    string           `json:"error,omitempty"`
+	// Err is the error behind Error, for callers that need to tell failures apart
+	Err error `json:"-"`
 }
@@ -2,14 +2,29 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"psql-mcp-registry/internal/healthreport"
 	"psql-mcp-registry/internal/model"
 	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/statements"
+	"psql-mcp-registry/internal/waitprofile"
 )
 
 type Router struct {
 	registry Registry
+	config   *Config
+	guards   map[string]*instanceGuard
+	mu       sync.Mutex
+}
+
+// instanceGuard holds the per-instance resilience state
+type instanceGuard struct {
+	breaker *circuitBreaker
+	limiter *instanceLimiter
 }
 
 //go:generate mockery --case snake --name Registry
@@ -19,25 +34,222 @@ type Registry interface {
 }
 
 func New(registry Registry) *Router {
-	return &Router{registry: registry}
+	return NewWithConfig(registry, DefaultConfig())
 }
 
-func (r *Router) RouteQuery(ctx context.Context, req QueryRequest, instance model.Instance) (*QueryResponse, error) {
-	client := r.registry.GetInstanceClient(instance)
-	if client == nil {
-		return &QueryResponse{
-			Instance: instance.Name,
-			Action:   req.Action,
-			Success:  false,
-			Error:    fmt.Sprintf("client not found for instance: %s", instance.Name),
-		}, fmt.Errorf("client not found for instance: %s", instance.Name)
+func NewWithConfig(registry Registry, config *Config) *Router {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Router{
+		registry: registry,
+		config:   config,
+		guards:   make(map[string]*instanceGuard),
 	}
+}
 
+// controlActions relieve an overloaded instance, so they bypass the limiter and the
+// circuit breaker that would refuse them exactly then. They use the pool connections
+// the slots leave free and their outcome does not count towards the breaker.
+var controlActions = map[model.ActionName]bool{
+	model.ActionNameBackendInfo:      true,
+	model.ActionNameCancelBackend:    true,
+	model.ActionNameTerminateBackend: true,
+}
+
+func (r *Router) RouteQuery(ctx context.Context, req QueryRequest, instance model.Instance) (*QueryResponse, error) {
 	response := &QueryResponse{
 		Instance: instance.Name,
 		Action:   req.Action,
 		Success:  false,
 	}
+	fail := func(err error) (*QueryResponse, error) {
+		response.Error = err.Error()
+		response.Err = err
+		return response, err
+	}
+
+	// Health is served outside the breaker so that its state stays observable while open
+	if req.Action == model.ActionNameInstanceHealth {
+		response.Success = true
+		response.Data = r.instanceHealth(ctx, instance)
+		return response, nil
+	}
+
+	if controlActions[req.Action] {
+		data, err := r.execute(ctx, req, instance)
+		if err != nil {
+			return fail(err)
+		}
+		response.Success = true
+		response.Data = data
+		return response, nil
+	}
+
+	guard := r.guard(instance.Name)
+
+	release, err := guard.limiter.acquire(ctx, req.Action)
+	if err != nil {
+		return fail(err)
+	}
+	defer release()
+
+	if err := guard.breaker.allow(); err != nil {
+		return fail(err)
+	}
+
+	data, err := r.execute(ctx, req, instance)
+	guard.breaker.record(err)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	response.Success = true
+	response.Data = data
+	return response, nil
+}
+
+// BreakerStatus returns the circuit breaker state of the given instance
+func (r *Router) BreakerStatus(instanceName string) BreakerStatus {
+	return r.guard(instanceName).breaker.status()
+}
+
+// HealthRules returns a copy of the default health_report rules
+func (r *Router) HealthRules() healthreport.Rules {
+	if r.config.HealthRules.Thresholds == nil {
+		return healthreport.DefaultRules()
+	}
+	return r.config.HealthRules.Clone()
+}
+
+// LimiterStatus returns slot usage and queue depth of the given instance
+func (r *Router) LimiterStatus(instanceName string) LimiterStatus {
+	return r.guard(instanceName).limiter.status()
+}
+
+func (r *Router) guard(instanceName string) *instanceGuard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	guard, exists := r.guards[instanceName]
+	if !exists {
+		guard = &instanceGuard{
+			breaker: newCircuitBreaker(instanceName, r.config.BreakerFailureThreshold, r.config.BreakerOpenTimeout),
+			limiter: newInstanceLimiter(instanceName, r.config),
+		}
+		r.guards[instanceName] = guard
+	}
+
+	return guard
+}
+
+// ErrUnsupportedAction is returned for an action the router does not serve
+var ErrUnsupportedAction = errors.New("unsupported action")
+
+// routedActions are the actions served by RouteQuery; keep in sync with execute
+var routedActions = map[model.ActionName]bool{
+	model.ActionNameInstanceHealth:      true,
+	model.ActionNameDatabaseOverview:    true,
+	model.ActionNameCacheHitRate:        true,
+	model.ActionNameCheckpointsStats:    true,
+	model.ActionNameWalActivity:         true,
+	model.ActionNameIOStats:             true,
+	model.ActionNameTablesInfo:          true,
+	model.ActionNameLockingInfo:         true,
+	model.ActionNameChangedSettings:     true,
+	model.ActionNameSettings:            true,
+	model.ActionNameVersion:             true,
+	model.ActionNameIndexStats:          true,
+	model.ActionNameActiveQueries:       true,
+	model.ActionNameConnectionStats:     true,
+	model.ActionNameActivitySample:      true,
+	model.ActionNameWaitProfile:         true,
+	model.ActionNameSlowQueries:         true,
+	model.ActionNameTopStatements:       true,
+	model.ActionNameStatementsSnapshot:  true,
+	model.ActionNameDatabaseSizes:       true,
+	model.ActionNameReplicationStatus:   true,
+	model.ActionNameReplicationSlots:    true,
+	model.ActionNameXidWraparound:       true,
+	model.ActionNameTableFreezeAge:      true,
+	model.ActionNameMaintenanceProgress: true,
+	model.ActionNameTableBloat:          true,
+	model.ActionNameIndexBloat:          true,
+	model.ActionNameIndexAdvisor:        true,
+	model.ActionNameLockGraph:           true,
+	model.ActionNameBackendInfo:         true,
+	model.ActionNameCancelBackend:       true,
+	model.ActionNameTerminateBackend:    true,
+	model.ActionNameListSchemas:         true,
+	model.ActionNameListTables:          true,
+	model.ActionNameDescribeTable:       true,
+	model.ActionNameListViews:           true,
+	model.ActionNameListFunctions:       true,
+	model.ActionNameSchemaSnapshot:      true,
+	model.ActionNameHealthReport:        true,
+}
+
+// SupportsAction reports whether the router serves the action
+func SupportsAction(action model.ActionName) bool {
+	return routedActions[action]
+}
+
+// toolParameters maps the snake_case input names of the MCP tools to the parameters
+// the router reads. Parameters only state-changing actions take are left out.
+var toolParameters = map[string]string{
+	"db_name":                   "dbName",
+	"limit":                     "limit",
+	"offset":                    "offset",
+	"schema":                    "schema",
+	"table":                     "table",
+	"exact":                     "exact",
+	"min_duration_seconds":      "minDuration",
+	"interval_ms":               "intervalMs",
+	"duration_seconds":          "durationSeconds",
+	"order_by":                  "orderBy",
+	"database":                  "database",
+	"user":                      "user",
+	"retained_wal_threshold_mb": "retainedWalThresholdMB",
+	"holders_limit":             "holdersLimit",
+}
+
+// IsParameter reports whether the router reads the parameter name
+func IsParameter(name string) bool {
+	for _, parameter := range toolParameters {
+		if parameter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeParameters renames the tool input names in params to the parameters the
+// router reads; router parameter names are kept as they are, anything else is an error
+func NormalizeParameters(params map[string]interface{}) (map[string]interface{}, error) {
+	normalized := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		parameter, ok := toolParameters[name]
+		if !ok {
+			if !IsParameter(name) {
+				return nil, fmt.Errorf("unknown parameter %q", name)
+			}
+			parameter = name
+		}
+		if _, duplicate := normalized[parameter]; duplicate {
+			return nil, fmt.Errorf("parameter %q is given twice", parameter)
+		}
+		normalized[parameter] = value
+	}
+	return normalized, nil
+}
+
+func (r *Router) execute(ctx context.Context, req QueryRequest, instance model.Instance) (interface{}, error) {
+	client := r.registry.GetInstanceClient(instance)
+	if client == nil {
+		return nil, fmt.Errorf("%w for instance: %s", errClientNotFound, instance.Name)
+	}
 
 	var err error
 	var data interface{}
@@ -60,6 +272,10 @@ func (r *Router) RouteQuery(ctx context.Context, req QueryRequest, instance mode
 	case model.ActionNameWalActivity:
 		data, err = client.GetWalActivity(ctx)
 
+	case model.ActionNameIOStats:
+		limit := getIntParam(req.Parameters, "limit", 20)
+		data, err = client.GetIOStats(ctx, limit)
+
 	case model.ActionNameTablesInfo:
 		limit := getIntParam(req.Parameters, "limit", 200)
 		data, err = client.GetTablesInfo(ctx, limit)
@@ -71,6 +287,9 @@ func (r *Router) RouteQuery(ctx context.Context, req QueryRequest, instance mode
 	case model.ActionNameChangedSettings:
 		data, err = client.GetChangedSettings(ctx)
 
+	case model.ActionNameSettings:
+		data, err = client.GetSettings(ctx)
+
 	case model.ActionNameVersion:
 		version := client.Version()
 		if version == nil {
@@ -91,25 +310,144 @@ func (r *Router) RouteQuery(ctx context.Context, req QueryRequest, instance mode
 	case model.ActionNameConnectionStats:
 		data, err = client.GetConnectionStats(ctx)
 
+	case model.ActionNameActivitySample:
+		data, err = client.GetActivitySample(ctx)
+
+	case model.ActionNameWaitProfile:
+		interval := getIntParam(req.Parameters, "intervalMs", int(waitprofile.DefaultInterval/time.Millisecond))
+		duration := getIntParam(req.Parameters, "durationSeconds", int(waitprofile.DefaultDuration/time.Second))
+		limit := getIntParam(req.Parameters, "limit", waitprofile.DefaultLimit)
+		data, err = waitprofile.Run(ctx, client, time.Duration(interval)*time.Millisecond, time.Duration(duration)*time.Second, limit)
+
 	case model.ActionNameSlowQueries:
 		limit := getIntParam(req.Parameters, "limit", 20)
 		data, err = client.GetSlowQueries(ctx, limit)
 
+	case model.ActionNameTopStatements:
+		orderBy := getStringParam(req.Parameters, "orderBy", pg.StatementsOrderTotalTime)
+		database := getStringParam(req.Parameters, "database", "")
+		user := getStringParam(req.Parameters, "user", "")
+		limit := getIntParam(req.Parameters, "limit", 20)
+		data, err = client.GetStatements(ctx, orderBy, database, user, limit)
+
+	case model.ActionNameStatementsSnapshot:
+		data, err = statements.Capture(ctx, client)
+
 	case model.ActionNameDatabaseSizes:
 		data, err = client.GetDatabaseSizes(ctx)
 
-	default:
-		err = fmt.Errorf("unsupported action: %s", req.Action)
-	}
+	case model.ActionNameReplicationStatus:
+		data, err = client.GetReplicationStatus(ctx)
 
-	if err != nil {
-		response.Error = err.Error()
-		return response, err
+	case model.ActionNameReplicationSlots:
+		thresholdMB := getIntParam(req.Parameters, "retainedWalThresholdMB", 1024)
+		data, err = client.GetReplicationSlots(ctx, int64(thresholdMB)*1024*1024)
+
+	case model.ActionNameXidWraparound:
+		holdersLimit := getIntParam(req.Parameters, "holdersLimit", 20)
+		data, err = client.GetXidWraparound(ctx, holdersLimit)
+
+	case model.ActionNameTableFreezeAge:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		limit := getIntParam(req.Parameters, "limit", 20)
+		data, err = client.GetTableFreezeAge(ctx, dbName, limit)
+
+	case model.ActionNameMaintenanceProgress:
+		data, err = client.GetMaintenanceProgress(ctx)
+
+	case model.ActionNameTableBloat:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		limit := getIntParam(req.Parameters, "limit", 50)
+		exact := getBoolParam(req.Parameters, "exact", false)
+		data, err = client.GetTableBloat(ctx, dbName, limit, exact)
+
+	case model.ActionNameIndexBloat:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		limit := getIntParam(req.Parameters, "limit", 50)
+		exact := getBoolParam(req.Parameters, "exact", false)
+		data, err = client.GetIndexBloat(ctx, dbName, limit, exact)
+
+	case model.ActionNameIndexAdvisor:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		data, err = client.GetIndexAdvice(ctx, dbName)
+
+	case model.ActionNameLockGraph:
+		data, err = client.GetLockGraph(ctx)
+
+	case model.ActionNameBackendInfo:
+		pid := getIntParam(req.Parameters, "pid", 0)
+		if pid <= 0 {
+			err = fmt.Errorf("pid parameter is required")
+			break
+		}
+		data, err = client.GetBackend(ctx, pid)
+
+	case model.ActionNameCancelBackend, model.ActionNameTerminateBackend:
+		pid := getIntParam(req.Parameters, "pid", 0)
+		queryStart, ok := getTimeParam(req.Parameters, "queryStart")
+		if pid <= 0 || !ok {
+			err = fmt.Errorf("pid and queryStart parameters are required")
+			break
+		}
+		if req.Action == model.ActionNameTerminateBackend {
+			data, err = client.TerminateBackend(ctx, pid, queryStart)
+		} else {
+			data, err = client.CancelBackend(ctx, pid, queryStart)
+		}
+
+	case model.ActionNameListSchemas:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		data, err = client.ListSchemas(ctx, dbName)
+
+	case model.ActionNameListTables:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		schema := getStringParam(req.Parameters, "schema", "")
+		limit := getIntParam(req.Parameters, "limit", 50)
+		offset := getIntParam(req.Parameters, "offset", 0)
+		data, err = client.ListTables(ctx, dbName, schema, limit, offset)
+
+	case model.ActionNameDescribeTable:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		schema := getStringParam(req.Parameters, "schema", "public")
+		table := getStringParam(req.Parameters, "table", "")
+		if table == "" {
+			err = fmt.Errorf("table parameter is required")
+			break
+		}
+		data, err = client.DescribeTable(ctx, dbName, schema, table)
+
+	case model.ActionNameListViews:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		schema := getStringParam(req.Parameters, "schema", "")
+		limit := getIntParam(req.Parameters, "limit", 50)
+		offset := getIntParam(req.Parameters, "offset", 0)
+		data, err = client.ListViews(ctx, dbName, schema, limit, offset)
+
+	case model.ActionNameListFunctions:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		schema := getStringParam(req.Parameters, "schema", "")
+		limit := getIntParam(req.Parameters, "limit", 50)
+		offset := getIntParam(req.Parameters, "offset", 0)
+		data, err = client.ListFunctions(ctx, dbName, schema, limit, offset)
+
+	case model.ActionNameSchemaSnapshot:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		schema := getStringParam(req.Parameters, "schema", "")
+		data, err = client.GetSchemaSnapshot(ctx, dbName, schema)
+
+	case model.ActionNameHealthReport:
+		dbName := getStringParam(req.Parameters, "dbName", instance.DatabaseName)
+		rules, ok := req.Parameters["rules"].(healthreport.Rules)
+		if !ok {
+			rules = r.HealthRules()
+		}
+		data, err = healthreport.Run(ctx, client, dbName, rules)
+
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupportedAction, req.Action)
 	}
 
-	response.Success = true
-	response.Data = data
-	return response, nil
+	return data, err
 }
 
 func getStringParam(params map[string]interface{}, key, defaultValue string) string {
@@ -134,3 +472,27 @@ func getIntParam(params map[string]interface{}, key string, defaultValue int) in
 	}
 	return defaultValue
 }
+
+func getBoolParam(params map[string]interface{}, key string, defaultValue bool) bool {
+	if val, exists := params[key]; exists {
+		if boolVal, ok := val.(bool); ok {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+// getTimeParam accepts a time.Time or an RFC 3339 string
+func getTimeParam(params map[string]interface{}, key string) (time.Time, bool) {
+	if val, exists := params[key]; exists {
+		switch v := val.(type) {
+		case time.Time:
+			return v, true
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -2,9 +2,14 @@ package router
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/healthreport"
 	"psql-mcp-registry/internal/model"
 	"psql-mcp-registry/internal/pg"
 	pgmocks "psql-mcp-registry/internal/pg/mocks"
@@ -72,3 +77,198 @@ func TestRouter_RouteQuery_DatabaseOverview_Success(t *testing.T) {
 	assert.Equal(t, expectedOverview.BlksRead, overview.BlksRead)
 	assert.Equal(t, expectedOverview.BlksHit, overview.BlksHit)
 }
+
+func TestRouter_RouteQuery_TerminateBackend_RequiresObservedQueryStart(t *testing.T) {
+	ctx := context.Background()
+
+	instance := model.Instance{
+		Name:         "test-instance",
+		DatabaseName: "testdb",
+		Status:       "active",
+	}
+	queryStart := time.Date(2025, 10, 20, 12, 30, 15, 123456000, time.UTC)
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("TerminateBackend", ctx, 4242, queryStart).Return(true, nil)
+
+	router := New(mockRegistry)
+
+	// Without query_start the pid alone is not accepted: it may have been reused
+	response, err := router.RouteQuery(ctx, QueryRequest{
+		InstanceName: instance.Name,
+		Action:       model.ActionNameTerminateBackend,
+		Parameters:   map[string]interface{}{"pid": 4242},
+	}, instance)
+
+	assert.Error(t, err)
+	assert.False(t, response.Success)
+	mockClient.AssertNotCalled(t, "TerminateBackend", mock.Anything, mock.Anything, mock.Anything)
+
+	response, err = router.RouteQuery(ctx, QueryRequest{
+		InstanceName: instance.Name,
+		Action:       model.ActionNameTerminateBackend,
+		Parameters: map[string]interface{}{
+			"pid":        float64(4242),
+			"queryStart": queryStart.Format(time.RFC3339Nano),
+		},
+	}, instance)
+
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, true, response.Data)
+}
+
+func TestRouter_RouteQuery_HealthReport_UsesInstanceDatabaseAndRequestRules(t *testing.T) {
+	ctx := context.Background()
+
+	instance := model.Instance{
+		Name:         "test-instance",
+		DatabaseName: "testdb",
+		Status:       "active",
+	}
+
+	mockClient := pgmocks.NewClientInterface(t)
+	mockRegistry := routermocks.NewRegistry(t)
+
+	mockRegistry.On("GetInstanceClient", instance).Return(mockClient)
+	mockClient.On("GetDatabaseOverview", mock.Anything, "testdb").Return(&pg.DatabaseOverview{Deadlocks: 5}, nil)
+	mockClient.On("GetCacheHitRateDB", mock.Anything, "testdb").Return(&pg.CacheHitRate{HitRate: pg.NewNullFloat64(0.999)}, nil)
+	mockClient.On("GetConnectionStats", mock.Anything).Return(&pg.ConnectionSummary{TotalConnections: 10, MaxConnections: 100}, nil)
+	mockClient.On("GetCheckpointsStats", mock.Anything).Return(&pg.CheckpointsStats{}, nil)
+	mockClient.On("GetTablesInfoDB", mock.Anything, "testdb", healthreport.TablesLimit).Return([]pg.TableInfo{}, nil)
+
+	router := New(mockRegistry)
+
+	response, err := router.RouteQuery(ctx, QueryRequest{
+		InstanceName: instance.Name,
+		Action:       model.ActionNameHealthReport,
+	}, instance)
+
+	assert.NoError(t, err)
+	report := response.Data.(*healthreport.Report)
+	assert.Equal(t, "testdb", report.Database)
+	assert.Equal(t, 90, report.Score)
+
+	rules := router.HealthRules()
+	assert.NoError(t, rules.Disable(healthreport.RuleDeadlocks))
+
+	response, err = router.RouteQuery(ctx, QueryRequest{
+		InstanceName: instance.Name,
+		Action:       model.ActionNameHealthReport,
+		Parameters:   map[string]interface{}{"rules": rules},
+	}, instance)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, response.Data.(*healthreport.Report).Score)
+	assert.Empty(t, router.HealthRules().DisabledRules(), "request rules do not change the defaults")
+}
+
+// unimplementedClient panics on every call; reaching it shows that an action is dispatched.
+// The health report checks run in goroutines, so they fail instead of panicking.
+type unimplementedClient struct {
+	pg.ClientInterface
+}
+
+var errUnimplemented = errors.New("unimplemented")
+
+func (unimplementedClient) GetDatabaseOverview(context.Context, string) (*pg.DatabaseOverview, error) {
+	return nil, errUnimplemented
+}
+
+func (unimplementedClient) GetCacheHitRateDB(context.Context, string) (*pg.CacheHitRate, error) {
+	return nil, errUnimplemented
+}
+
+func (unimplementedClient) GetConnectionStats(context.Context) (*pg.ConnectionSummary, error) {
+	return nil, errUnimplemented
+}
+
+func (unimplementedClient) GetCheckpointsStats(context.Context) (*pg.CheckpointsStats, error) {
+	return nil, errUnimplemented
+}
+
+func (unimplementedClient) GetTablesInfoDB(context.Context, string, int) ([]pg.TableInfo, error) {
+	return nil, errUnimplemented
+}
+
+func TestRouter_SupportsAction_MatchesExecute(t *testing.T) {
+	ctx := context.Background()
+	instance := model.Instance{Name: "test-instance"}
+
+	mockRegistry := routermocks.NewRegistry(t)
+	mockRegistry.On("GetInstanceClient", instance).Return(unimplementedClient{})
+
+	router := New(mockRegistry)
+	execute := func(action model.ActionName) (err error) {
+		defer func() {
+			if recover() != nil {
+				err = nil
+			}
+		}()
+		_, err = router.execute(ctx, QueryRequest{InstanceName: instance.Name, Action: action}, instance)
+		return err
+	}
+
+	for action := range routedActions {
+		if action == model.ActionNameInstanceHealth {
+			continue // served by RouteQuery before execute
+		}
+		assert.NotErrorIs(t, execute(action), ErrUnsupportedAction, action)
+	}
+
+	assert.False(t, SupportsAction(model.ActionNameSaveAlertRule))
+	assert.ErrorIs(t, execute(model.ActionNameSaveAlertRule), ErrUnsupportedAction)
+}
+
+func TestNormalizeParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "tool input names",
+			params: map[string]interface{}{"db_name": "shop", "min_duration_seconds": 30, "limit": 10},
+			want:   map[string]interface{}{"dbName": "shop", "minDuration": 30, "limit": 10},
+		},
+		{
+			name:   "router names",
+			params: map[string]interface{}{"dbName": "shop", "retainedWalThresholdMB": 512},
+			want:   map[string]interface{}{"dbName": "shop", "retainedWalThresholdMB": 512},
+		},
+		{
+			name:   "none",
+			params: nil,
+			want:   map[string]interface{}{},
+		},
+		{
+			name:   "unknown",
+			params: map[string]interface{}{"dbname": "shop"},
+			err:    `unknown parameter "dbname"`,
+		},
+		{
+			name:   "state-changing action parameter",
+			params: map[string]interface{}{"pid": 42},
+			err:    `unknown parameter "pid"`,
+		},
+		{
+			name:   "both spellings",
+			params: map[string]interface{}{"db_name": "shop", "dbName": "crm"},
+			err:    `parameter "dbName" is given twice`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeParameters(tt.params)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
@@ -0,0 +1,460 @@
+// Package schemadiff compares the schema snapshots of two databases.
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"psql-mcp-registry/internal/pg"
+)
+
+// Objects a change can refer to, in the order they appear in a patch
+const (
+	ObjectExtension  = "extension"
+	ObjectTable      = "table"
+	ObjectColumn     = "column"
+	ObjectConstraint = "constraint"
+	ObjectIndex      = "index"
+)
+
+// Kinds of changes, seen from the source: missing objects exist only in the
+// source, extra objects only in the target, changed objects differ
+const (
+	KindMissing = "missing"
+	KindExtra   = "extra"
+	KindChanged = "changed"
+)
+
+// Change is one difference between the source and the target schema
+type Change struct {
+	Object string `json:"object"`          // extension, table, column, constraint, index
+	Kind   string `json:"kind"`            // missing, extra, changed
+	Table  string `json:"table,omitempty"` // schema.table, empty for extensions
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"` // definition in the source, empty when extra
+	Target string `json:"target,omitempty"` // definition in the target, empty when missing
+	Patch  string `json:"patch"`            // DDL that makes the target match the source
+}
+
+// Summary counts changes per object
+type Summary struct {
+	Total       int `json:"total"`
+	Extensions  int `json:"extensions"`
+	Tables      int `json:"tables"`
+	Columns     int `json:"columns"`
+	Constraints int `json:"constraints"`
+	Indexes     int `json:"indexes"`
+}
+
+// Diff compares two snapshots and returns the changes ordered the way they should be
+// applied to the target: extensions, tables, columns, constraints, then indexes. Partitions
+// are dropped before and created after their parents.
+func Diff(source, target *pg.SchemaSnapshot) []Change {
+	changes := []Change{}
+	changes = append(changes, diffExtensions(source.Extensions, target.Extensions)...)
+
+	sourceTables := tablesByName(source.Tables)
+	targetTables := tablesByName(target.Tables)
+
+	for _, name := range unionKeys(sourceTables, targetTables) {
+		s, inSource := sourceTables[name]
+		t, inTarget := targetTables[name]
+		switch {
+		case !inTarget:
+			changes = append(changes, missingTable(name, s)...)
+		case !inSource:
+			changes = append(changes, Change{
+				Object: ObjectTable,
+				Kind:   KindExtra,
+				Table:  name,
+				Name:   name,
+				Target: t.Kind,
+				Patch:  fmt.Sprintf("DROP %s %s;", tableKeyword(t), name),
+			})
+		default:
+			changes = append(changes, diffColumns(name, s.Columns, t.Columns)...)
+			changes = append(changes, diffConstraints(name, s.Constraints, t.Constraints)...)
+			changes = append(changes, diffIndexes(quoteIdent(s.SchemaName), name, s.Indexes, t.Indexes)...)
+		}
+	}
+
+	order := map[string]int{
+		ObjectExtension:  0,
+		ObjectTable:      1,
+		ObjectColumn:     2,
+		ObjectConstraint: 3,
+		ObjectIndex:      4,
+	}
+	sourceDepths := partitionDepths(sourceTables)
+	targetDepths := partitionDepths(targetTables)
+	tableRank := func(change Change) int {
+		switch {
+		case change.Object != ObjectTable:
+			return 0
+		case change.Kind == KindExtra:
+			return -targetDepths[change.Table]
+		default:
+			return sourceDepths[change.Table]
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if order[changes[i].Object] != order[changes[j].Object] {
+			return order[changes[i].Object] < order[changes[j].Object]
+		}
+		return tableRank(changes[i]) < tableRank(changes[j])
+	})
+
+	return changes
+}
+
+// Summarize counts the changes per object
+func Summarize(changes []Change) Summary {
+	summary := Summary{Total: len(changes)}
+	for _, change := range changes {
+		switch change.Object {
+		case ObjectExtension:
+			summary.Extensions++
+		case ObjectTable:
+			summary.Tables++
+		case ObjectColumn:
+			summary.Columns++
+		case ObjectConstraint:
+			summary.Constraints++
+		case ObjectIndex:
+			summary.Indexes++
+		}
+	}
+	return summary
+}
+
+// Patch joins the DDL of the changes into one script
+func Patch(changes []Change) string {
+	statements := make([]string, len(changes))
+	for i, change := range changes {
+		statements[i] = change.Patch
+	}
+	return strings.Join(statements, "\n")
+}
+
+func diffExtensions(source, target []pg.ExtensionInfo) []Change {
+	sourceByName := make(map[string]pg.ExtensionInfo, len(source))
+	for _, extension := range source {
+		sourceByName[extension.Name] = extension
+	}
+	targetByName := make(map[string]pg.ExtensionInfo, len(target))
+	for _, extension := range target {
+		targetByName[extension.Name] = extension
+	}
+
+	changes := []Change{}
+	for _, name := range unionKeys(sourceByName, targetByName) {
+		s, inSource := sourceByName[name]
+		t, inTarget := targetByName[name]
+		change := Change{Object: ObjectExtension, Name: name, Source: s.Version, Target: t.Version}
+		switch {
+		case !inTarget:
+			change.Kind = KindMissing
+			change.Patch = fmt.Sprintf("CREATE EXTENSION %s SCHEMA %s VERSION '%s';",
+				quoteIdent(name), quoteIdent(s.Schema), s.Version)
+		case !inSource:
+			change.Kind = KindExtra
+			change.Patch = fmt.Sprintf("DROP EXTENSION %s;", quoteIdent(name))
+		case s.Version != t.Version:
+			change.Kind = KindChanged
+			change.Patch = fmt.Sprintf("ALTER EXTENSION %s UPDATE TO '%s';", quoteIdent(name), s.Version)
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// missingTable reports a table that exists only in the source. Its constraints and
+// indexes are reported as missing too so the patch recreates them, except for a
+// partition, which takes its columns, constraints and indexes from the parent.
+func missingTable(name string, table pg.TableDetail) []Change {
+	changes := []Change{{
+		Object: ObjectTable,
+		Kind:   KindMissing,
+		Table:  name,
+		Name:   name,
+		Source: table.Kind,
+		Patch:  createTable(name, table),
+	}}
+	if table.PartitionBound.Valid {
+		return changes
+	}
+
+	indexes := table.Indexes
+	if table.PartitionKey.Valid {
+		// pg_get_indexdef creates the index of a partitioned table ON ONLY the parent;
+		// without ONLY it is also built on the partitions created after the table
+		indexes = make([]pg.IndexDefinition, len(table.Indexes))
+		for i, index := range table.Indexes {
+			index.Definition = strings.Replace(index.Definition, " ON ONLY ", " ON ", 1)
+			indexes[i] = index
+		}
+	}
+	changes = append(changes, diffConstraints(name, table.Constraints, nil)...)
+	changes = append(changes, diffIndexes(quoteIdent(table.SchemaName), name, indexes, nil)...)
+	return changes
+}
+
+// createTable returns the CREATE statement of a table, with the partition key of a
+// partitioned table, the parent of a partition and the server of a foreign table
+func createTable(name string, table pg.TableDetail) string {
+	var ddl string
+	if table.PartitionBound.Valid {
+		parent := qualifiedName(table.ParentSchema.String, table.ParentTable.String)
+		ddl = fmt.Sprintf("CREATE %s %s PARTITION OF %s %s", tableKeyword(table), name, parent, table.PartitionBound.String)
+	} else {
+		columns := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			columns[i] = "  " + columnDefinition(column)
+		}
+		ddl = fmt.Sprintf("CREATE %s %s (\n%s\n)", tableKeyword(table), name, strings.Join(columns, ",\n"))
+	}
+	if table.PartitionKey.Valid {
+		ddl += " PARTITION BY " + table.PartitionKey.String
+	}
+	if table.ForeignServer.Valid {
+		ddl += " SERVER " + quoteIdent(table.ForeignServer.String)
+		if table.ForeignOptions.Valid {
+			ddl += " OPTIONS (" + table.ForeignOptions.String + ")"
+		}
+	}
+	return ddl + ";"
+}
+
+// tableKeyword is the object type of a table in CREATE and DROP statements
+func tableKeyword(table pg.TableDetail) string {
+	if table.ForeignServer.Valid {
+		return "FOREIGN TABLE"
+	}
+	return "TABLE"
+}
+
+func diffColumns(table string, source, target []pg.ColumnInfo) []Change {
+	sourceByName := make(map[string]pg.ColumnInfo, len(source))
+	for _, column := range source {
+		sourceByName[column.Name] = column
+	}
+	targetByName := make(map[string]pg.ColumnInfo, len(target))
+	for _, column := range target {
+		targetByName[column.Name] = column
+	}
+
+	changes := []Change{}
+	// Keep the column order of the source, extra columns follow in target order
+	for _, column := range source {
+		t, inTarget := targetByName[column.Name]
+		if !inTarget {
+			changes = append(changes, Change{
+				Object: ObjectColumn,
+				Kind:   KindMissing,
+				Table:  table,
+				Name:   column.Name,
+				Source: columnSpec(column),
+				Patch:  fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, columnDefinition(column)),
+			})
+			continue
+		}
+
+		var patch []string
+		if column.Type != t.Type {
+			patch = append(patch, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;",
+				table, quoteIdent(column.Name), column.Type))
+		}
+		if column.Nullable != t.Nullable {
+			action := "SET NOT NULL"
+			if column.Nullable {
+				action = "DROP NOT NULL"
+			}
+			patch = append(patch, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;",
+				table, quoteIdent(column.Name), action))
+		}
+		if column.Default != t.Default {
+			action := "DROP DEFAULT"
+			if column.Default.Valid {
+				action = "SET DEFAULT " + column.Default.String
+			}
+			patch = append(patch, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;",
+				table, quoteIdent(column.Name), action))
+		}
+		if len(patch) > 0 {
+			changes = append(changes, Change{
+				Object: ObjectColumn,
+				Kind:   KindChanged,
+				Table:  table,
+				Name:   column.Name,
+				Source: columnSpec(column),
+				Target: columnSpec(t),
+				Patch:  strings.Join(patch, "\n"),
+			})
+		}
+	}
+	for _, column := range target {
+		if _, inSource := sourceByName[column.Name]; !inSource {
+			changes = append(changes, Change{
+				Object: ObjectColumn,
+				Kind:   KindExtra,
+				Table:  table,
+				Name:   column.Name,
+				Target: columnSpec(column),
+				Patch:  fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, quoteIdent(column.Name)),
+			})
+		}
+	}
+	return changes
+}
+
+func diffConstraints(table string, source, target []pg.ConstraintInfo) []Change {
+	sourceByName := make(map[string]pg.ConstraintInfo, len(source))
+	for _, constraint := range source {
+		sourceByName[constraint.Name] = constraint
+	}
+	targetByName := make(map[string]pg.ConstraintInfo, len(target))
+	for _, constraint := range target {
+		targetByName[constraint.Name] = constraint
+	}
+
+	changes := []Change{}
+	for _, name := range unionKeys(sourceByName, targetByName) {
+		s, inSource := sourceByName[name]
+		t, inTarget := targetByName[name]
+		add := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", table, quoteIdent(name), s.Definition)
+		drop := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", table, quoteIdent(name))
+		change := Change{Object: ObjectConstraint, Table: table, Name: name, Source: s.Definition, Target: t.Definition}
+		switch {
+		case !inTarget:
+			change.Kind = KindMissing
+			change.Patch = add
+		case !inSource:
+			change.Kind = KindExtra
+			change.Patch = drop
+		case s.Definition != t.Definition:
+			change.Kind = KindChanged
+			change.Patch = drop + "\n" + add
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffIndexes compares indexes by name. Indexes that back a constraint are
+// covered by the constraint and skipped. schema is quoted like table.
+func diffIndexes(schema, table string, source, target []pg.IndexDefinition) []Change {
+	sourceByName := make(map[string]pg.IndexDefinition, len(source))
+	for _, index := range source {
+		if !index.Constraint.Valid {
+			sourceByName[index.Name] = index
+		}
+	}
+	targetByName := make(map[string]pg.IndexDefinition, len(target))
+	for _, index := range target {
+		if !index.Constraint.Valid {
+			targetByName[index.Name] = index
+		}
+	}
+
+	changes := []Change{}
+	for _, name := range unionKeys(sourceByName, targetByName) {
+		s, inSource := sourceByName[name]
+		t, inTarget := targetByName[name]
+		drop := fmt.Sprintf("DROP INDEX %s.%s;", schema, quoteIdent(name))
+		change := Change{Object: ObjectIndex, Table: table, Name: name, Source: s.Definition, Target: t.Definition}
+		switch {
+		case !inTarget:
+			change.Kind = KindMissing
+			change.Patch = s.Definition + ";"
+		case !inSource:
+			change.Kind = KindExtra
+			change.Patch = drop
+		case s.Definition != t.Definition:
+			change.Kind = KindChanged
+			change.Patch = drop + "\n" + s.Definition + ";"
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// tablesByName keys tables by their quoted schema.table name
+func tablesByName(tables []pg.TableDetail) map[string]pg.TableDetail {
+	byName := make(map[string]pg.TableDetail, len(tables))
+	for _, table := range tables {
+		byName[qualifiedName(table.SchemaName, table.TableName)] = table
+	}
+	return byName
+}
+
+func qualifiedName(schema, name string) string {
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// partitionDepths counts the partitioned tables above each partition
+func partitionDepths(tables map[string]pg.TableDetail) map[string]int {
+	depths := make(map[string]int, len(tables))
+	for name, table := range tables {
+		for table.ParentTable.Valid && depths[name] < len(tables) {
+			parent, exists := tables[qualifiedName(table.ParentSchema.String, table.ParentTable.String)]
+			if !exists {
+				break
+			}
+			depths[name]++
+			table = parent
+		}
+	}
+	return depths
+}
+
+func unionKeys[V, W any](a map[string]V, b map[string]W) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// columnSpec is the type, nullability and default of a column
+func columnSpec(column pg.ColumnInfo) string {
+	spec := column.Type
+	if !column.Nullable {
+		spec += " NOT NULL"
+	}
+	if column.Default.Valid {
+		spec += " DEFAULT " + column.Default.String
+	}
+	return spec
+}
+
+func columnDefinition(column pg.ColumnInfo) string {
+	return quoteIdent(column.Name) + " " + columnSpec(column)
+}
+
+// quoteIdent quotes an identifier unless it is a plain lower-case name
+func quoteIdent(name string) string {
+	plain := name != ""
+	for i, r := range name {
+		if !(r >= 'a' && r <= 'z' || r == '_' || i > 0 && (r >= '0' && r <= '9' || r == '$')) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
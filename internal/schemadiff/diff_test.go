@@ -0,0 +1,180 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/pg"
+)
+
+func ordersTable() pg.TableDetail {
+	return pg.TableDetail{
+		TableSchema: pg.TableSchema{
+			SchemaName: "public",
+			TableName:  "orders",
+			Kind:       "table",
+			Columns: []pg.ColumnInfo{
+				{Name: "id", Type: "bigint", Default: pg.NewNullString("nextval('orders_id_seq'::regclass)")},
+				{Name: "customer_id", Type: "bigint"},
+				{Name: "note", Type: "text", Nullable: true},
+			},
+		},
+		Constraints: []pg.ConstraintInfo{
+			{Name: "orders_pkey", Type: "primary_key", Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+		},
+		Indexes: []pg.IndexDefinition{
+			{Name: "orders_pkey", Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)", Constraint: pg.NewNullString("orders_pkey")},
+			{Name: "orders_customer_id_idx", Definition: "CREATE INDEX orders_customer_id_idx ON public.orders USING btree (customer_id)"},
+		},
+	}
+}
+
+func TestDiff_IdenticalSnapshotsHaveNoChanges(t *testing.T) {
+	snapshot := &pg.SchemaSnapshot{
+		Tables:     []pg.TableDetail{ordersTable()},
+		Extensions: []pg.ExtensionInfo{{Name: "pg_trgm", Version: "1.6", Schema: "public"}},
+	}
+
+	changes := Diff(snapshot, snapshot)
+
+	assert.Empty(t, changes)
+	assert.Equal(t, Summary{}, Summarize(changes))
+}
+
+func TestDiff_ReportsColumnIndexConstraintAndExtensionChanges(t *testing.T) {
+	source := &pg.SchemaSnapshot{
+		Tables:     []pg.TableDetail{ordersTable()},
+		Extensions: []pg.ExtensionInfo{{Name: "pg_trgm", Version: "1.6", Schema: "public"}},
+	}
+
+	staging := ordersTable()
+	staging.Columns[1].Type = "integer"
+	staging.Columns[2].Nullable = false
+	staging.Columns = append(staging.Columns, pg.ColumnInfo{Name: "Legacy Flag", Type: "boolean", Nullable: true})
+	staging.Indexes = staging.Indexes[:1]
+	staging.Constraints = append(staging.Constraints, pg.ConstraintInfo{
+		Name: "orders_note_check", Type: "check", Definition: "CHECK (note <> ''::text)",
+	})
+	target := &pg.SchemaSnapshot{
+		Tables:     []pg.TableDetail{staging},
+		Extensions: []pg.ExtensionInfo{{Name: "pg_trgm", Version: "1.5", Schema: "public"}},
+	}
+
+	changes := Diff(source, target)
+
+	require.Len(t, changes, 6)
+	assert.Equal(t, Summary{Total: 6, Extensions: 1, Columns: 3, Constraints: 1, Indexes: 1}, Summarize(changes))
+
+	assert.Equal(t, Change{
+		Object: ObjectExtension, Kind: KindChanged, Name: "pg_trgm", Source: "1.6", Target: "1.5",
+		Patch: "ALTER EXTENSION pg_trgm UPDATE TO '1.6';",
+	}, changes[0])
+	assert.Equal(t, "ALTER TABLE public.orders ALTER COLUMN customer_id TYPE bigint;", changes[1].Patch)
+	assert.Equal(t, "ALTER TABLE public.orders ALTER COLUMN note DROP NOT NULL;", changes[2].Patch)
+	assert.Equal(t, KindExtra, changes[3].Kind)
+	assert.Equal(t, `ALTER TABLE public.orders DROP COLUMN "Legacy Flag";`, changes[3].Patch)
+	assert.Equal(t, "ALTER TABLE public.orders DROP CONSTRAINT orders_note_check;", changes[4].Patch)
+	assert.Equal(t, Change{
+		Object: ObjectIndex, Kind: KindMissing, Table: "public.orders", Name: "orders_customer_id_idx",
+		Source: "CREATE INDEX orders_customer_id_idx ON public.orders USING btree (customer_id)",
+		Patch:  "CREATE INDEX orders_customer_id_idx ON public.orders USING btree (customer_id);",
+	}, changes[5])
+}
+
+func TestDiff_MissingTableIsCreatedWithConstraintsAndIndexes(t *testing.T) {
+	source := &pg.SchemaSnapshot{Tables: []pg.TableDetail{ordersTable()}}
+	target := &pg.SchemaSnapshot{Extensions: []pg.ExtensionInfo{{Name: "hstore", Version: "1.8", Schema: "public"}}}
+
+	changes := Diff(source, target)
+
+	assert.Equal(t, Summary{Total: 4, Extensions: 1, Tables: 1, Constraints: 1, Indexes: 1}, Summarize(changes))
+	assert.Equal(t, `DROP EXTENSION hstore;
+CREATE TABLE public.orders (
+  id bigint NOT NULL DEFAULT nextval('orders_id_seq'::regclass),
+  customer_id bigint NOT NULL,
+  note text
+);
+ALTER TABLE public.orders ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+CREATE INDEX orders_customer_id_idx ON public.orders USING btree (customer_id);`, Patch(changes))
+}
+
+func TestDiff_PartitionedAndForeignTables(t *testing.T) {
+	events := pg.TableDetail{
+		TableSchema: pg.TableSchema{
+			SchemaName: "public",
+			TableName:  "events",
+			Kind:       "partitioned table",
+			Columns:    []pg.ColumnInfo{{Name: "created_at", Type: "timestamp with time zone"}},
+		},
+		Indexes: []pg.IndexDefinition{
+			{Name: "events_created_at_idx", Definition: "CREATE INDEX events_created_at_idx ON ONLY public.events USING btree (created_at)"},
+		},
+		PartitionKey: pg.NewNullString("RANGE (created_at)"),
+	}
+	partition := pg.TableDetail{
+		TableSchema: pg.TableSchema{
+			SchemaName: "public",
+			TableName:  "a_events_2026",
+			Kind:       "table",
+			Columns:    events.Columns,
+		},
+		Indexes: []pg.IndexDefinition{
+			{Name: "a_events_2026_created_at_idx", Definition: "CREATE INDEX a_events_2026_created_at_idx ON public.a_events_2026 USING btree (created_at)"},
+		},
+		ParentSchema:   pg.NewNullString("public"),
+		ParentTable:    pg.NewNullString("events"),
+		PartitionBound: pg.NewNullString("FOR VALUES FROM ('2026-01-01 00:00:00+00') TO ('2027-01-01 00:00:00+00')"),
+	}
+	remote := pg.TableDetail{
+		TableSchema: pg.TableSchema{
+			SchemaName: "public",
+			TableName:  "remote_users",
+			Kind:       "foreign table",
+			Columns:    []pg.ColumnInfo{{Name: "id", Type: "bigint"}},
+		},
+		ForeignServer:  pg.NewNullString("Users Server"),
+		ForeignOptions: pg.NewNullString("schema_name 'public', table_name 'users'"),
+	}
+
+	changes := Diff(&pg.SchemaSnapshot{Tables: []pg.TableDetail{partition, events, remote}}, &pg.SchemaSnapshot{})
+
+	assert.Equal(t, `CREATE TABLE public.events (
+  created_at timestamp with time zone NOT NULL
+) PARTITION BY RANGE (created_at);
+CREATE FOREIGN TABLE public.remote_users (
+  id bigint NOT NULL
+) SERVER "Users Server" OPTIONS (schema_name 'public', table_name 'users');
+CREATE TABLE public.a_events_2026 PARTITION OF public.events FOR VALUES FROM ('2026-01-01 00:00:00+00') TO ('2027-01-01 00:00:00+00');
+CREATE INDEX events_created_at_idx ON public.events USING btree (created_at);`, Patch(changes))
+
+	changes = Diff(&pg.SchemaSnapshot{}, &pg.SchemaSnapshot{Tables: []pg.TableDetail{events, partition, remote}})
+
+	assert.Equal(t, `DROP TABLE public.a_events_2026;
+DROP TABLE public.events;
+DROP FOREIGN TABLE public.remote_users;`, Patch(changes))
+}
+
+func TestDiff_ComparesDefaultsAndQuotesSchemaOfIndexes(t *testing.T) {
+	source := ordersTable()
+	source.SchemaName = "sales.eu"
+	source.Columns[2].Default = pg.NewNullString("''::text")
+
+	staging := ordersTable()
+	staging.SchemaName = "sales.eu"
+	staging.Columns[0].Default = pg.NullString{}
+	staging.Indexes = append(staging.Indexes, pg.IndexDefinition{
+		Name: "orders_note_idx", Definition: `CREATE INDEX orders_note_idx ON "sales.eu".orders USING btree (note)`,
+	})
+
+	changes := Diff(&pg.SchemaSnapshot{Tables: []pg.TableDetail{source}}, &pg.SchemaSnapshot{Tables: []pg.TableDetail{staging}})
+
+	require.Len(t, changes, 3)
+	assert.Equal(t, Change{
+		Object: ObjectColumn, Kind: KindChanged, Table: `"sales.eu".orders`, Name: "id",
+		Source: "bigint NOT NULL DEFAULT nextval('orders_id_seq'::regclass)", Target: "bigint NOT NULL",
+		Patch: `ALTER TABLE "sales.eu".orders ALTER COLUMN id SET DEFAULT nextval('orders_id_seq'::regclass);`,
+	}, changes[0])
+	assert.Equal(t, `ALTER TABLE "sales.eu".orders ALTER COLUMN note SET DEFAULT ''::text;`, changes[1].Patch)
+	assert.Equal(t, `DROP INDEX "sales.eu".orders_note_idx;`, changes[2].Patch)
+}
@@ -0,0 +1,250 @@
+// Package settingsdrift compares PostgreSQL settings between instances and
+// against a baseline profile.
+package settingsdrift
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"psql-mcp-registry/internal/pg"
+)
+
+// IgnoredSettings differ between healthy instances by design (paths, node names,
+// primary/standby state) and are skipped unless a baseline lists them
+var IgnoredSettings = map[string]bool{
+	"application_name":      true,
+	"cluster_name":          true,
+	"config_file":           true,
+	"data_directory":        true,
+	"external_pid_file":     true,
+	"hba_file":              true,
+	"ident_file":            true,
+	"in_hot_standby":        true,
+	"transaction_read_only": true,
+}
+
+// Instance holds the pg_settings rows of one instance
+type Instance struct {
+	Name     string
+	Settings []pg.SettingInfo
+}
+
+// Value is a setting as seen on one instance
+type Value struct {
+	Instance       string `json:"instance"`
+	Value          string `json:"value"`          // normalized, empty when the instance has no such setting
+	Setting        string `json:"setting"`        // as reported by pg_settings
+	Unit           string `json:"unit,omitempty"` // unit of setting as reported by pg_settings
+	Source         string `json:"source,omitempty"`
+	PendingRestart bool   `json:"pending_restart"`
+}
+
+// Drift is a setting that differs between instances, from the baseline, or
+// that waits for a restart on some instance
+type Drift struct {
+	Name             string  `json:"name"`
+	Values           []Value `json:"values"`
+	Baseline         string  `json:"baseline,omitempty"` // normalized baseline value
+	Differs          bool    `json:"differs"`            // instances disagree with each other
+	BaselineMismatch bool    `json:"baseline_mismatch"`  // some instance disagrees with the baseline
+	PendingRestart   bool    `json:"pending_restart"`    // some instance runs with another value than configured
+}
+
+// Compare returns the drifting settings ordered by name. baseline may be nil.
+func Compare(instances []Instance, baseline map[string]string) []Drift {
+	byInstance := make([]map[string]pg.SettingInfo, len(instances))
+	names := map[string]bool{}
+	for i, instance := range instances {
+		byInstance[i] = make(map[string]pg.SettingInfo, len(instance.Settings))
+		for _, setting := range instance.Settings {
+			byInstance[i][setting.Name] = setting
+			names[setting.Name] = true
+		}
+	}
+	for name := range baseline {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	drifts := []Drift{}
+	for _, name := range sorted {
+		expected, inBaseline := baseline[name]
+		if IgnoredSettings[name] && !inBaseline {
+			continue
+		}
+
+		drift := Drift{Name: name, Values: make([]Value, len(instances))}
+		unit := ""
+		distinct := map[string]bool{}
+		for i, instance := range instances {
+			value := Value{Instance: instance.Name}
+			if setting, exists := byInstance[i][name]; exists {
+				value.Value = Normalize(setting.Setting, setting.Unit.String)
+				value.Setting = setting.Setting
+				value.Unit = setting.Unit.String
+				value.Source = setting.Source
+				value.PendingRestart = setting.PendingRestart
+				drift.PendingRestart = drift.PendingRestart || setting.PendingRestart
+				unit = setting.Unit.String
+			}
+			distinct[value.Value] = true
+			drift.Values[i] = value
+		}
+		drift.Differs = len(distinct) > 1
+
+		if inBaseline {
+			drift.Baseline = Normalize(expected, unit)
+			for _, value := range drift.Values {
+				if value.Value != drift.Baseline {
+					drift.BaselineMismatch = true
+				}
+			}
+		}
+
+		if drift.Differs || drift.BaselineMismatch || drift.PendingRestart {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	return drifts
+}
+
+// Multipliers of memory units to bytes and of time units to milliseconds, as
+// used by pg_settings.unit and accepted in postgresql.conf
+var (
+	memoryUnits = map[string]float64{
+		"B":    1,
+		"kB":   1 << 10,
+		"8kB":  8 << 10,
+		"MB":   1 << 20,
+		"16MB": 16 << 20,
+		"GB":   1 << 30,
+		"TB":   1 << 40,
+	}
+	timeUnits = map[string]float64{
+		"us":  0.001,
+		"ms":  1,
+		"s":   1000,
+		"min": 60 * 1000,
+		"h":   60 * 60 * 1000,
+		"d":   24 * 60 * 60 * 1000,
+	}
+)
+
+var numberWithUnit = regexp.MustCompile(`^(-?[0-9]*\.?[0-9]+(?:[eE][-+]?[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// Normalize returns a canonical form of a setting value so that 4GB, 4096MB and
+// 524288 with unit 8kB compare equal. A value without a unit suffix is read in unit,
+// like postgresql.conf does. Memory is rendered in the largest unit that divides it
+// evenly, time likewise, booleans as on/off and other numbers without trailing zeros.
+func Normalize(value, unit string) string {
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(value) {
+	case "on", "true", "yes":
+		return "on"
+	case "off", "false", "no":
+		return "off"
+	}
+
+	number, suffix, ok := parseNumber(value)
+	if !ok {
+		return value
+	}
+
+	// -1 disables a setting regardless of its unit
+	if number == -1 {
+		return "-1"
+	}
+
+	if bytes, ok := convert(number, suffix, unit, memoryUnits); ok {
+		return FormatBytes(bytes)
+	}
+	if ms, ok := convert(number, suffix, unit, timeUnits); ok {
+		return FormatMilliseconds(ms)
+	}
+
+	if suffix != "" {
+		return value
+	}
+	return strconv.FormatFloat(number, 'g', -1, 64)
+}
+
+// Bytes returns a memory setting in bytes. ok is false for settings without a memory
+// unit and for -1.
+func Bytes(value, unit string) (int64, bool) {
+	number, suffix, ok := parseNumber(value)
+	if !ok || number == -1 {
+		return 0, false
+	}
+	bytes, ok := convert(number, suffix, unit, memoryUnits)
+	return int64(bytes), ok
+}
+
+// Milliseconds returns a time setting in milliseconds. ok is false for settings without
+// a time unit and for -1.
+func Milliseconds(value, unit string) (float64, bool) {
+	number, suffix, ok := parseNumber(value)
+	if !ok || number == -1 {
+		return 0, false
+	}
+	return convert(number, suffix, unit, timeUnits)
+}
+
+// FormatBytes renders bytes in the largest memory unit that divides them evenly
+func FormatBytes(bytes float64) string {
+	return formatWithUnits(bytes, []string{"TB", "GB", "MB", "kB", "B"}, memoryUnits)
+}
+
+// FormatMilliseconds renders a duration in the largest time unit that divides it evenly
+func FormatMilliseconds(ms float64) string {
+	return formatWithUnits(ms, []string{"d", "h", "min", "s", "ms", "us"}, timeUnits)
+}
+
+func parseNumber(value string) (float64, string, bool) {
+	match := numberWithUnit.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, "", false
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return number, match[2], true
+}
+
+// convert reads number in the unit of its suffix or, without one, in the unit of the
+// setting. ok is false when the setting unit is not one of units.
+func convert(number float64, suffix, unit string, units map[string]float64) (float64, bool) {
+	multiplier, ok := units[unit]
+	if !ok {
+		return 0, false
+	}
+	if suffixMultiplier, ok := units[suffix]; ok && suffix != "" {
+		multiplier = suffixMultiplier
+	}
+	return number * multiplier, true
+}
+
+// formatWithUnits renders v in the largest unit that divides it evenly
+func formatWithUnits(v float64, order []string, units map[string]float64) string {
+	if v == 0 {
+		return "0"
+	}
+	for _, unit := range order {
+		scaled := v / units[unit]
+		if math.Abs(scaled-math.Round(scaled)) < 1e-9 {
+			return strconv.FormatFloat(math.Round(scaled), 'f', -1, 64) + unit
+		}
+	}
+	last := order[len(order)-1]
+	return strconv.FormatFloat(v/units[last], 'g', -1, 64) + last
+}
@@ -0,0 +1,101 @@
+package settingsdrift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/pg"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value, unit, want string
+	}{
+		{"524288", "8kB", "4GB"},
+		{"4GB", "8kB", "4GB"},
+		{"4096MB", "8kB", "4GB"},
+		{"65536", "kB", "64MB"},
+		{"100", "8kB", "800kB"},
+		{"16777216", "B", "16MB"},
+		{"300", "s", "5min"},
+		{"5min", "s", "5min"},
+		{"1000", "ms", "1s"},
+		{"2", "ms", "2ms"},
+		{"0.5", "ms", "500us"},
+		{"-1", "kB", "-1"},
+		{"0", "ms", "0"},
+		{"4.0", "", "4"},
+		{"1.1", "", "1.1"},
+		{"true", "", "on"},
+		{"off", "", "off"},
+		{"replica", "", "replica"},
+		{"Europe/Moscow", "", "Europe/Moscow"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Normalize(tt.value, tt.unit), "Normalize(%q, %q)", tt.value, tt.unit)
+	}
+}
+
+func setting(name, value, unit string) pg.SettingInfo {
+	s := pg.SettingInfo{Name: name, Setting: value, Source: "configuration file"}
+	if unit != "" {
+		s.Unit = pg.NewNullString(unit)
+	}
+	return s
+}
+
+func TestCompare_ReportsDifferencesPendingRestartAndBaseline(t *testing.T) {
+	restarting := setting("max_connections", "200", "")
+	restarting.PendingRestart = true
+
+	instances := []Instance{
+		{Name: "prod-1", Settings: []pg.SettingInfo{
+			setting("shared_buffers", "524288", "8kB"),
+			setting("work_mem", "4096", "kB"),
+			setting("max_connections", "200", ""),
+			setting("data_directory", "/var/lib/postgresql/16/main", ""),
+		}},
+		{Name: "prod-2", Settings: []pg.SettingInfo{
+			setting("shared_buffers", "524288", "8kB"),
+			setting("work_mem", "8192", "kB"),
+			restarting,
+			setting("data_directory", "/data/pg", ""),
+		}},
+	}
+
+	drifts := Compare(instances, map[string]string{"shared_buffers": "8GB"})
+
+	require.Len(t, drifts, 3)
+
+	assert.Equal(t, "max_connections", drifts[0].Name)
+	assert.False(t, drifts[0].Differs)
+	assert.True(t, drifts[0].PendingRestart)
+
+	assert.Equal(t, "shared_buffers", drifts[1].Name)
+	assert.False(t, drifts[1].Differs)
+	assert.True(t, drifts[1].BaselineMismatch)
+	assert.Equal(t, "8GB", drifts[1].Baseline)
+	assert.Equal(t, "4GB", drifts[1].Values[0].Value)
+	assert.Equal(t, "524288", drifts[1].Values[0].Setting)
+	assert.Equal(t, "8kB", drifts[1].Values[0].Unit)
+
+	assert.Equal(t, "work_mem", drifts[2].Name)
+	assert.True(t, drifts[2].Differs)
+	assert.Equal(t, []string{"4MB", "8MB"}, []string{drifts[2].Values[0].Value, drifts[2].Values[1].Value})
+}
+
+func TestCompare_MissingSettingDiffers(t *testing.T) {
+	instances := []Instance{
+		{Name: "pg16", Settings: []pg.SettingInfo{setting("io_combine_limit", "16", "8kB")}},
+		{Name: "pg13", Settings: []pg.SettingInfo{}},
+	}
+
+	drifts := Compare(instances, nil)
+
+	require.Len(t, drifts, 1)
+	assert.True(t, drifts[0].Differs)
+	assert.Equal(t, "128kB", drifts[0].Values[0].Value)
+	assert.Equal(t, "", drifts[0].Values[1].Value)
+}
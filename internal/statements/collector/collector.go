@@ -0,0 +1,140 @@
+// Package collector captures pg_stat_statements snapshots of every registered
+// instance on a schedule and removes the ones past their retention.
+package collector
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/router"
+)
+
+// CreatedBy marks the snapshots taken by the collector
+const CreatedBy = "collector"
+
+// Config holds the schedule and retention of the collector
+type Config struct {
+	// Interval is the time between two rounds of snapshots; zero disables the collector
+	Interval time.Duration
+	// Retention is how long snapshots are kept; zero keeps them forever
+	Retention time.Duration
+}
+
+// DefaultConfig returns the default collector configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Retention: 7 * 24 * time.Hour,
+	}
+}
+
+// LoadConfigFromEnv loads the collector configuration from environment variables
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	if interval := os.Getenv("STATEMENTS_SNAPSHOT_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d >= 0 {
+			cfg.Interval = d
+		}
+	}
+
+	if retention := os.Getenv("STATEMENTS_SNAPSHOT_RETENTION"); retention != "" {
+		if d, err := time.ParseDuration(retention); err == nil && d >= 0 {
+			cfg.Retention = d
+		}
+	}
+
+	return cfg
+}
+
+// Store keeps the snapshots in the registry
+type Store interface {
+	SaveStatementsSnapshot(ctx context.Context, snapshot *model.StatementsSnapshot) error
+	DeleteStatementsSnapshots(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Collector snapshots pg_stat_statements of all instances
+type Collector struct {
+	store     Store
+	querier   router.Querier
+	instances router.InstanceLister
+	config    *Config
+	now       func() time.Time
+}
+
+func NewCollector(store Store, querier router.Querier, instances router.InstanceLister, config *Config) *Collector {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Collector{
+		store:     store,
+		querier:   querier,
+		instances: instances,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Enabled reports whether the collector takes snapshots on a schedule
+func (c *Collector) Enabled() bool {
+	return c.config.Interval > 0
+}
+
+// Run takes snapshots every interval until the context is done; it returns at once when disabled
+func (c *Collector) Run(ctx context.Context) {
+	if !c.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.Collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect runs one round: a snapshot of every instance, then the retention cleanup.
+// Instances that fail (e.g. without pg_stat_statements) are logged and skipped.
+func (c *Collector) Collect(ctx context.Context) {
+	instances, err := c.instances.ListInstances(ctx)
+	if err != nil {
+		log.Printf("Statements snapshots: failed to list instances: %v", err)
+		return
+	}
+
+	responses := c.querier.RouteAll(ctx, router.QueryRequest{Action: model.ActionNameStatementsSnapshot}, instances)
+	for i, response := range responses {
+		instance := instances[i]
+		if !response.Success {
+			log.Printf("Statements snapshot of %s failed: %s", instance.Name, response.Error)
+			continue
+		}
+
+		snapshot, ok := response.Data.(*model.StatementsSnapshot)
+		if !ok {
+			log.Printf("Statements snapshot of %s failed: unexpected data type %T", instance.Name, response.Data)
+			continue
+		}
+		snapshot.InstanceName = instance.Name
+		snapshot.CreatedBy = CreatedBy
+		if err := c.store.SaveStatementsSnapshot(ctx, snapshot); err != nil {
+			log.Printf("Statements snapshot of %s failed: %v", instance.Name, err)
+		}
+	}
+
+	if c.config.Retention > 0 {
+		if _, err := c.store.DeleteStatementsSnapshots(ctx, c.now().Add(-c.config.Retention)); err != nil {
+			log.Printf("Statements snapshots: %v", err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/router"
+)
+
+type memoryStore struct {
+	saved         []model.StatementsSnapshot
+	deletedBefore []time.Time
+}
+
+func (s *memoryStore) SaveStatementsSnapshot(ctx context.Context, snapshot *model.StatementsSnapshot) error {
+	snapshot.ID = len(s.saved) + 1
+	s.saved = append(s.saved, *snapshot)
+	return nil
+}
+
+func (s *memoryStore) DeleteStatementsSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	s.deletedBefore = append(s.deletedBefore, before)
+	return 0, nil
+}
+
+// snapshotQuerier answers statements_snapshot for every instance except the failing ones
+type snapshotQuerier struct {
+	failing map[string]bool
+}
+
+func (q snapshotQuerier) RouteAll(ctx context.Context, req router.QueryRequest, instances []model.Instance) []*router.QueryResponse {
+	responses := make([]*router.QueryResponse, len(instances))
+	for i, instance := range instances {
+		if q.failing[instance.Name] {
+			responses[i] = &router.QueryResponse{Instance: instance.Name, Action: req.Action, Error: "pg_stat_statements extension is not installed"}
+			continue
+		}
+		responses[i] = &router.QueryResponse{
+			Instance: instance.Name,
+			Action:   req.Action,
+			Success:  true,
+			Data:     &model.StatementsSnapshot{Statements: []model.StatementCounters{{QueryID: 1, Calls: 10}}, Count: 1},
+		}
+	}
+	return responses
+}
+
+type instanceList []model.Instance
+
+func (l instanceList) ListInstances(ctx context.Context) ([]model.Instance, error) {
+	return l, nil
+}
+
+func TestCollector_Collect_SavesSnapshotsAndAppliesRetention(t *testing.T) {
+	store := &memoryStore{}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	instances := instanceList{{Name: "prod"}, {Name: "legacy"}, {Name: "dev"}}
+
+	collector := NewCollector(store, snapshotQuerier{failing: map[string]bool{"legacy": true}}, instances,
+		&Config{Interval: 5 * time.Minute, Retention: 24 * time.Hour})
+	collector.now = func() time.Time { return now }
+
+	collector.Collect(context.Background())
+
+	require.Len(t, store.saved, 2)
+	assert.Equal(t, "prod", store.saved[0].InstanceName)
+	assert.Equal(t, "dev", store.saved[1].InstanceName)
+	assert.Equal(t, CreatedBy, store.saved[0].CreatedBy)
+	assert.Equal(t, []time.Time{now.Add(-24 * time.Hour)}, store.deletedBefore)
+}
+
+func TestCollector_DisabledByDefault(t *testing.T) {
+	store := &memoryStore{}
+	collector := NewCollector(store, snapshotQuerier{}, instanceList{{Name: "prod"}}, nil)
+
+	assert.False(t, collector.Enabled())
+	collector.Run(context.Background())
+	assert.Empty(t, store.saved)
+}
@@ -0,0 +1,161 @@
+package statements
+
+import (
+	"sort"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// DefaultDiffLimit is the number of statements listed per ranking of a diff
+const DefaultDiffLimit = 10
+
+// Ref identifies one side of a diff; ID is 0 for a live capture that was not stored
+type Ref struct {
+	ID      int       `json:"id,omitempty"`
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// Change is the activity of one queryid between two snapshots
+type Change struct {
+	QueryID int64  `json:"queryid"`
+	Query   string `json:"query"`
+	// New is set when the statement was not in the earlier snapshot
+	New bool `json:"new"`
+	// Reset is set when the statistics were reset in between or the counters went down
+	// because the statement was evicted; its counters are then taken from the later snapshot
+	Reset    bool    `json:"reset"`
+	Calls    int64   `json:"calls"`
+	ExecTime float64 `json:"exec_time"`
+	// MeanExecTime is the mean time per call between the snapshots
+	MeanExecTime float64 `json:"mean_exec_time"`
+	// PreviousMeanExecTime is the cumulative mean up to the earlier snapshot, nil for new statements
+	PreviousMeanExecTime *float64 `json:"previous_mean_exec_time"`
+	MeanExecTimeChange   *float64 `json:"mean_exec_time_change"`
+	Rows                 int64    `json:"rows"`
+	SharedBlksRead       int64    `json:"shared_blks_read"`
+	TempBlks             int64    `json:"temp_blks"`
+	WalBytes             int64    `json:"wal_bytes"`
+}
+
+// Diff ranks the statements by how much their activity changed between two snapshots
+type Diff struct {
+	From    Ref     `json:"from"`
+	To      Ref     `json:"to"`
+	Seconds float64 `json:"seconds"`
+	// StatsReset tells whether pg_stat_statements was reset between the snapshots. It is
+	// nil before PostgreSQL 14, which does not record resets; only evictions are detected then.
+	StatsReset *bool `json:"stats_reset"`
+	// Active is the number of statements called between the snapshots
+	Active   int     `json:"active"`
+	Calls    int64   `json:"calls"`
+	ExecTime float64 `json:"exec_time"`
+
+	ByCalls       []Change `json:"by_calls"`
+	ByMeanTime    []Change `json:"by_mean_time"`
+	BySharedReads []Change `json:"by_shared_reads"`
+	New           []Change `json:"new"`
+}
+
+// Compare diffs the later snapshot to against the earlier one from and keeps the
+// top limit statements of every ranking
+func Compare(from, to *model.StatementsSnapshot, limit int) *Diff {
+	if limit <= 0 {
+		limit = DefaultDiffLimit
+	}
+
+	diff := &Diff{
+		From:          Ref{ID: from.ID, TakenAt: from.CreatedAt},
+		To:            Ref{ID: to.ID, TakenAt: to.CreatedAt},
+		Seconds:       to.CreatedAt.Sub(from.CreatedAt).Seconds(),
+		StatsReset:    statsResetBetween(from, to),
+		ByCalls:       []Change{},
+		ByMeanTime:    []Change{},
+		BySharedReads: []Change{},
+		New:           []Change{},
+	}
+
+	previous := make(map[int64]model.StatementCounters, len(from.Statements))
+	for _, counters := range from.Statements {
+		previous[counters.QueryID] = counters
+	}
+
+	var changes []Change
+	for _, counters := range to.Statements {
+		before, seen := previous[counters.QueryID]
+		change := compareCounters(before, counters, seen, diff.StatsReset != nil && *diff.StatsReset)
+		if change.Calls == 0 && !change.New {
+			continue
+		}
+
+		diff.Active++
+		diff.Calls += change.Calls
+		diff.ExecTime += change.ExecTime
+		changes = append(changes, change)
+	}
+
+	diff.ByCalls = top(changes, limit, func(c Change) bool { return c.Calls > 0 },
+		func(a, b Change) bool { return a.Calls > b.Calls })
+	diff.ByMeanTime = top(changes, limit, func(c Change) bool { return c.MeanExecTimeChange != nil && *c.MeanExecTimeChange > 0 },
+		func(a, b Change) bool { return *a.MeanExecTimeChange > *b.MeanExecTimeChange })
+	diff.BySharedReads = top(changes, limit, func(c Change) bool { return c.SharedBlksRead > 0 },
+		func(a, b Change) bool { return a.SharedBlksRead > b.SharedBlksRead })
+	diff.New = top(changes, limit, func(c Change) bool { return c.New },
+		func(a, b Change) bool { return a.ExecTime > b.ExecTime })
+
+	return diff
+}
+
+func compareCounters(before, after model.StatementCounters, seen, statsReset bool) Change {
+	change := Change{
+		QueryID: after.QueryID,
+		Query:   after.Query,
+		New:     !seen,
+		Reset:   seen && (statsReset || after.Calls < before.Calls),
+	}
+	base := before
+	if !seen || change.Reset {
+		base = model.StatementCounters{}
+	}
+
+	change.Calls = after.Calls - base.Calls
+	change.ExecTime = after.TotalExecTime - base.TotalExecTime
+	change.Rows = after.Rows - base.Rows
+	change.SharedBlksRead = after.SharedBlksRead - base.SharedBlksRead
+	change.TempBlks = after.TempBlks - base.TempBlks
+	change.WalBytes = after.WalBytes - base.WalBytes
+
+	if change.Calls > 0 {
+		change.MeanExecTime = change.ExecTime / float64(change.Calls)
+		if seen && before.Calls > 0 {
+			previousMean := before.TotalExecTime / float64(before.Calls)
+			meanChange := change.MeanExecTime - previousMean
+			change.PreviousMeanExecTime = &previousMean
+			change.MeanExecTimeChange = &meanChange
+		}
+	}
+
+	return change
+}
+
+func statsResetBetween(from, to *model.StatementsSnapshot) *bool {
+	if to.StatsReset == nil {
+		return nil
+	}
+	reset := from.StatsReset == nil || to.StatsReset.After(*from.StatsReset)
+	return &reset
+}
+
+func top(changes []Change, limit int, keep func(Change) bool, less func(a, b Change) bool) []Change {
+	result := []Change{}
+	for _, change := range changes {
+		if keep(change) {
+			result = append(result, change)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return less(result[i], result[j]) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
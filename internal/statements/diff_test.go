@@ -0,0 +1,148 @@
+package statements
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+)
+
+var taken = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func snapshot(id int, at time.Time, counters ...model.StatementCounters) *model.StatementsSnapshot {
+	return &model.StatementsSnapshot{ID: id, CreatedAt: at, Statements: counters}
+}
+
+func counters(queryID, calls int64, totalTime float64, sharedRead int64) model.StatementCounters {
+	return model.StatementCounters{
+		QueryID:        queryID,
+		Query:          fmt.Sprintf("select %d", queryID),
+		Calls:          calls,
+		TotalExecTime:  totalTime,
+		SharedBlksRead: sharedRead,
+	}
+}
+
+func TestCapture_AggregatesByQueryID(t *testing.T) {
+	reset := taken.Add(-time.Hour)
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetStatements", mock.Anything, pg.StatementsOrderTotalTime, "", "", pg.StatementsAll).Return(&pg.StatementsReport{
+		Statements: []pg.Statement{
+			{QueryID: pg.NewNullInt64(1), Query: "select 1", Calls: 10, TotalExecTime: 100, TempBlksRead: 1, TempBlksWritten: 2},
+			{QueryID: pg.NewNullInt64(2), Query: "select 2", Calls: 1, TotalExecTime: 500},
+			{QueryID: pg.NewNullInt64(1), Query: "select 1", Calls: 5, TotalExecTime: 50, WalBytes: pg.NewNullInt64(8192)},
+			{Query: "<insufficient privilege>", Calls: 99, TotalExecTime: 9999},
+		},
+		Info: pg.StatementsInfo{StatsReset: pg.NewNullTime(reset)},
+	}, nil)
+
+	captured, err := Capture(context.Background(), client)
+	require.NoError(t, err)
+
+	require.Len(t, captured.Statements, 2)
+	assert.Equal(t, 2, captured.Count)
+	assert.Equal(t, reset, *captured.StatsReset)
+	assert.Equal(t, int64(2), captured.Statements[0].QueryID, "ordered by total time")
+	assert.Equal(t, model.StatementCounters{
+		QueryID: 1, Query: "select 1", Calls: 15, TotalExecTime: 150, TempBlks: 3, WalBytes: 8192,
+	}, captured.Statements[1])
+}
+
+func TestCompare_RanksChangedAndNewStatements(t *testing.T) {
+	from := snapshot(1, taken,
+		counters(1, 1000, 1000, 10), // 1 ms per call
+		counters(2, 100, 100, 0),
+		counters(3, 50, 50, 0),
+		counters(4, 10, 10, 0), // evicted before the later snapshot
+	)
+	to := snapshot(2, taken.Add(10*time.Minute),
+		counters(1, 1100, 2000, 5010), // 100 calls at 10 ms, 5000 reads
+		counters(2, 600, 600, 0),      // 500 calls at the same 1 ms
+		counters(3, 50, 50, 0),        // idle
+		counters(5, 20, 400, 0),       // new
+	)
+
+	diff := Compare(from, to, 10)
+
+	assert.Equal(t, Ref{ID: 1, TakenAt: taken}, diff.From)
+	assert.Equal(t, 600.0, diff.Seconds)
+	assert.Nil(t, diff.StatsReset, "snapshots without stats_reset come from PostgreSQL before 14")
+	assert.Equal(t, 3, diff.Active)
+	assert.Equal(t, int64(620), diff.Calls)
+	assert.Equal(t, 1900.0, diff.ExecTime)
+
+	require.Len(t, diff.ByCalls, 3)
+	assert.Equal(t, []int64{2, 1, 5}, queryIDs(diff.ByCalls))
+
+	require.Len(t, diff.ByMeanTime, 1, "only statements that got slower and existed before")
+	assert.Equal(t, int64(1), diff.ByMeanTime[0].QueryID)
+	assert.Equal(t, 10.0, diff.ByMeanTime[0].MeanExecTime)
+	assert.Equal(t, 1.0, *diff.ByMeanTime[0].PreviousMeanExecTime)
+	assert.Equal(t, 9.0, *diff.ByMeanTime[0].MeanExecTimeChange)
+
+	assert.Equal(t, []int64{1}, queryIDs(diff.BySharedReads))
+	assert.Equal(t, int64(5000), diff.BySharedReads[0].SharedBlksRead)
+
+	require.Len(t, diff.New, 1)
+	assert.Equal(t, int64(5), diff.New[0].QueryID)
+	assert.Equal(t, 20.0, diff.New[0].MeanExecTime)
+	assert.Nil(t, diff.New[0].PreviousMeanExecTime)
+}
+
+func TestCompare_CountersThatWentDownStartFromZero(t *testing.T) {
+	from := snapshot(1, taken, counters(1, 1000, 1000, 0))
+	to := snapshot(2, taken.Add(time.Minute), counters(1, 10, 50, 0))
+
+	diff := Compare(from, to, 10)
+
+	require.Len(t, diff.ByCalls, 1)
+	change := diff.ByCalls[0]
+	assert.True(t, change.Reset)
+	assert.False(t, change.New)
+	assert.Equal(t, int64(10), change.Calls)
+	assert.Equal(t, 5.0, change.MeanExecTime)
+	assert.Equal(t, 4.0, *change.MeanExecTimeChange, "compared to the mean before the reset")
+}
+
+func TestCompare_StatsResetTreatsAllStatementsAsReset(t *testing.T) {
+	before := taken.Add(-time.Hour)
+	after := taken.Add(time.Minute)
+	from := snapshot(1, taken, counters(1, 10, 10, 0))
+	from.StatsReset = &before
+	to := snapshot(2, taken.Add(2*time.Minute), counters(1, 30, 30, 0))
+	to.StatsReset = &after
+
+	diff := Compare(from, to, 10)
+
+	require.NotNil(t, diff.StatsReset)
+	assert.True(t, *diff.StatsReset)
+	require.Len(t, diff.ByCalls, 1)
+	assert.True(t, diff.ByCalls[0].Reset)
+	assert.Equal(t, int64(30), diff.ByCalls[0].Calls)
+}
+
+func TestCompare_Limit(t *testing.T) {
+	from := snapshot(1, taken)
+	to := snapshot(2, taken.Add(time.Minute), counters(1, 1, 1, 0), counters(2, 2, 2, 0), counters(3, 3, 3, 0))
+
+	diff := Compare(from, to, 2)
+
+	assert.Equal(t, []int64{3, 2}, queryIDs(diff.ByCalls))
+	assert.Equal(t, []int64{3, 2}, queryIDs(diff.New))
+	assert.Equal(t, 3, diff.Active)
+}
+
+func queryIDs(changes []Change) []int64 {
+	ids := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.QueryID)
+	}
+	return ids
+}
@@ -0,0 +1,61 @@
+// Package statements captures pg_stat_statements snapshots keyed by queryid and
+// diffs two of them to find what changed in the workload between them.
+package statements
+
+import (
+	"context"
+	"sort"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+)
+
+// Capture reads all of pg_stat_statements of an instance into a snapshot that is not
+// stored yet. It is bounded by pg_stat_statements.max; a cutoff would make statements
+// that move back above it look new in a diff.
+func Capture(ctx context.Context, client pg.ClientInterface) (*model.StatementsSnapshot, error) {
+	report, err := client.GetStatements(ctx, pg.StatementsOrderTotalTime, "", "", pg.StatementsAll)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(report), nil
+}
+
+// Aggregate sums the entries of a statements report by queryid. The same queryid
+// appears once per user and database that ran it; entries without one are dropped.
+func Aggregate(report *pg.StatementsReport) *model.StatementsSnapshot {
+	byID := make(map[int64]*model.StatementCounters, len(report.Statements))
+	for _, statement := range report.Statements {
+		if !statement.QueryID.Valid {
+			continue
+		}
+
+		counters, ok := byID[statement.QueryID.Int64]
+		if !ok {
+			counters = &model.StatementCounters{QueryID: statement.QueryID.Int64, Query: statement.Query}
+			byID[statement.QueryID.Int64] = counters
+		}
+		counters.Calls += statement.Calls
+		counters.TotalExecTime += statement.TotalExecTime
+		counters.Rows += statement.Rows
+		counters.SharedBlksHit += statement.SharedBlksHit
+		counters.SharedBlksRead += statement.SharedBlksRead
+		counters.TempBlks += statement.TempBlksRead + statement.TempBlksWritten
+		counters.WalBytes += statement.WalBytes.Int64
+	}
+
+	snapshot := &model.StatementsSnapshot{Statements: make([]model.StatementCounters, 0, len(byID))}
+	if report.Info.StatsReset.Valid {
+		statsReset := report.Info.StatsReset.Time
+		snapshot.StatsReset = &statsReset
+	}
+	for _, counters := range byID {
+		snapshot.Statements = append(snapshot.Statements, *counters)
+	}
+	sort.Slice(snapshot.Statements, func(i, j int) bool {
+		return snapshot.Statements[i].TotalExecTime > snapshot.Statements[j].TotalExecTime
+	})
+	snapshot.Count = len(snapshot.Statements)
+
+	return snapshot
+}
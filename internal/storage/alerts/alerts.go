@@ -0,0 +1,275 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+)
+
+// SaveAlertRule creates the rule or replaces the one with the same name
+func (s *PostgresStorage) SaveAlertRule(ctx context.Context, rule *model.AlertRule) error {
+	params, err := json.Marshal(rule.Params)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert rule params: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_rules
+		(name, description, action, params, expression, for_seconds, severity, selector, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			action = EXCLUDED.action,
+			params = EXCLUDED.params,
+			expression = EXCLUDED.expression,
+			for_seconds = EXCLUDED.for_seconds,
+			severity = EXCLUDED.severity,
+			selector = EXCLUDED.selector,
+			enabled = EXCLUDED.enabled,
+			created_by = EXCLUDED.created_by
+		RETURNING id, created_at, updated_at
+	`
+
+	err = s.db.QueryRowContext(
+		ctx, query,
+		rule.Name,
+		rule.Description,
+		rule.Action,
+		params,
+		rule.Expression,
+		int(rule.For.Seconds()),
+		rule.Severity,
+		rule.Selector,
+		rule.Enabled,
+		rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save alert rule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStorage) ListAlertRules(ctx context.Context) ([]model.AlertRule, error) {
+	query := `
+		SELECT
+			id, name, COALESCE(description, ''), action, params, expression, for_seconds,
+			severity, selector, enabled, COALESCE(created_by, ''), created_at, updated_at
+		FROM alert_rules
+		ORDER BY name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.AlertRule
+	for rows.Next() {
+		var rule model.AlertRule
+		var params []byte
+		var forSeconds int
+		err := rows.Scan(
+			&rule.ID,
+			&rule.Name,
+			&rule.Description,
+			&rule.Action,
+			&params,
+			&rule.Expression,
+			&forSeconds,
+			&rule.Severity,
+			&rule.Selector,
+			&rule.Enabled,
+			&rule.CreatedBy,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		if err := json.Unmarshal(params, &rule.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode alert rule params: %w", err)
+		}
+		rule.For = time.Duration(forSeconds) * time.Second
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SaveAlertState creates or replaces the state of a rule on an instance
+func (s *PostgresStorage) SaveAlertState(ctx context.Context, state *model.AlertState) error {
+	query := `
+		INSERT INTO alert_states
+		(rule_name, instance_name, state, value, active_since, firing_since, resolved_at, notified, evaluated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (rule_name, instance_name) DO UPDATE SET
+			state = EXCLUDED.state,
+			value = EXCLUDED.value,
+			active_since = EXCLUDED.active_since,
+			firing_since = EXCLUDED.firing_since,
+			resolved_at = EXCLUDED.resolved_at,
+			notified = EXCLUDED.notified,
+			evaluated_at = EXCLUDED.evaluated_at
+	`
+
+	_, err := s.db.ExecContext(
+		ctx, query,
+		state.RuleName,
+		state.InstanceName,
+		state.State,
+		state.Value,
+		state.ActiveSince,
+		state.FiringSince,
+		state.ResolvedAt,
+		state.Notified,
+		state.EvaluatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStorage) DeleteAlertState(ctx context.Context, ruleName, instanceName string) error {
+	query := `DELETE FROM alert_states WHERE rule_name = $1 AND instance_name = $2`
+
+	if _, err := s.db.ExecContext(ctx, query, ruleName, instanceName); err != nil {
+		return fmt.Errorf("failed to delete alert state: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStorage) ListAlertStates(ctx context.Context) ([]model.AlertState, error) {
+	query := `
+		SELECT
+			rule_name, instance_name, state, value, active_since, firing_since, resolved_at,
+			notified, evaluated_at
+		FROM alert_states
+		ORDER BY rule_name, instance_name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []model.AlertState
+	for rows.Next() {
+		var state model.AlertState
+		var value sql.NullFloat64
+		var firingSince, resolvedAt sql.NullTime
+		err := rows.Scan(
+			&state.RuleName,
+			&state.InstanceName,
+			&state.State,
+			&value,
+			&state.ActiveSince,
+			&firingSince,
+			&resolvedAt,
+			&state.Notified,
+			&state.EvaluatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert state: %w", err)
+		}
+		if value.Valid {
+			state.Value = &value.Float64
+		}
+		if firingSince.Valid {
+			state.FiringSince = &firingSince.Time
+		}
+		if resolvedAt.Valid {
+			state.ResolvedAt = &resolvedAt.Time
+		}
+		states = append(states, state)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return states, nil
+}
+
+func (s *PostgresStorage) SaveAlertSilence(ctx context.Context, silence *model.AlertSilence) error {
+	query := `
+		INSERT INTO alert_silences
+		(rule_name, instance_name, starts_at, ends_at, comment, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := s.db.QueryRowContext(
+		ctx, query,
+		silence.RuleName,
+		silence.InstanceName,
+		silence.StartsAt,
+		silence.EndsAt,
+		silence.Comment,
+		silence.CreatedBy,
+	).Scan(&silence.ID, &silence.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save alert silence: %w", err)
+	}
+
+	return nil
+}
+
+// ListActiveSilences returns the silences that have not ended at the given time,
+// including the ones that start later
+func (s *PostgresStorage) ListActiveSilences(ctx context.Context, at time.Time) ([]model.AlertSilence, error) {
+	query := `
+		SELECT
+			id, rule_name, instance_name, starts_at, ends_at, COALESCE(comment, ''),
+			COALESCE(created_by, ''), created_at
+		FROM alert_silences
+		WHERE ends_at > $1
+		ORDER BY starts_at, id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []model.AlertSilence
+	for rows.Next() {
+		var silence model.AlertSilence
+		err := rows.Scan(
+			&silence.ID,
+			&silence.RuleName,
+			&silence.InstanceName,
+			&silence.StartsAt,
+			&silence.EndsAt,
+			&silence.Comment,
+			&silence.CreatedBy,
+			&silence.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert silence: %w", err)
+		}
+		silences = append(silences, silence)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return silences, nil
+}
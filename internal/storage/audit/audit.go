@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"psql-mcp-registry/internal/model"
+)
+
+func (s *PostgresStorage) RecordAudit(ctx context.Context, record *model.AuditRecord) error {
+	query := `
+		INSERT INTO action_audit
+		(instance_name, action, username, role, pid, query_start, target_user, target_query, outcome, detail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	err := s.db.QueryRowContext(
+		ctx, query,
+		record.InstanceName,
+		record.Action,
+		record.Username,
+		record.Role,
+		record.PID,
+		record.QueryStart,
+		record.TargetUser,
+		record.TargetQuery,
+		record.Outcome,
+		record.Detail,
+	).Scan(&record.ID, &record.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to record audit: %w", err)
+	}
+
+	return nil
+}
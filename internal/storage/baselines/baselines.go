@@ -0,0 +1,124 @@
+package baselines
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"psql-mcp-registry/internal/model"
+)
+
+var ErrNotFound = errors.New("settings baseline not found")
+
+// SaveSettingsBaseline creates the baseline or replaces the one with the same name
+func (s *PostgresStorage) SaveSettingsBaseline(ctx context.Context, baseline *model.SettingsBaseline) error {
+	settings, err := json.Marshal(baseline.Settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline settings: %w", err)
+	}
+
+	query := `
+		INSERT INTO settings_baselines
+		(name, description, settings, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			settings = EXCLUDED.settings,
+			created_by = EXCLUDED.created_by
+		RETURNING id, created_at, updated_at
+	`
+
+	err = s.db.QueryRowContext(
+		ctx, query,
+		baseline.Name,
+		baseline.Description,
+		settings,
+		baseline.CreatedBy,
+	).Scan(&baseline.ID, &baseline.CreatedAt, &baseline.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save settings baseline: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStorage) GetSettingsBaseline(ctx context.Context, name string) (*model.SettingsBaseline, error) {
+	query := `
+		SELECT
+			id, name, COALESCE(description, ''), settings, COALESCE(created_by, ''),
+			created_at, updated_at
+		FROM settings_baselines
+		WHERE name = $1
+	`
+
+	baseline, err := scanBaseline(s.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings baseline: %w", err)
+	}
+
+	return baseline, nil
+}
+
+func (s *PostgresStorage) ListSettingsBaselines(ctx context.Context) ([]model.SettingsBaseline, error) {
+	query := `
+		SELECT
+			id, name, COALESCE(description, ''), settings, COALESCE(created_by, ''),
+			created_at, updated_at
+		FROM settings_baselines
+		ORDER BY name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings baselines: %w", err)
+	}
+	defer rows.Close()
+
+	var baselines []model.SettingsBaseline
+	for rows.Next() {
+		baseline, err := scanBaseline(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan settings baseline: %w", err)
+		}
+		baselines = append(baselines, *baseline)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return baselines, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBaseline(row rowScanner) (*model.SettingsBaseline, error) {
+	var baseline model.SettingsBaseline
+	var settings []byte
+	err := row.Scan(
+		&baseline.ID,
+		&baseline.Name,
+		&baseline.Description,
+		&settings,
+		&baseline.CreatedBy,
+		&baseline.CreatedAt,
+		&baseline.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(settings, &baseline.Settings); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline settings: %w", err)
+	}
+
+	return &baseline, nil
+}
@@ -0,0 +1,13 @@
+package baselines
+
+import (
+	"database/sql"
+)
+
+type PostgresStorage struct {
+	db *sql.DB
+}
+
+func NewPostgresStorage(db *sql.DB) *PostgresStorage {
+	return &PostgresStorage{db: db}
+}
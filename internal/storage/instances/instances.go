@@ -14,8 +14,8 @@ var ErrNotFound = errors.New("instance not found")
 func (s *PostgresStorage) CreateInstance(ctx context.Context, instance *model.Instance) error {
 	query := `
 		INSERT INTO instance_registry 
-		(name, database_name, description, creator_username, status)
-		VALUES ($1, $2, $3, $4, $5)
+		(name, database_name, description, creator_username, status, labels)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -26,6 +26,7 @@ func (s *PostgresStorage) CreateInstance(ctx context.Context, instance *model.In
 		instance.Description,
 		instance.CreatorUsername,
 		instance.Status,
+		instance.Labels,
 	).Scan(&instance.ID, &instance.CreatedAt, &instance.UpdatedAt)
 
 	if err != nil {
@@ -39,7 +40,7 @@ func (s *PostgresStorage) GetInstanceByName(ctx context.Context, name string) (*
 	query := `
 		SELECT 
 			id, name, database_name, description, creator_username, 
-			status, created_at, updated_at
+			status, labels, created_at, updated_at
 		FROM instance_registry
 		WHERE name = $1
 	`
@@ -52,6 +53,7 @@ func (s *PostgresStorage) GetInstanceByName(ctx context.Context, name string) (*
 		&instance.Description,
 		&instance.CreatorUsername,
 		&instance.Status,
+		&instance.Labels,
 		&instance.CreatedAt,
 		&instance.UpdatedAt,
 	)
@@ -70,7 +72,7 @@ func (s *PostgresStorage) ListInstances(ctx context.Context) ([]model.Instance,
 	query := `
 		SELECT 
 			id, name, database_name, description, creator_username,
-			status, created_at, updated_at
+			status, labels, created_at, updated_at
 		FROM instance_registry
 		WHERE status = 'active'
 		ORDER BY name
@@ -92,6 +94,7 @@ func (s *PostgresStorage) ListInstances(ctx context.Context) ([]model.Instance,
 			&inst.Description,
 			&inst.CreatorUsername,
 			&inst.Status,
+			&inst.Labels,
 			&inst.CreatedAt,
 			&inst.UpdatedAt,
 		)
@@ -0,0 +1,179 @@
+package snapshots
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+)
+
+var ErrNotFound = errors.New("statements snapshot not found")
+
+func (s *PostgresStorage) SaveStatementsSnapshot(ctx context.Context, snapshot *model.StatementsSnapshot) error {
+	statements, err := json.Marshal(snapshot.Statements)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot statements: %w", err)
+	}
+
+	query := `
+		INSERT INTO statements_snapshots
+		(instance_name, stats_reset, statements, comment, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = s.db.QueryRowContext(
+		ctx, query,
+		snapshot.InstanceName,
+		snapshot.StatsReset,
+		statements,
+		snapshot.Comment,
+		snapshot.CreatedBy,
+	).Scan(&snapshot.ID, &snapshot.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save statements snapshot: %w", err)
+	}
+	snapshot.Count = len(snapshot.Statements)
+
+	return nil
+}
+
+func (s *PostgresStorage) GetStatementsSnapshot(ctx context.Context, id int) (*model.StatementsSnapshot, error) {
+	query := `
+		SELECT
+			id, instance_name, stats_reset, statements, COALESCE(comment, ''),
+			COALESCE(created_by, ''), created_at
+		FROM statements_snapshots
+		WHERE id = $1
+	`
+
+	snapshot, err := scanSnapshot(s.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statements snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// FindStatementsSnapshot returns the latest snapshot of the instance taken at or before the given time
+func (s *PostgresStorage) FindStatementsSnapshot(ctx context.Context, instanceName string, at time.Time) (*model.StatementsSnapshot, error) {
+	query := `
+		SELECT
+			id, instance_name, stats_reset, statements, COALESCE(comment, ''),
+			COALESCE(created_by, ''), created_at
+		FROM statements_snapshots
+		WHERE instance_name = $1 AND created_at <= $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanSnapshot(s.db.QueryRowContext(ctx, query, instanceName, at))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find statements snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// ListStatementsSnapshots returns the latest snapshots of the instance, newest first,
+// without their statements
+func (s *PostgresStorage) ListStatementsSnapshots(ctx context.Context, instanceName string, limit int) ([]model.StatementsSnapshot, error) {
+	query := `
+		SELECT
+			id, instance_name, stats_reset, jsonb_array_length(statements), COALESCE(comment, ''),
+			COALESCE(created_by, ''), created_at
+		FROM statements_snapshots
+		WHERE instance_name = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, instanceName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statements snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []model.StatementsSnapshot
+	for rows.Next() {
+		var snapshot model.StatementsSnapshot
+		var statsReset sql.NullTime
+		err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.InstanceName,
+			&statsReset,
+			&snapshot.Count,
+			&snapshot.Comment,
+			&snapshot.CreatedBy,
+			&snapshot.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statements snapshot: %w", err)
+		}
+		if statsReset.Valid {
+			snapshot.StatsReset = &statsReset.Time
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// DeleteStatementsSnapshots removes the snapshots of all instances taken before the given time
+func (s *PostgresStorage) DeleteStatementsSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM statements_snapshots WHERE created_at < $1`
+
+	result, err := s.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete statements snapshots: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSnapshot(row rowScanner) (*model.StatementsSnapshot, error) {
+	var snapshot model.StatementsSnapshot
+	var statsReset sql.NullTime
+	var statements []byte
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.InstanceName,
+		&statsReset,
+		&statements,
+		&snapshot.Comment,
+		&snapshot.CreatedBy,
+		&snapshot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if statsReset.Valid {
+		snapshot.StatsReset = &statsReset.Time
+	}
+	if err := json.Unmarshal(statements, &snapshot.Statements); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot statements: %w", err)
+	}
+	snapshot.Count = len(snapshot.Statements)
+
+	return &snapshot, nil
+}
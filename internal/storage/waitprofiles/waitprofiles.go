@@ -0,0 +1,77 @@
+package waitprofiles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"psql-mcp-registry/internal/waitprofile"
+)
+
+// SaveWaitProfile stores the profile of one history window of an instance
+func (s *PostgresStorage) SaveWaitProfile(ctx context.Context, instanceName string, profile *waitprofile.Profile) error {
+	encoded, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode wait profile: %w", err)
+	}
+
+	query := `
+		INSERT INTO wait_profiles
+		(instance_name, started_at, ended_at, profile)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, instanceName, profile.Start, profile.End, encoded); err != nil {
+		return fmt.Errorf("failed to save wait profile: %w", err)
+	}
+
+	return nil
+}
+
+// ListWaitProfiles returns the profiles of the instance that ended after the given time, oldest first
+func (s *PostgresStorage) ListWaitProfiles(ctx context.Context, instanceName string, since time.Time) ([]waitprofile.Profile, error) {
+	query := `
+		SELECT profile
+		FROM wait_profiles
+		WHERE instance_name = $1 AND ended_at > $2
+		ORDER BY started_at
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, instanceName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wait profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []waitprofile.Profile
+	for rows.Next() {
+		var encoded []byte
+		if err := rows.Scan(&encoded); err != nil {
+			return nil, fmt.Errorf("failed to scan wait profile: %w", err)
+		}
+		var profile waitprofile.Profile
+		if err := json.Unmarshal(encoded, &profile); err != nil {
+			return nil, fmt.Errorf("failed to decode wait profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// DeleteWaitProfiles removes the profiles of all instances that ended before the given time
+func (s *PostgresStorage) DeleteWaitProfiles(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM wait_profiles WHERE ended_at < $1`
+
+	result, err := s.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete wait profiles: %w", err)
+	}
+
+	return result.RowsAffected()
+}
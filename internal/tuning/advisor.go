@@ -0,0 +1,303 @@
+// Package tuning reviews PostgreSQL settings against the server resources and the
+// activity observed in the statistics views.
+package tuning
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/settingsdrift"
+)
+
+// Severities of findings, most urgent first
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+var severityRank = map[string]int{
+	SeverityCritical: 0,
+	SeverityWarning:  1,
+	SeverityInfo:     2,
+}
+
+// Thresholds of the rules
+const (
+	// requestedCheckpointRatio is the share of requested checkpoints above which max_wal_size is too small
+	requestedCheckpointRatio = 0.2
+	// minCheckpoints is the number of checkpoints needed before the ratio is judged
+	minCheckpoints = 10
+	// tempBytesWarning is the cumulative temp file volume above which work_mem is reviewed
+	tempBytesWarning = 1 << 30
+	// connectionHeadroom is the share of max_connections in use above which the limit is near
+	connectionHeadroom = 0.8
+	// oversizedMaxConnections is the max_connections above which idle slots are questioned
+	oversizedMaxConnections = 500
+)
+
+const mb = 1 << 20
+
+// Facts are the observations the advisor works with. Everything except Settings is
+// optional; rules whose facts are missing are skipped.
+type Facts struct {
+	Settings     []pg.SettingInfo
+	Checkpoints  *pg.CheckpointsStats
+	Database     *pg.DatabaseOverview
+	Connections  *pg.ConnectionSummary
+	HostRAMBytes int64
+}
+
+// Finding is one recommendation about a setting
+type Finding struct {
+	Setting   string `json:"setting"`
+	Severity  string `json:"severity"` // critical, warning, info
+	Current   string `json:"current"`  // normalized current value
+	Suggested string `json:"suggested"`
+	Rationale string `json:"rationale"`
+}
+
+// Advise applies the rules to the facts and returns findings ordered by severity
+func Advise(facts Facts) []Finding {
+	a := advisor{facts: facts, settings: make(map[string]pg.SettingInfo, len(facts.Settings))}
+	for _, setting := range facts.Settings {
+		a.settings[setting.Name] = setting
+	}
+
+	a.sharedBuffers()
+	a.effectiveCacheSize()
+	a.workMem()
+	a.checkpoints()
+	a.randomPageCost()
+	a.autovacuum()
+	a.maxConnections()
+
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		return severityRank[a.findings[i].Severity] < severityRank[a.findings[j].Severity]
+	})
+	return a.findings
+}
+
+type advisor struct {
+	facts    Facts
+	settings map[string]pg.SettingInfo
+	findings []Finding
+}
+
+func (a *advisor) add(setting, severity, suggested, rationale string) {
+	a.findings = append(a.findings, Finding{
+		Setting:   setting,
+		Severity:  severity,
+		Current:   a.current(setting),
+		Suggested: suggested,
+		Rationale: rationale,
+	})
+}
+
+func (a *advisor) current(name string) string {
+	setting, exists := a.settings[name]
+	if !exists {
+		return ""
+	}
+	return settingsdrift.Normalize(setting.Setting, setting.Unit.String)
+}
+
+func (a *advisor) bytes(name string) (int64, bool) {
+	setting, exists := a.settings[name]
+	if !exists {
+		return 0, false
+	}
+	return settingsdrift.Bytes(setting.Setting, setting.Unit.String)
+}
+
+func (a *advisor) milliseconds(name string) (float64, bool) {
+	setting, exists := a.settings[name]
+	if !exists {
+		return 0, false
+	}
+	return settingsdrift.Milliseconds(setting.Setting, setting.Unit.String)
+}
+
+func (a *advisor) number(name string) (float64, bool) {
+	setting, exists := a.settings[name]
+	if !exists {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(setting.Setting, 64)
+	return number, err == nil
+}
+
+// ramShare returns a share of host RAM rounded down to whole megabytes
+func (a *advisor) ramShare(share float64) string {
+	bytes := math.Floor(float64(a.facts.HostRAMBytes)*share/mb) * mb
+	return settingsdrift.FormatBytes(bytes)
+}
+
+func (a *advisor) sharedBuffers() {
+	shared, ok := a.bytes("shared_buffers")
+	if !ok {
+		return
+	}
+
+	ram := a.facts.HostRAMBytes
+	switch {
+	case ram == 0 && shared <= 128*mb:
+		a.add("shared_buffers", SeverityWarning, "25% of RAM",
+			"shared_buffers is at the 128MB default, which is sized for the smallest machines; most of the working set is read through the OS cache and copied on every access")
+	case ram > 0 && float64(shared) < 0.15*float64(ram):
+		a.add("shared_buffers", SeverityWarning, a.ramShare(0.25),
+			fmt.Sprintf("shared_buffers is %.0f%% of host RAM; 25%% is the usual starting point for a dedicated server", 100*float64(shared)/float64(ram)))
+	case ram > 0 && float64(shared) > 0.4*float64(ram):
+		a.add("shared_buffers", SeverityWarning, a.ramShare(0.25),
+			fmt.Sprintf("shared_buffers is %.0f%% of host RAM; above 40%% it competes with the OS cache and work_mem and double-buffers the same pages", 100*float64(shared)/float64(ram)))
+	}
+}
+
+func (a *advisor) effectiveCacheSize() {
+	cache, ok := a.bytes("effective_cache_size")
+	if !ok {
+		return
+	}
+	shared, _ := a.bytes("shared_buffers")
+
+	ram := a.facts.HostRAMBytes
+	switch {
+	case ram > 0 && float64(cache) < 0.5*float64(ram):
+		a.add("effective_cache_size", SeverityInfo, a.ramShare(0.75),
+			"effective_cache_size below half of host RAM makes the planner underestimate cached data and avoid index scans; it allocates nothing, so 50-75% of RAM is safe")
+	case ram > 0 && float64(cache) > float64(ram):
+		a.add("effective_cache_size", SeverityWarning, a.ramShare(0.75),
+			"effective_cache_size is larger than host RAM, so the planner assumes data is cached that has to be read from disk")
+	case ram == 0 && cache < 2*shared:
+		a.add("effective_cache_size", SeverityInfo, settingsdrift.FormatBytes(float64(3*shared)),
+			"effective_cache_size should cover shared_buffers plus the OS cache; a value close to shared_buffers makes index scans look too expensive")
+	}
+}
+
+func (a *advisor) workMem() {
+	workMem, ok := a.bytes("work_mem")
+	if !ok {
+		return
+	}
+	maxConnections, _ := a.number("max_connections")
+
+	// Every connection may use work_mem several times, once per sort or hash node
+	ram := a.facts.HostRAMBytes
+	if ram > 0 && maxConnections > 0 && float64(workMem)*maxConnections > float64(ram) {
+		a.add("work_mem", SeverityCritical, a.workMemBudget(maxConnections),
+			fmt.Sprintf("work_mem × max_connections (%s) exceeds host RAM; a burst of sorting queries can exhaust memory and trigger the OOM killer",
+				settingsdrift.FormatBytes(float64(workMem)*maxConnections)))
+		return
+	}
+
+	database := a.facts.Database
+	if database == nil || database.TempFiles == 0 || database.TempBytes < tempBytesWarning {
+		return
+	}
+
+	average := database.TempBytes / database.TempFiles
+	suggested := max(roundUpMB(average), 2*workMem)
+	suggestedText := settingsdrift.FormatBytes(float64(suggested))
+	if ram > 0 && maxConnections > 0 {
+		budget := float64(ram) / 4 / maxConnections
+		if float64(suggested) > budget {
+			suggestedText = a.workMemBudget(maxConnections) + " globally; raise it per role or query for the heavy reports"
+		}
+	}
+
+	a.add("work_mem", SeverityWarning, suggestedText,
+		fmt.Sprintf("%d temp files with %s in total were written since the statistics reset (%s per file on average): sorts and hashes spill to disk because work_mem is too small",
+			database.TempFiles, settingsdrift.FormatBytes(float64(database.TempBytes)), settingsdrift.FormatBytes(float64(average))))
+}
+
+// workMemBudget keeps work_mem × max_connections within a quarter of host RAM
+func (a *advisor) workMemBudget(maxConnections float64) string {
+	budget := math.Max(math.Floor(float64(a.facts.HostRAMBytes)/4/maxConnections/mb), 4) * mb
+	return settingsdrift.FormatBytes(budget)
+}
+
+func (a *advisor) checkpoints() {
+	if stats := a.facts.Checkpoints; stats != nil {
+		total := stats.CheckpointsTimed + stats.CheckpointsReq
+		if total >= minCheckpoints {
+			ratio := float64(stats.CheckpointsReq) / float64(total)
+			if maxWal, ok := a.bytes("max_wal_size"); ok && ratio > requestedCheckpointRatio {
+				factor := int64(2)
+				if ratio > 0.5 {
+					factor = 4
+				}
+				a.add("max_wal_size", SeverityWarning, settingsdrift.FormatBytes(float64(factor*maxWal)),
+					fmt.Sprintf("%.0f%% of checkpoints (%d of %d) were requested because max_wal_size filled up before checkpoint_timeout; frequent checkpoints cause I/O spikes and more full-page writes",
+						100*ratio, stats.CheckpointsReq, total))
+			}
+		}
+	}
+
+	if timeout, ok := a.milliseconds("checkpoint_timeout"); ok && timeout < 10*60*1000 {
+		a.add("checkpoint_timeout", SeverityInfo, "15min",
+			"checkpoint_timeout below 10min writes a full-page image of every page after each checkpoint; 15min spreads checkpoint I/O at the cost of a longer crash recovery. Raise max_wal_size with it")
+	}
+}
+
+func (a *advisor) randomPageCost() {
+	cost, ok := a.number("random_page_cost")
+	if !ok || cost < 4 {
+		return
+	}
+	a.add("random_page_cost", SeverityInfo, "1.1",
+		"random_page_cost of 4 models spinning disks; on SSD or network storage with a warm cache random reads cost about as much as sequential ones, and the default steers the planner away from index scans")
+}
+
+func (a *advisor) autovacuum() {
+	if a.current("autovacuum") == "off" {
+		a.add("autovacuum", SeverityCritical, "on",
+			"with autovacuum off dead tuples accumulate, statistics go stale and nothing prevents transaction ID wraparound")
+		return
+	}
+
+	if scale, ok := a.number("autovacuum_vacuum_scale_factor"); ok && scale >= 0.2 {
+		a.add("autovacuum_vacuum_scale_factor", SeverityInfo, "0.05",
+			"a scale factor of 0.2 waits until a fifth of a table is dead before vacuuming it; on large tables that is millions of rows of bloat. Lower it globally or per table")
+	}
+
+	costLimit, ok := a.number("autovacuum_vacuum_cost_limit")
+	if ok && costLimit == -1 {
+		costLimit, ok = a.number("vacuum_cost_limit")
+	}
+	if ok && costLimit <= 200 {
+		a.add("autovacuum_vacuum_cost_limit", SeverityInfo, "1000",
+			"the default cost limit of 200 throttles autovacuum to a few MB/s, which busy tables outpace on modern storage")
+	}
+
+	if naptime, ok := a.milliseconds("autovacuum_naptime"); ok && naptime > 60*1000 {
+		a.add("autovacuum_naptime", SeverityInfo, "1min",
+			"a long naptime delays vacuum on busy tables between autovacuum rounds")
+	}
+}
+
+func (a *advisor) maxConnections() {
+	connections := a.facts.Connections
+	if connections == nil || connections.MaxConnections == 0 {
+		return
+	}
+
+	used := float64(connections.TotalConnections) / float64(connections.MaxConnections)
+	switch {
+	case used > connectionHeadroom:
+		a.add("max_connections", SeverityWarning, "a connection pooler in front of the server",
+			fmt.Sprintf("%d of %d connections are in use (%.0f%%); new clients will be refused soon. Raising max_connections costs memory per slot, a pooler serves more clients with fewer backends",
+				connections.TotalConnections, connections.MaxConnections, 100*used))
+	case connections.MaxConnections > oversizedMaxConnections && used < 0.1:
+		suggested := max(100, 2*connections.TotalConnections)
+		a.add("max_connections", SeverityInfo, strconv.Itoa(suggested),
+			fmt.Sprintf("only %d of %d connections are in use; every slot reserves shared memory and lock table space, and a high limit invites connection storms",
+				connections.TotalConnections, connections.MaxConnections))
+	}
+}
+
+func roundUpMB(bytes int64) int64 {
+	return (bytes + mb - 1) / mb * mb
+}
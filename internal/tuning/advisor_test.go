@@ -0,0 +1,101 @@
+package tuning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/pg"
+)
+
+func setting(name, value, unit string) pg.SettingInfo {
+	s := pg.SettingInfo{Name: name, Setting: value, Source: "default"}
+	if unit != "" {
+		s.Unit = pg.NewNullString(unit)
+	}
+	return s
+}
+
+// defaults of PostgreSQL 16
+func defaultSettings() []pg.SettingInfo {
+	return []pg.SettingInfo{
+		setting("shared_buffers", "16384", "8kB"),
+		setting("effective_cache_size", "524288", "8kB"),
+		setting("work_mem", "4096", "kB"),
+		setting("max_connections", "100", ""),
+		setting("max_wal_size", "1024", "MB"),
+		setting("checkpoint_timeout", "300", "s"),
+		setting("random_page_cost", "4", ""),
+		setting("autovacuum", "on", ""),
+		setting("autovacuum_vacuum_scale_factor", "0.2", ""),
+		setting("autovacuum_vacuum_cost_limit", "-1", ""),
+		setting("vacuum_cost_limit", "200", ""),
+		setting("autovacuum_naptime", "60", "s"),
+	}
+}
+
+func findingsBySetting(findings []Finding) map[string]Finding {
+	bySetting := make(map[string]Finding, len(findings))
+	for _, finding := range findings {
+		bySetting[finding.Setting] = finding
+	}
+	return bySetting
+}
+
+func TestAdvise_DefaultsWithObservedLoad(t *testing.T) {
+	findings := Advise(Facts{
+		Settings:     defaultSettings(),
+		Checkpoints:  &pg.CheckpointsStats{CheckpointsTimed: 40, CheckpointsReq: 60},
+		Database:     &pg.DatabaseOverview{TempFiles: 100, TempBytes: 3200 << 20},
+		Connections:  &pg.ConnectionSummary{TotalConnections: 90, MaxConnections: 100},
+		HostRAMBytes: 16 << 30,
+	})
+
+	bySetting := findingsBySetting(findings)
+
+	assert.Equal(t, Finding{
+		Setting:   "shared_buffers",
+		Severity:  SeverityWarning,
+		Current:   "128MB",
+		Suggested: "4GB",
+		Rationale: bySetting["shared_buffers"].Rationale,
+	}, bySetting["shared_buffers"])
+
+	assert.Equal(t, "12GB", bySetting["effective_cache_size"].Suggested)
+	assert.Equal(t, "4GB", bySetting["max_wal_size"].Suggested, "60% requested checkpoints quadruple max_wal_size")
+	assert.Equal(t, "32MB", bySetting["work_mem"].Suggested, "average temp file of 32MB")
+	assert.Equal(t, SeverityWarning, bySetting["max_connections"].Severity)
+	assert.Equal(t, "1.1", bySetting["random_page_cost"].Suggested)
+	assert.Equal(t, "1000", bySetting["autovacuum_vacuum_cost_limit"].Suggested)
+	assert.Contains(t, bySetting, "checkpoint_timeout")
+	assert.Contains(t, bySetting, "autovacuum_vacuum_scale_factor")
+	assert.NotContains(t, bySetting, "autovacuum_naptime")
+
+	for i := 1; i < len(findings); i++ {
+		assert.LessOrEqual(t, severityRank[findings[i-1].Severity], severityRank[findings[i].Severity])
+	}
+}
+
+func TestAdvise_WorkMemAboveRAMIsCritical(t *testing.T) {
+	settings := defaultSettings()
+	settings[2] = setting("work_mem", "256MB", "kB")
+	settings[3] = setting("max_connections", "500", "")
+
+	findings := Advise(Facts{Settings: settings, HostRAMBytes: 64 << 30})
+
+	require.NotEmpty(t, findings)
+	assert.Equal(t, "work_mem", findings[0].Setting)
+	assert.Equal(t, SeverityCritical, findings[0].Severity)
+	assert.Equal(t, "32MB", findings[0].Suggested)
+}
+
+func TestAdvise_AutovacuumOffIsCritical(t *testing.T) {
+	settings := defaultSettings()
+	settings[7] = setting("autovacuum", "off", "")
+
+	bySetting := findingsBySetting(Advise(Facts{Settings: settings}))
+
+	assert.Equal(t, SeverityCritical, bySetting["autovacuum"].Severity)
+	assert.NotContains(t, bySetting, "autovacuum_vacuum_scale_factor")
+	assert.Equal(t, "25% of RAM", bySetting["shared_buffers"].Suggested)
+}
@@ -0,0 +1,312 @@
+// Package waitprofile samples the active sessions of pg_stat_activity at a fixed
+// interval and aggregates the samples into a wait event profile: the share of
+// active session time per wait event, per query and per user.
+package waitprofile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"psql-mcp-registry/internal/pg"
+)
+
+// CPU is the wait event of an active session that is not waiting on anything
+// PostgreSQL instruments: it runs on CPU or waits for something invisible to it
+const CPU = "CPU"
+
+// Limits of an on-demand run
+const (
+	DefaultInterval = 100 * time.Millisecond
+	MinInterval     = 10 * time.Millisecond
+	DefaultDuration = 10 * time.Second
+	MaxDuration     = 2 * time.Minute
+	// DefaultLimit is the number of entries kept per breakdown
+	DefaultLimit = 10
+	// maxQueryWaits caps the wait events listed per query
+	maxQueryWaits = 3
+)
+
+// Share is the part of the sampled active session time that went to one key
+type Share struct {
+	Key     string  `json:"key"`
+	Samples int     `json:"samples"`
+	Percent float64 `json:"percent"`
+	// AverageActive is the average number of active sessions with this key
+	AverageActive float64 `json:"average_active"`
+}
+
+// QueryShare is the part of the active session time spent in one query
+type QueryShare struct {
+	QueryID       int64   `json:"queryid,omitempty"`
+	Query         string  `json:"query"`
+	Samples       int     `json:"samples"`
+	Percent       float64 `json:"percent"`
+	AverageActive float64 `json:"average_active"`
+	Waits         []Share `json:"waits"`
+}
+
+// Profile aggregates the samples taken between Start and End. A sample is one
+// active session seen in one poll of pg_stat_activity.
+type Profile struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	IntervalMS int64     `json:"interval_ms"`
+	// Polls is the number of successful polls, FailedPolls the ones that errored
+	Polls       int `json:"polls"`
+	FailedPolls int `json:"failed_polls"`
+	Samples     int `json:"samples"`
+	// AverageActive is the average number of active sessions per poll
+	AverageActive float64      `json:"average_active"`
+	ByWaitEvent   []Share      `json:"by_wait_event"`
+	ByQuery       []QueryShare `json:"by_query"`
+	ByUser        []Share      `json:"by_user"`
+}
+
+// Run polls pg_stat_activity every interval for the given duration and returns the profile
+func Run(ctx context.Context, client pg.ClientInterface, interval, duration time.Duration, limit int) (*Profile, error) {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	if duration == 0 {
+		duration = DefaultDuration
+	}
+	if interval < MinInterval {
+		return nil, fmt.Errorf("interval must be at least %s", MinInterval)
+	}
+	if duration < interval || duration > MaxDuration {
+		return nil, fmt.Errorf("duration must be between the interval and %s", MaxDuration)
+	}
+
+	profiler := NewProfiler(time.Now(), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+
+	var lastErr error
+	for {
+		samples, err := client.GetActivitySample(ctx)
+		if err != nil {
+			lastErr = err
+			profiler.Fail()
+		} else {
+			profiler.Add(samples)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			if profiler.polls == 0 {
+				return nil, lastErr
+			}
+			return profiler.Profile(time.Now(), limit), nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Profiler accumulates polls into a profile. It is not safe for concurrent use.
+type Profiler struct {
+	start    time.Time
+	interval time.Duration
+	polls    int
+	failed   int
+	samples  int
+	waits    map[string]int
+	users    map[string]int
+	queries  map[queryKey]*queryCounts
+}
+
+type queryKey struct {
+	id    int64
+	query string
+}
+
+type queryCounts struct {
+	text    string
+	samples int
+	waits   map[string]int
+}
+
+func NewProfiler(start time.Time, interval time.Duration) *Profiler {
+	return &Profiler{
+		start:    start,
+		interval: interval,
+		waits:    map[string]int{},
+		users:    map[string]int{},
+		queries:  map[queryKey]*queryCounts{},
+	}
+}
+
+// Polls returns the number of successful polls so far
+func (p *Profiler) Polls() int {
+	return p.polls
+}
+
+// Add records one successful poll
+func (p *Profiler) Add(samples []pg.ActivitySample) {
+	p.polls++
+	for _, sample := range samples {
+		wait := WaitEvent(sample)
+		p.samples++
+		p.waits[wait]++
+		p.users[userOf(sample)]++
+
+		key := queryKey{query: sample.Query.String}
+		if sample.QueryID.Valid {
+			// Samples of one queryid differ in their literals; the first text seen is kept
+			key = queryKey{id: sample.QueryID.Int64}
+		}
+		counts := p.query(key, sample.Query.String)
+		counts.samples++
+		counts.waits[wait]++
+	}
+}
+
+// Fail records a poll that errored
+func (p *Profiler) Fail() {
+	p.failed++
+}
+
+// Merge adds the counts of a stored profile, e.g. to combine history windows. Keys
+// cut off by the limit of the stored profile are missing from the breakdowns.
+func (p *Profiler) Merge(profile Profile) {
+	if profile.Start.Before(p.start) || p.start.IsZero() {
+		p.start = profile.Start
+	}
+	if p.interval == 0 {
+		p.interval = time.Duration(profile.IntervalMS) * time.Millisecond
+	}
+	p.polls += profile.Polls
+	p.failed += profile.FailedPolls
+	p.samples += profile.Samples
+	for _, share := range profile.ByWaitEvent {
+		p.waits[share.Key] += share.Samples
+	}
+	for _, share := range profile.ByUser {
+		p.users[share.Key] += share.Samples
+	}
+	for _, query := range profile.ByQuery {
+		key := queryKey{query: query.Query}
+		if query.QueryID != 0 {
+			key = queryKey{id: query.QueryID}
+		}
+		// Only the top waits of a query are stored, so its total comes from Samples
+		counts := p.query(key, query.Query)
+		counts.samples += query.Samples
+		for _, wait := range query.Waits {
+			counts.waits[wait.Key] += wait.Samples
+		}
+	}
+}
+
+func (p *Profiler) query(key queryKey, text string) *queryCounts {
+	counts, ok := p.queries[key]
+	if !ok {
+		counts = &queryCounts{text: text, waits: map[string]int{}}
+		p.queries[key] = counts
+	}
+	return counts
+}
+
+// Profile returns the profile so far with the top limit entries of every breakdown
+func (p *Profiler) Profile(end time.Time, limit int) *Profile {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	profile := &Profile{
+		Start:       p.start,
+		End:         end,
+		IntervalMS:  p.interval.Milliseconds(),
+		Polls:       p.polls,
+		FailedPolls: p.failed,
+		Samples:     p.samples,
+		ByWaitEvent: p.shares(p.waits, limit),
+		ByQuery:     []QueryShare{},
+		ByUser:      p.shares(p.users, limit),
+	}
+	profile.AverageActive = p.averageActive(p.samples)
+
+	for key, counts := range p.queries {
+		profile.ByQuery = append(profile.ByQuery, QueryShare{
+			QueryID:       key.id,
+			Query:         counts.text,
+			Samples:       counts.samples,
+			Percent:       p.percent(counts.samples),
+			AverageActive: p.averageActive(counts.samples),
+			Waits:         p.shares(counts.waits, maxQueryWaits),
+		})
+	}
+	sort.Slice(profile.ByQuery, func(i, j int) bool {
+		a, b := profile.ByQuery[i], profile.ByQuery[j]
+		if a.Samples != b.Samples {
+			return a.Samples > b.Samples
+		}
+		return a.Query < b.Query
+	})
+	if len(profile.ByQuery) > limit {
+		profile.ByQuery = profile.ByQuery[:limit]
+	}
+
+	return profile
+}
+
+func (p *Profiler) shares(counts map[string]int, limit int) []Share {
+	shares := make([]Share, 0, len(counts))
+	for key, samples := range counts {
+		shares = append(shares, Share{
+			Key:           key,
+			Samples:       samples,
+			Percent:       p.percent(samples),
+			AverageActive: p.averageActive(samples),
+		})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Samples != shares[j].Samples {
+			return shares[i].Samples > shares[j].Samples
+		}
+		return shares[i].Key < shares[j].Key
+	})
+	if len(shares) > limit {
+		shares = shares[:limit]
+	}
+	return shares
+}
+
+func (p *Profiler) percent(samples int) float64 {
+	if p.samples == 0 {
+		return 0
+	}
+	return math.Round(1000*float64(samples)/float64(p.samples)) / 10
+}
+
+func (p *Profiler) averageActive(samples int) float64 {
+	if p.polls == 0 {
+		return 0
+	}
+	return math.Round(100*float64(samples)/float64(p.polls)) / 100
+}
+
+// WaitEvent returns the wait event of a sample as type:event, or CPU when it does not wait
+func WaitEvent(sample pg.ActivitySample) string {
+	if !sample.WaitEvent.Valid {
+		return CPU
+	}
+	return sample.WaitEventType.String + ":" + sample.WaitEvent.String
+}
+
+// userOf names the role of a sample; background processes have none and go by their backend type
+func userOf(sample pg.ActivitySample) string {
+	if sample.Username.Valid {
+		return sample.Username.String
+	}
+	if sample.BackendType.Valid {
+		return sample.BackendType.String
+	}
+	return "unknown"
+}
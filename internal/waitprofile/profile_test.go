@@ -0,0 +1,153 @@
+package waitprofile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/pg"
+	pgmocks "psql-mcp-registry/internal/pg/mocks"
+)
+
+var start = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func sample(user string, queryID int64, query, waitType, wait string) pg.ActivitySample {
+	s := pg.ActivitySample{
+		Username: pg.NewNullString(user),
+		Query:    pg.NewNullString(query),
+	}
+	if queryID != 0 {
+		s.QueryID = pg.NewNullInt64(queryID)
+	}
+	if wait != "" {
+		s.WaitEventType = pg.NewNullString(waitType)
+		s.WaitEvent = pg.NewNullString(wait)
+	}
+	return s
+}
+
+func TestProfiler_AggregatesByWaitEventQueryAndUser(t *testing.T) {
+	profiler := NewProfiler(start, 100*time.Millisecond)
+
+	profiler.Add([]pg.ActivitySample{
+		sample("app", 1, "select * from orders where id = 1", "IO", "DataFileRead"),
+		sample("app", 1, "select * from orders where id = 2", "", ""),
+		sample("etl", 2, "insert into events select ...", "LWLock", "WALWrite"),
+	})
+	profiler.Add([]pg.ActivitySample{
+		sample("app", 1, "select * from orders where id = 3", "IO", "DataFileRead"),
+	})
+	profiler.Add(nil)
+	profiler.Add([]pg.ActivitySample{
+		{BackendType: pg.NewNullString("autovacuum worker"), Query: pg.NewNullString("autovacuum: VACUUM public.orders")},
+	})
+	profiler.Fail()
+
+	profile := profiler.Profile(start.Add(time.Second), 10)
+
+	assert.Equal(t, 4, profile.Polls)
+	assert.Equal(t, 1, profile.FailedPolls)
+	assert.Equal(t, 5, profile.Samples)
+	assert.Equal(t, 1.25, profile.AverageActive)
+	assert.Equal(t, int64(100), profile.IntervalMS)
+
+	assert.Equal(t, []Share{
+		{Key: CPU, Samples: 2, Percent: 40, AverageActive: 0.5},
+		{Key: "IO:DataFileRead", Samples: 2, Percent: 40, AverageActive: 0.5},
+		{Key: "LWLock:WALWrite", Samples: 1, Percent: 20, AverageActive: 0.25},
+	}, profile.ByWaitEvent)
+
+	assert.Equal(t, []string{"app", "autovacuum worker", "etl"}, keys(profile.ByUser))
+	assert.Equal(t, 3, profile.ByUser[0].Samples)
+
+	require.Len(t, profile.ByQuery, 3)
+	top := profile.ByQuery[0]
+	assert.Equal(t, int64(1), top.QueryID)
+	assert.Equal(t, "select * from orders where id = 1", top.Query, "first text of the queryid")
+	assert.Equal(t, 3, top.Samples)
+	assert.Equal(t, 60.0, top.Percent)
+	assert.Equal(t, []string{"IO:DataFileRead", CPU}, keys(top.Waits))
+	assert.Equal(t, int64(0), profile.ByQuery[1].QueryID, "samples without queryid are grouped by text")
+}
+
+func TestProfiler_Limit(t *testing.T) {
+	profiler := NewProfiler(start, time.Second)
+	profiler.Add([]pg.ActivitySample{
+		sample("a", 0, "q1", "", ""),
+		sample("a", 0, "q1", "", ""),
+		sample("b", 0, "q2", "Lock", "relation"),
+		sample("c", 0, "q3", "IO", "DataFileRead"),
+	})
+
+	profile := profiler.Profile(start, 2)
+
+	assert.Len(t, profile.ByWaitEvent, 2)
+	assert.Len(t, profile.ByUser, 2)
+	assert.Equal(t, []string{"q1", "q2"}, []string{profile.ByQuery[0].Query, profile.ByQuery[1].Query})
+	assert.Equal(t, 4, profile.Samples, "totals are not limited")
+}
+
+func TestProfiler_Merge(t *testing.T) {
+	first := NewProfiler(start, time.Second)
+	first.Add([]pg.ActivitySample{sample("app", 1, "q1", "IO", "DataFileRead"), sample("app", 1, "q1", "", "")})
+	second := NewProfiler(start.Add(time.Minute), time.Second)
+	second.Add([]pg.ActivitySample{sample("app", 1, "q1", "IO", "DataFileRead")})
+	second.Add([]pg.ActivitySample{sample("etl", 2, "q2", "Lock", "tuple")})
+
+	merged := NewProfiler(time.Time{}, 0)
+	merged.Merge(*second.Profile(start.Add(2*time.Minute), 10))
+	merged.Merge(*first.Profile(start.Add(time.Minute), 10))
+	profile := merged.Profile(start.Add(2*time.Minute), 10)
+
+	assert.Equal(t, start, profile.Start)
+	assert.Equal(t, int64(1000), profile.IntervalMS)
+	assert.Equal(t, 3, profile.Polls)
+	assert.Equal(t, 4, profile.Samples)
+	assert.Equal(t, "IO:DataFileRead", profile.ByWaitEvent[0].Key)
+	assert.Equal(t, 2, profile.ByWaitEvent[0].Samples)
+	assert.Equal(t, 3, profile.ByQuery[0].Samples)
+	assert.Equal(t, 75.0, profile.ByQuery[0].Percent)
+}
+
+func TestRun_PollsUntilDuration(t *testing.T) {
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetActivitySample", mock.Anything).Return([]pg.ActivitySample{sample("app", 1, "q1", "", "")}, nil)
+
+	profile, err := Run(context.Background(), client, 10*time.Millisecond, 50*time.Millisecond, 0)
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, profile.Polls, 2)
+	assert.Equal(t, profile.Polls, profile.Samples)
+	assert.Equal(t, 1.0, profile.AverageActive)
+}
+
+func TestRun_FailsWhenNoPollSucceeds(t *testing.T) {
+	client := pgmocks.NewClientInterface(t)
+	client.On("GetActivitySample", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	_, err := Run(context.Background(), client, 10*time.Millisecond, 30*time.Millisecond, 0)
+
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestRun_ValidatesLimits(t *testing.T) {
+	client := pgmocks.NewClientInterface(t)
+
+	_, err := Run(context.Background(), client, time.Millisecond, time.Second, 0)
+	assert.Error(t, err)
+
+	_, err = Run(context.Background(), client, time.Second, time.Hour, 0)
+	assert.Error(t, err)
+}
+
+func keys(shares []Share) []string {
+	result := make([]string, 0, len(shares))
+	for _, share := range shares {
+		result = append(result, share.Key)
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+// Package recorder samples the active sessions of every registered instance
+// continuously and stores one wait profile per instance and window in the registry.
+package recorder
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/waitprofile"
+)
+
+// StoredLimit is the number of entries kept per breakdown of a stored window;
+// keys beyond it are missing when windows are merged into a history profile
+const StoredLimit = 100
+
+// Config holds the sampling schedule and retention of the recorder
+type Config struct {
+	// Interval is the time between two polls of pg_stat_activity; zero disables the recorder
+	Interval time.Duration
+	// Window is the time covered by one stored profile
+	Window time.Duration
+	// Retention is how long profiles are kept; zero keeps them forever
+	Retention time.Duration
+}
+
+// DefaultConfig returns the default recorder configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Window:    time.Minute,
+		Retention: 24 * time.Hour,
+	}
+}
+
+// LoadConfigFromEnv loads the recorder configuration from environment variables
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	if interval := os.Getenv("WAIT_PROFILE_HISTORY_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d >= 0 {
+			cfg.Interval = d
+		}
+	}
+
+	if window := os.Getenv("WAIT_PROFILE_HISTORY_WINDOW"); window != "" {
+		if d, err := time.ParseDuration(window); err == nil && d > 0 {
+			cfg.Window = d
+		}
+	}
+
+	if retention := os.Getenv("WAIT_PROFILE_HISTORY_RETENTION"); retention != "" {
+		if d, err := time.ParseDuration(retention); err == nil && d >= 0 {
+			cfg.Retention = d
+		}
+	}
+
+	return cfg
+}
+
+// Store keeps the profiles in the registry
+type Store interface {
+	SaveWaitProfile(ctx context.Context, instanceName string, profile *waitprofile.Profile) error
+	DeleteWaitProfiles(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Recorder polls all instances and stores a profile per instance when a window ends
+type Recorder struct {
+	store     Store
+	querier   router.Querier
+	instances router.InstanceLister
+	config    *Config
+	now       func() time.Time
+
+	// State of the current window; the instance list is refreshed when a window starts
+	windowStart time.Time
+	current     []model.Instance
+	profilers   map[string]*waitprofile.Profiler
+}
+
+func NewRecorder(store Store, querier router.Querier, instances router.InstanceLister, config *Config) *Recorder {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Recorder{
+		store:     store,
+		querier:   querier,
+		instances: instances,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Enabled reports whether the recorder samples continuously
+func (r *Recorder) Enabled() bool {
+	return r.config.Interval > 0
+}
+
+// Run polls every interval until the context is done; it returns at once when disabled
+func (r *Recorder) Run(ctx context.Context) {
+	if !r.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll samples every instance once and stores the profiles when the window is over
+func (r *Recorder) Poll(ctx context.Context) {
+	if r.profilers == nil {
+		instances, err := r.instances.ListInstances(ctx)
+		if err != nil {
+			log.Printf("Wait profile history: failed to list instances: %v", err)
+			return
+		}
+		r.windowStart = r.now()
+		r.current = instances
+		r.profilers = make(map[string]*waitprofile.Profiler, len(instances))
+		for _, instance := range instances {
+			r.profilers[instance.Name] = waitprofile.NewProfiler(r.windowStart, r.config.Interval)
+		}
+	}
+
+	responses := r.querier.RouteAll(ctx, router.QueryRequest{Action: model.ActionNameActivitySample}, r.current)
+	for i, response := range responses {
+		profiler := r.profilers[r.current[i].Name]
+		samples, ok := response.Data.([]pg.ActivitySample)
+		if !response.Success || !ok {
+			profiler.Fail()
+			continue
+		}
+		profiler.Add(samples)
+	}
+
+	if now := r.now(); now.Sub(r.windowStart) >= r.config.Window {
+		r.flush(ctx, now)
+	}
+}
+
+func (r *Recorder) flush(ctx context.Context, end time.Time) {
+	for _, instance := range r.current {
+		profiler := r.profilers[instance.Name]
+		if profiler.Polls() == 0 {
+			// Nothing was sampled, e.g. the instance was down for the whole window
+			continue
+		}
+		if err := r.store.SaveWaitProfile(ctx, instance.Name, profiler.Profile(end, StoredLimit)); err != nil {
+			log.Printf("Wait profile history of %s: %v", instance.Name, err)
+		}
+	}
+	r.profilers = nil
+
+	if r.config.Retention > 0 {
+		if _, err := r.store.DeleteWaitProfiles(ctx, end.Add(-r.config.Retention)); err != nil {
+			log.Printf("Wait profile history: %v", err)
+		}
+	}
+}
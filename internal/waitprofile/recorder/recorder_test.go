@@ -0,0 +1,104 @@
+package recorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"psql-mcp-registry/internal/model"
+	"psql-mcp-registry/internal/pg"
+	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/waitprofile"
+)
+
+type memoryStore struct {
+	saved         map[string][]waitprofile.Profile
+	deletedBefore []time.Time
+}
+
+func (s *memoryStore) SaveWaitProfile(ctx context.Context, instanceName string, profile *waitprofile.Profile) error {
+	if s.saved == nil {
+		s.saved = map[string][]waitprofile.Profile{}
+	}
+	s.saved[instanceName] = append(s.saved[instanceName], *profile)
+	return nil
+}
+
+func (s *memoryStore) DeleteWaitProfiles(ctx context.Context, before time.Time) (int64, error) {
+	s.deletedBefore = append(s.deletedBefore, before)
+	return 0, nil
+}
+
+// activityQuerier answers activity_sample with one active session waiting on IO,
+// except for the instances marked down
+type activityQuerier struct {
+	down map[string]bool
+}
+
+func (q activityQuerier) RouteAll(ctx context.Context, req router.QueryRequest, instances []model.Instance) []*router.QueryResponse {
+	responses := make([]*router.QueryResponse, len(instances))
+	for i, instance := range instances {
+		if q.down[instance.Name] {
+			responses[i] = &router.QueryResponse{Instance: instance.Name, Action: req.Action, Error: "connection refused"}
+			continue
+		}
+		responses[i] = &router.QueryResponse{
+			Instance: instance.Name,
+			Action:   req.Action,
+			Success:  true,
+			Data: []pg.ActivitySample{{
+				Username:      pg.NewNullString("app"),
+				WaitEventType: pg.NewNullString("IO"),
+				WaitEvent:     pg.NewNullString("DataFileRead"),
+				Query:         pg.NewNullString("select 1"),
+			}},
+		}
+	}
+	return responses
+}
+
+type instanceList []model.Instance
+
+func (l instanceList) ListInstances(ctx context.Context) ([]model.Instance, error) {
+	return l, nil
+}
+
+func TestRecorder_StoresOneProfilePerWindow(t *testing.T) {
+	store := &memoryStore{}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder(store, activityQuerier{down: map[string]bool{"standby": true}},
+		instanceList{{Name: "prod"}, {Name: "standby"}},
+		&Config{Interval: 10 * time.Second, Window: time.Minute, Retention: time.Hour})
+	recorder.now = func() time.Time { return now }
+
+	for i := 0; i < 6; i++ {
+		recorder.Poll(context.Background())
+		now = now.Add(10 * time.Second)
+	}
+	assert.Empty(t, store.saved, "the window is not over yet")
+
+	recorder.Poll(context.Background())
+
+	require.Len(t, store.saved["prod"], 1)
+	assert.Empty(t, store.saved["standby"], "an instance that was never sampled stores nothing")
+	profile := store.saved["prod"][0]
+	assert.Equal(t, 7, profile.Polls)
+	assert.Equal(t, 7, profile.Samples)
+	assert.Equal(t, now.Add(-time.Minute), profile.Start)
+	assert.Equal(t, now, profile.End)
+	assert.Equal(t, "IO:DataFileRead", profile.ByWaitEvent[0].Key)
+	assert.Equal(t, []time.Time{now.Add(-time.Hour)}, store.deletedBefore)
+
+	now = now.Add(10 * time.Second)
+	recorder.Poll(context.Background())
+	assert.Len(t, store.saved["prod"], 1, "the next window has started")
+}
+
+func TestRecorder_DisabledByDefault(t *testing.T) {
+	recorder := NewRecorder(&memoryStore{}, activityQuerier{}, instanceList{}, nil)
+
+	assert.False(t, recorder.Enabled())
+	recorder.Run(context.Background())
+}
@@ -5,8 +5,11 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"psql-mcp-registry/internal/access"
+	"psql-mcp-registry/internal/alerting"
 	"psql-mcp-registry/internal/api"
 	"psql-mcp-registry/internal/factory"
 	"psql-mcp-registry/internal/instance_manager"
@@ -14,7 +17,14 @@ import (
 	"psql-mcp-registry/internal/pg"
 	"psql-mcp-registry/internal/registry"
 	"psql-mcp-registry/internal/router"
+	"psql-mcp-registry/internal/statements/collector"
+	"psql-mcp-registry/internal/storage/alerts"
+	"psql-mcp-registry/internal/storage/audit"
+	"psql-mcp-registry/internal/storage/baselines"
 	"psql-mcp-registry/internal/storage/instances"
+	"psql-mcp-registry/internal/storage/snapshots"
+	"psql-mcp-registry/internal/storage/waitprofiles"
+	"psql-mcp-registry/internal/waitprofile/recorder"
 	"psql-mcp-registry/migrations"
 )
 
@@ -69,20 +79,95 @@ func main() {
 	log.Println("Initialized instance manager")
 
 	// Create router
-	queryRouter := router.New(instanceRegistry)
+	queryRouter := router.NewWithConfig(instanceRegistry, router.LoadConfigFromEnv())
 	log.Println("Initialized query router")
 
+	// Create audit storage for state-changing MCP actions
+	auditStorage := audit.NewPostgresStorage(client.DB())
+
+	// Create storage for settings baselines
+	baselineStorage := baselines.NewPostgresStorage(client.DB())
+
+	// Create storage for alert rules, alert states and silences
+	alertStorage := alerts.NewPostgresStorage(client.DB())
+
+	// Create storage for pg_stat_statements snapshots
+	snapshotStorage := snapshots.NewPostgresStorage(client.DB())
+
+	// Create storage for recorded wait profiles
+	waitProfileStorage := waitprofiles.NewPostgresStorage(client.DB())
+
+	// Load MCP access control (bearer tokens and roles)
+	accessConfig := access.LoadConfigFromEnv()
+	log.Printf("Loaded %d MCP auth tokens, default role %s", len(accessConfig.Tokens), accessConfig.DefaultRole)
+
 	// Create MCP server
-	mcpServer := mcpserver.NewMCPServer(queryRouter, instanceManager)
+	mcpServer := mcpserver.NewMCPServer(queryRouter, instanceManager, accessConfig, mcpserver.Stores{
+		Audit:        auditStorage,
+		Baselines:    baselineStorage,
+		Alerts:       alertStorage,
+		Snapshots:    snapshotStorage,
+		WaitProfiles: waitProfileStorage,
+	})
 	log.Println("Initialized MCP server")
 
+	// Publish registered instances as MCP resources and keep them in sync with registrations
+	if err := mcpServer.SyncInstanceResources(ctx); err != nil {
+		log.Printf("Failed to publish instance resources: %v", err)
+	}
+	instanceManager.OnRegister(mcpServer.AddInstanceResources)
+
+	// Read MCP transport from environment variable (default: http)
+	mcpTransport := os.Getenv("MCP_TRANSPORT")
+	if mcpTransport == "" {
+		mcpTransport = mcpserver.TransportHTTP
+	}
+	if mcpTransport != mcpserver.TransportHTTP && mcpTransport != mcpserver.TransportStdio {
+		log.Fatalf("Unsupported MCP_TRANSPORT %q (expected %q or %q)", mcpTransport, mcpserver.TransportHTTP, mcpserver.TransportStdio)
+	}
+
+	// A stdio server belongs to a single desktop client, so by default it runs without
+	// the HTTP API and the background jobs; MCP_STDIO_SERVICES=true turns them back on
+	runServices := true
+	if mcpTransport == mcpserver.TransportStdio {
+		runServices, _ = strconv.ParseBool(os.Getenv("MCP_STDIO_SERVICES"))
+	}
+
+	if runServices {
+		// Evaluate alert rules on a schedule
+		alertConfig := alerting.LoadConfigFromEnv()
+		alertEngine := alerting.NewEngine(alertStorage, queryRouter, instanceManager, alertConfig)
+		go alertEngine.Run(ctx)
+		log.Printf("Started alerting engine, evaluating rules every %s", alertConfig.Interval)
+
+		// Snapshot pg_stat_statements of all instances on a schedule, if enabled
+		collectorConfig := collector.LoadConfigFromEnv()
+		statementsCollector := collector.NewCollector(snapshotStorage, queryRouter, instanceManager, collectorConfig)
+		if statementsCollector.Enabled() {
+			go statementsCollector.Run(ctx)
+			log.Printf("Started statements snapshot collector, every %s with %s retention",
+				collectorConfig.Interval, collectorConfig.Retention)
+		}
+
+		// Sample the active sessions of all instances into wait profile windows, if enabled
+		recorderConfig := recorder.LoadConfigFromEnv()
+		waitProfileRecorder := recorder.NewRecorder(waitProfileStorage, queryRouter, instanceManager, recorderConfig)
+		if waitProfileRecorder.Enabled() {
+			go waitProfileRecorder.Run(ctx)
+			log.Printf("Started wait profile recorder, polling every %s in %s windows with %s retention",
+				recorderConfig.Interval, recorderConfig.Window, recorderConfig.Retention)
+		}
+	} else {
+		log.Println("HTTP API server and background jobs are disabled for the stdio transport, set MCP_STDIO_SERVICES=true to run them")
+	}
+
 	// Read HTTP API port from environment variable (default: 8080)
 	httpPort := os.Getenv("HTTP_API_PORT")
 	if httpPort == "" {
 		httpPort = "8080"
 	}
 
-	// Read MCP SSE port from environment variable (default: 3000)
+	// Read MCP HTTP port from environment variable (default: 3000)
 	mcpPort := os.Getenv("MCP_PORT")
 	if mcpPort == "" {
 		mcpPort = "3000"
@@ -90,8 +175,14 @@ func main() {
 
 	// Create HTTP API server
 	apiServer := api.NewAPIServer(instanceManager, httpPort)
-	log.Printf("Initialized HTTP API server on port %s", httpPort)
-	log.Printf("MCP server will use SSE transport on port %s", mcpPort)
+	if runServices {
+		log.Printf("Initialized HTTP API server on port %s", httpPort)
+	}
+	if mcpTransport == mcpserver.TransportStdio {
+		log.Println("MCP server will use stdio transport")
+	} else {
+		log.Printf("MCP server will use streamable HTTP and SSE transports on port %s", mcpPort)
+	}
 
 	// Log successful initialization
 	log.Println("Application initialized successfully")
@@ -100,24 +191,40 @@ func main() {
 	// Run both servers in goroutines
 	errChan := make(chan error, 2)
 
-	// Run MCP server with SSE transport
+	// Run MCP server with the configured transport
+	mcpDone := make(chan struct{})
 	go func() {
-		log.Printf("Starting MCP server with SSE transport on :%s", mcpPort)
-		log.Printf("SSE endpoint: http://localhost:%s/sse", mcpPort)
-		log.Printf("Test with: npx @modelcontextprotocol/inspector http://localhost:%s/sse", mcpPort)
-		if err := mcpServer.RunWithSSE(ctx, mcpPort); err != nil {
-			errChan <- err
+		if mcpTransport == mcpserver.TransportStdio {
+			// Logs go to stderr, stdout is reserved for the protocol
+			log.Println("Starting MCP server with stdio transport")
+			if err := mcpServer.Run(ctx); err != nil {
+				errChan <- err
+				return
+			}
+			// The client closed stdin, nothing left to serve
+			close(mcpDone)
+			return
 		}
-	}()
 
-	// Run HTTP API server
-	go func() {
-		log.Printf("Starting HTTP API server on :%s", httpPort)
-		if err := apiServer.Run(ctx); err != nil {
+		log.Printf("Starting MCP server with HTTP transports on :%s", mcpPort)
+		log.Printf("Streamable HTTP endpoint: http://localhost:%s%s", mcpPort, mcpserver.StreamableHTTPPath)
+		log.Printf("SSE endpoint: http://localhost:%s%s", mcpPort, mcpserver.SSEPath)
+		log.Printf("Test with: npx @modelcontextprotocol/inspector http://localhost:%s%s", mcpPort, mcpserver.StreamableHTTPPath)
+		if err := mcpServer.RunWithHTTP(ctx, mcpPort); err != nil {
 			errChan <- err
 		}
 	}()
 
+	// Run HTTP API server
+	if runServices {
+		go func() {
+			log.Printf("Starting HTTP API server on :%s", httpPort)
+			if err := apiServer.Run(ctx); err != nil {
+				errChan <- err
+			}
+		}()
+	}
+
 	// Wait for interrupt signal or error
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -129,6 +236,9 @@ func main() {
 	case err := <-errChan:
 		log.Printf("Server error: %v", err)
 		cancel()
+	case <-mcpDone:
+		log.Println("MCP client disconnected, shutting down...")
+		cancel()
 	}
 
 	log.Println("Shutdown complete")
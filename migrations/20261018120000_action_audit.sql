@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS action_audit (
+    id SERIAL PRIMARY KEY,
+    instance_name VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    pid INTEGER,
+    query_start TIMESTAMP WITH TIME ZONE,
+    target_user VARCHAR(255),
+    target_query TEXT,
+    outcome VARCHAR(50) NOT NULL CHECK (outcome IN ('refused', 'confirmation_required', 'executed', 'failed')),
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS action_audit_instance_created_at_idx ON action_audit (instance_name, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS action_audit;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    action VARCHAR(100) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    expression TEXT NOT NULL,
+    for_seconds INTEGER NOT NULL DEFAULT 0 CHECK (for_seconds >= 0),
+    severity VARCHAR(50) NOT NULL CHECK (severity IN ('critical', 'warning', 'info')),
+    selector TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_alert_rules_updated_at
+    BEFORE UPDATE ON alert_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS alert_states (
+    rule_name VARCHAR(255) NOT NULL REFERENCES alert_rules (name) ON DELETE CASCADE ON UPDATE CASCADE,
+    instance_name VARCHAR(255) NOT NULL,
+    state VARCHAR(50) NOT NULL CHECK (state IN ('pending', 'firing', 'resolved')),
+    value DOUBLE PRECISION,
+    active_since TIMESTAMP WITH TIME ZONE NOT NULL,
+    firing_since TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+    evaluated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (rule_name, instance_name)
+);
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id SERIAL PRIMARY KEY,
+    rule_name VARCHAR(255) NOT NULL DEFAULT '',
+    instance_name VARCHAR(255) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL CHECK (ends_at > starts_at),
+    comment TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS alert_silences_ends_at_idx ON alert_silences (ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS alert_silences;
+DROP TABLE IF EXISTS alert_states;
+DROP TABLE IF EXISTS alert_rules;
+-- +goose StatementEnd
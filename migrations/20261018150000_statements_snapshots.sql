@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS statements_snapshots (
+    id SERIAL PRIMARY KEY,
+    instance_name VARCHAR(255) NOT NULL,
+    stats_reset TIMESTAMP WITH TIME ZONE,
+    statements JSONB NOT NULL DEFAULT '[]',
+    comment TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS statements_snapshots_instance_created_at_idx
+    ON statements_snapshots (instance_name, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS statements_snapshots;
+-- +goose StatementEnd